@@ -120,6 +120,9 @@ func newApp() *urfave.Command {
 			deleteCmd,
 			scoreCmd,
 			substituteCmd,
+			botsCmd,
+			entityCmd,
+			cohortCmd,
 			queryCmd,
 			serverCmd,
 			syncCmd,
@@ -21,12 +21,86 @@ function applyChartDefaults() {
     Chart.defaults.transitions.resize = { animation: { duration: 0 } };
 }
 
+// anomalyCharts maps the charts annotated with anomalies to their
+// metrics. Charts with a fixed granularity use the anomalies of that
+// granularity; daily charts mark the first day of each monthly anomaly.
+var anomalyCharts = {
+    'time-series-chart': { g: 'month', metrics: ['pr', 'pr_review', 'pr_review_comment', 'issue', 'issue_comment', 'fork'] },
+    'stars-trend-chart': { g: 'month', daily: true, metrics: ['stars'] },
+    'time-to-merge-chart': { metrics: ['time_to_merge'] },
+    'review-latency-chart': { metrics: ['review_latency'] }
+};
+
+// anomalyIndex holds the loaded anomalies by granularity, metric and period.
+var anomalyIndex = {};
+
+var anomalyPlugin = {
+    id: 'anomalies',
+    afterDatasetsDraw: function (chart) {
+        var cfg = anomalyCharts[chart.canvas.id];
+        if (!cfg) return;
+        var byMetric = anomalyIndex[cfg.g || $("#granularity-select").val() || 'month'];
+        if (!byMetric) return;
+        var labels = chart.data.labels || [];
+        var area = chart.chartArea;
+        var ctx = chart.ctx;
+        $.each(labels, function (i, label) {
+            var period = cfg.daily ? String(label).substring(0, 7) : String(label);
+            if (cfg.daily && i > 0 && String(labels[i - 1]).substring(0, 7) === period) return;
+            var found = [];
+            $.each(cfg.metrics, function (j, m) {
+                if (byMetric[m] && byMetric[m][period]) found.push(byMetric[m][period]);
+            });
+            if (found.length === 0) return;
+            var x = chart.scales.x.getPixelForValue(i);
+            var up = found[0].direction === 'up';
+            ctx.save();
+            ctx.strokeStyle = '#d1242f';
+            ctx.fillStyle = '#d1242f';
+            ctx.setLineDash([4, 3]);
+            ctx.beginPath();
+            ctx.moveTo(x, area.top + 12);
+            ctx.lineTo(x, area.bottom);
+            ctx.stroke();
+            ctx.setLineDash([]);
+            ctx.font = '11px sans-serif';
+            ctx.textAlign = 'center';
+            ctx.fillText((up ? '\u25B2 ' : '\u25BC ') + found.map(function (a) {
+                return a.metric.replace(/_/g, ' ');
+            }).join(', '), x, area.top + 9);
+            ctx.restore();
+        });
+    }
+};
+
+// loadAnomalies loads the anomalies of the selection, at the selected
+// granularity and monthly for the monthly charts, and redraws the charts
+// they annotate.
+function loadAnomalies(months, org, repo) {
+    var g = $("#granularity-select").val() || 'month';
+    anomalyIndex = {};
+    $.each(g === 'month' ? ['month'] : [g, 'month'], function (i, gran) {
+        $.get('/data/insights/anomalies?m=' + months + '&o=' + org + '&r=' + repo + '&g=' + gran, function (data) {
+            var byMetric = {};
+            $.each(data, function (j, a) {
+                byMetric[a.metric] = byMetric[a.metric] || {};
+                byMetric[a.metric][a.period] = a;
+            });
+            anomalyIndex[gran] = byMetric;
+            $.each(Chart.instances, function (k, c) {
+                if (anomalyCharts[c.canvas.id]) c.draw();
+            });
+        });
+    });
+}
+
 function initTheme() {
     const saved = localStorage.getItem('theme');
     if (saved) {
         document.documentElement.setAttribute('data-theme', saved);
     }
     applyChartDefaults();
+    Chart.register(anomalyPlugin);
 }
 
 function toggleTheme() {
@@ -75,6 +149,7 @@ const searchCriteria = {
     "repo": null,
     "user": null,
     "entity": null,
+    "cohort": null,
     "page": 1,
     "page_size": 10,
     init: function () {
@@ -124,9 +199,32 @@ let starsTrendChart;
 let forksTrendChart;
 let changeFailureRateChart;
 let reviewLatencyChart;
+let reviewDepthChart;
+let approverConcentrationChart;
+let timeToTriageChart;
+let reopenRateChart;
+let labelDwellChart;
+let reviewRequestLatencyChart;
+let issueResolutionChart;
+let issueFixLeadTimeChart;
+let prLifecycleChart;
+let backlogAgingChart;
+let backlogHistoryChart;
+let activityHeatmapChart;
+let offHoursChart;
+let mostWantedChart;
+let discussionsChart;
+let topAnswerersChart;
+let externalShareChart;
+let milestonesChart;
+let milestoneBurnupChart;
+let projectStatusChart;
 let prSizeChart;
 let contributorFunnelChart;
 let contributorMomentumChart;
+let factorTrendChart;
+let entityDiversityChart;
+let contributorLifecycleChart;
 let contributorProfileChart;
 let containerActivityChart;
 let healthActivitySparkline;
@@ -222,6 +320,13 @@ $(function () {
         initUnifiedSearch();
         initSearchFilters();
         initPeriodSelector();
+        initCohortSelector();
+        initGranularitySelector();
+        initFactorWindowSelector();
+        initDiversitySelector();
+        initCohortRetentionSelector();
+        initDiscussionSelector();
+        initDurationSelector();
         initTabs();
         var params = new URLSearchParams(window.location.search);
         var paramOrg = params.get("o") || "";
@@ -255,7 +360,7 @@ $(function () {
 
 function initTabs() {
     var hash = window.location.hash.replace('#', '');
-    var validTabs = ['health', 'activity', 'velocity', 'quality', 'community', 'events'];
+    var validTabs = ['health', 'activity', 'velocity', 'quality', 'community', 'planning', 'events'];
     if (validTabs.indexOf(hash) !== -1) {
         activeTab = hash;
     }
@@ -310,14 +415,14 @@ function activateTab(tab) {
     var org = searchCriteria.org || "";
     var repo = searchCriteria.repo || "";
     var entity = searchCriteria.entity || "";
-    var key = tab + "|" + months + "|" + org + "|" + repo + "|" + entity;
+    var key = tab + "|" + months + "|" + org + "|" + repo + "|" + entity + "|" + cohortParam() + discussionParam() + granularityParam();
     if (key === lastTabKey) return;
     lastTabKey = key;
     loadTabCharts(tab, months, org, repo, entity);
 }
 
 function loadSummaryBanner(months, org, repo, entity) {
-    $.get('/data/insights/summary?m=' + months + '&o=' + org + '&r=' + repo + '&e=' + entity, function (data) {
+    $.get('/data/insights/summary?m=' + months + '&o=' + org + '&r=' + repo + '&e=' + entity + cohortParam(), function (data) {
         $("#banner-orgs").text(data.orgs.toLocaleString());
         $("#banner-repos").text(data.repos.toLocaleString());
         $("#banner-events").text(data.events.toLocaleString());
@@ -327,11 +432,13 @@ function loadSummaryBanner(months, org, repo, entity) {
 }
 
 function loadTabCharts(tab, months, org, repo, entity) {
-    var q = 'm=' + months + '&o=' + org + '&r=' + repo + '&e=' + entity;
+    var q = 'm=' + months + '&o=' + org + '&r=' + repo + '&e=' + entity + cohortParam() + discussionParam() + granularityParam();
+    loadAnomalies(months, org, repo);
     switch (tab) {
         case 'health':
             loadInsightsSummary('/data/insights/summary?' + q);
             loadHealthActivitySparkline('/data/insights/daily-activity?' + q);
+            loadFactorTrendChart('/data/insights/factor-trend?' + q + '&w=' + $("#factor-window-select").val());
             loadRepoMeta('/data/insights/repo-meta?o=' + org + '&r=' + repo);
             if (repo) {
                 $("#stars-trend-panel").show();
@@ -351,26 +458,47 @@ function loadTabCharts(tab, months, org, repo, entity) {
             loadPRSizeChart('/data/insights/pr-size?' + q);
             loadForksAndActivityChart('/data/insights/forks-and-activity?' + q);
             loadIssueRatioChart('/data/insights/issue-ratio?' + q);
+            loadActivityHeatmapChart('/data/insights/activity-heatmap?' + q);
+            loadOffHoursChart('/data/insights/off-hours?' + q);
             break;
         case 'velocity':
+            loadDORASummary('/data/insights/dora?' + q);
             loadTimeToFirstResponseChart('/data/insights/time-to-first-response?' + q);
             loadVelocityChart('/data/insights/time-to-merge?' + q, 'time-to-merge-chart', 'timeToMerge');
+            loadVelocityChart('/data/insights/issue-fix-lead-time?' + q, 'issue-fix-lead-time-chart', 'issueFixLeadTime');
+            loadPRLifecycleChart('/data/insights/pr-lifecycle?' + q);
             loadChangeFailureRateChart('/data/insights/change-failure-rate?' + q);
             loadReleaseCadenceChart('/data/insights/release-cadence?' + q);
-            loadReleaseDownloadsChart('/data/insights/release-downloads?m=' + months + '&o=' + org + '&r=' + repo);
-            loadReleaseDownloadsByTagChart('/data/insights/release-downloads-by-tag?m=' + months + '&o=' + org + '&r=' + repo);
+            loadReleaseDownloadsChart('/data/insights/release-downloads?' + q);
+            loadReleaseDownloadsByTagChart('/data/insights/release-downloads-by-tag?' + q);
             loadContainerActivityChart('/data/insights/container-activity?' + q);
             break;
         case 'quality':
             loadPRRatioChart('/data/insights/pr-ratio?' + q);
             loadReviewLatencyChart('/data/insights/review-latency?' + q);
+            loadReviewDepthChart('/data/insights/review-depth?' + q);
+            loadApproverConcentrationChart('/data/insights/approver-concentration?' + q);
             loadTimeToCloseChart('/data/insights/time-to-close?' + q, '/data/insights/time-to-restore?' + q);
+            loadTimeToTriageChart('/data/insights/time-to-triage?' + q);
+            loadReopenRateChart('/data/insights/reopen-rate?' + q);
+            loadLabelDwellChart('/data/insights/label-dwell?' + q);
+            loadReviewRequestLatencyChart('/data/insights/review-request-latency?' + q);
+            loadIssueResolutionChart('/data/insights/issue-resolution?' + q);
+            loadBacklogAgingChart('/data/insights/backlog-aging?' + q);
+            loadBacklogHistoryChart('/data/insights/backlog-history?' + q);
             loadReputationChart('/data/insights/reputation?' + q);
             break;
         case 'community':
             loadRetentionChart('/data/insights/retention?' + q);
             loadContributorMomentumChart('/data/insights/contributor-momentum?' + q);
+            loadContributorLifecycleChart('/data/insights/contributor-lifecycle?' + q);
+            loadCohortRetentionTable('/data/insights/cohort-retention?' + q + '&split=' + ($("#cohort-retention-split-select").val() || ''));
             loadContributorFunnelChart('/data/insights/contributor-funnel?' + q);
+            loadExternalShareChart('/data/insights/external-share?' + q);
+            loadEntityDiversityChart('/data/insights/entity-diversity?' + q + ($("#diversity-unaffiliated-select").val() ? '&xu=true' : ''));
+            loadMostWantedChart('/data/insights/most-wanted?' + q);
+            loadDiscussionsChart('/data/insights/discussions?' + q);
+            loadTopAnswerersChart('/data/insights/top-answerers?' + q);
             (function() {
                 var onLeftExclude = function () {
                     leftChart.destroy();
@@ -389,6 +517,10 @@ function loadTabCharts(tab, months, org, repo, entity) {
             })();
             initContributorSearch(q);
             break;
+        case 'planning':
+            loadMilestonesChart('/data/insights/milestones?' + q);
+            loadProjectStatusChart('/data/insights/project-status?o=' + org + '&r=' + repo);
+            break;
         case 'events':
             break;
     }
@@ -635,6 +767,67 @@ function resetCharts() {
     if (reviewLatencyChart) {
         reviewLatencyChart.destroy();
     }
+    if (reviewDepthChart) {
+        reviewDepthChart.destroy();
+    }
+    if (approverConcentrationChart) {
+        approverConcentrationChart.destroy();
+    }
+    if (timeToTriageChart) {
+        timeToTriageChart.destroy();
+    }
+    if (reopenRateChart) {
+        reopenRateChart.destroy();
+    }
+    if (labelDwellChart) {
+        labelDwellChart.destroy();
+    }
+    if (reviewRequestLatencyChart) {
+        reviewRequestLatencyChart.destroy();
+    }
+    if (issueResolutionChart) {
+        issueResolutionChart.destroy();
+    }
+    if (issueFixLeadTimeChart) {
+        issueFixLeadTimeChart.destroy();
+    }
+    if (prLifecycleChart) {
+        prLifecycleChart.destroy();
+    }
+    if (backlogAgingChart) {
+        backlogAgingChart.destroy();
+    }
+    if (backlogHistoryChart) {
+        backlogHistoryChart.destroy();
+    }
+    if (activityHeatmapChart) {
+        activityHeatmapChart.destroy();
+    }
+    if (offHoursChart) {
+        offHoursChart.destroy();
+    }
+    if (mostWantedChart) {
+        mostWantedChart.destroy();
+    }
+    if (discussionsChart) {
+        discussionsChart.destroy();
+    }
+    if (topAnswerersChart) {
+        topAnswerersChart.destroy();
+    }
+    if (externalShareChart) {
+        externalShareChart.destroy();
+    }
+    if (milestonesChart) {
+        milestonesChart.destroy();
+    }
+    if (milestoneBurnupChart) {
+        milestoneBurnupChart.destroy();
+    }
+    $("#milestone-burnup-title").text('');
+    if (projectStatusChart) {
+        projectStatusChart.destroy();
+    }
     if (prSizeChart) {
         prSizeChart.destroy();
     }
@@ -644,6 +837,15 @@ function resetCharts() {
     if (contributorMomentumChart) {
         contributorMomentumChart.destroy();
     }
+    if (factorTrendChart) {
+        factorTrendChart.destroy();
+    }
+    if (entityDiversityChart) {
+        entityDiversityChart.destroy();
+    }
+    if (contributorLifecycleChart) {
+        contributorLifecycleChart.destroy();
+    }
     if (contributorProfileChart) {
         contributorProfileChart.destroy();
         contributorProfileChart = null;
@@ -687,6 +889,7 @@ function onRightChartSelect(label) {
 function submitSearch() {
     $("#tbl-criteria").html(searchCriteria.String());
     const table = $("#result-table-content").empty();
+    searchCriteria.cohort = $("#cohort-select").val() || null;
     const criteria = JSON.stringify(searchCriteria);
 
     $.post("/data/search", criteria).done(function (data) {
@@ -769,24 +972,30 @@ function loadTimeSeriesChart(url, fn) {
                     backgroundColor: colors[1],
                     borderWidth: 1,
                     order: 3
+                }, {
+                    label: 'PR-Review-Comment',
+                    data: data.pr_review_comment,
+                    backgroundColor: colors[7],
+                    borderWidth: 1,
+                    order: 4
                 }, {
                     label: 'Issue',
                     data: data.issue,
                     backgroundColor: colors[2],
                     borderWidth: 1,
-                    order: 4
+                    order: 5
                 }, {
                     label: 'Issue-Comment',
                     data: data.issue_comment,
                     backgroundColor: colors[3],
                     borderWidth: 1,
-                    order: 5
+                    order: 6
                 }, {
                     label: 'Fork',
                     data: data.fork,
                     backgroundColor: colors[4],
                     borderWidth: 1,
-                    order: 6
+                    order: 7
                 },{
                     label: 'Total',
                     type: 'line',
@@ -1105,13 +1314,13 @@ function loadTimeToCloseChart(closeURL, restoreURL) {
                 labels: close.months,
                 datasets: [{
                     label: 'All Issues',
-                    data: close.avg_days,
+                    data: durationField(close, 'avg_days', 'p50_days'),
                     backgroundColor: colors[2],
                     borderWidth: 1,
                     order: 2
                 }, {
-                    label: 'Bug (near release)',
-                    data: restore.avg_days,
+                    label: 'Failed Changes',
+                    data: durationField(restore, 'avg_days', 'p50_days'),
                     backgroundColor: colors[3],
                     borderWidth: 1,
                     order: 1
@@ -1124,7 +1333,7 @@ function loadTimeToCloseChart(closeURL, restoreURL) {
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
                     y: { beginAtZero: true, ticks: { font: { size: 14 } },
-                        title: { display: true, text: 'Avg Days' } }
+                        title: { display: true, text: durationLabel('Days') } }
                 }
             }
         });
@@ -1133,14 +1342,15 @@ function loadTimeToCloseChart(closeURL, restoreURL) {
 
 function loadVelocityChart(url, canvasId, key) {
     $.get(url, function (data) {
-        if (timeToMergeChart) timeToMergeChart.destroy();
+        if (key === 'timeToMerge' && timeToMergeChart) timeToMergeChart.destroy();
+        if (key === 'issueFixLeadTime' && issueFixLeadTimeChart) issueFixLeadTimeChart.destroy();
         const chart = new Chart($(`#${canvasId}`)[0].getContext("2d"), {
             type: 'bar',
             data: {
                 labels: data.months,
                 datasets: [{
-                    label: 'Avg Days',
-                    data: data.avg_days,
+                    label: durationLabel('Days'),
+                    data: durationField(data, 'avg_days', 'p50_days'),
                     backgroundColor: colors[2],
                     borderWidth: 1,
                     yAxisID: 'y',
@@ -1166,7 +1376,7 @@ function loadVelocityChart(url, canvasId, key) {
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
                     y: { beginAtZero: true, position: 'left', ticks: { font: { size: 14 } },
-                        title: { display: true, text: 'Avg Days' } },
+                        title: { display: true, text: durationLabel('Days') } },
                     y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
                         ticks: { precision: 0, font: { size: 14 } },
                         title: { display: true, text: 'Count' } }
@@ -1175,6 +1385,7 @@ function loadVelocityChart(url, canvasId, key) {
         });
         if (key === 'timeToMerge') { timeToMergeChart = chart; }
         if (key === 'timeToClose') { timeToCloseChart = chart; }
+        if (key === 'issueFixLeadTime') { issueFixLeadTimeChart = chart; }
     });
 }
 
@@ -1266,17 +1477,39 @@ function loadTimeToFirstResponseChart(url) {
             data: {
                 labels: data.months,
                 datasets: [{
-                    label: 'Issues (avg hrs)',
-                    data: data.issue_avg,
+                    label: durationMedian() ? 'Issues (median hrs)' : 'Issues (avg hrs)',
+                    data: durationField(data, 'issue_avg', 'issue_p50'),
                     backgroundColor: colors[3],
                     borderWidth: 1,
                     order: 2
                 }, {
-                    label: 'PRs (avg hrs)',
-                    data: data.pr_avg,
+                    label: durationMedian() ? 'PRs (median hrs)' : 'PRs (avg hrs)',
+                    data: durationField(data, 'pr_avg', 'pr_p50'),
                     backgroundColor: colors[2],
                     borderWidth: 1,
                     order: 1
+                }, {
+                    label: 'Issues (business hrs)',
+                    type: 'line',
+                    data: durationField(data, 'issue_business_avg', 'issue_business_p50'),
+                    borderColor: colors[3],
+                    borderWidth: 2,
+                    borderDash: [5, 5],
+                    fill: false,
+                    hidden: true,
+                    order: 0,
+                    tension: 0.3
+                }, {
+                    label: 'PRs (business hrs)',
+                    type: 'line',
+                    data: durationField(data, 'pr_business_avg', 'pr_business_p50'),
+                    borderColor: colors[2],
+                    borderWidth: 2,
+                    borderDash: [5, 5],
+                    fill: false,
+                    hidden: true,
+                    order: 0,
+                    tension: 0.3
                 }]
             },
             options: {
@@ -1286,7 +1519,7 @@ function loadTimeToFirstResponseChart(url) {
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
                     y: { beginAtZero: true, ticks: { font: { size: 14 } },
-                        title: { display: true, text: 'Avg Hours' } }
+                        title: { display: true, text: durationLabel('Hours') } }
                 }
             }
         });
@@ -1513,6 +1746,40 @@ function loadRepoOverview(url) {
     });
 }
 
+var doraTrendArrows = { improving: '\u25B2 improving', declining: '\u25BC declining', steady: '\u25B6 steady' };
+
+function formatDORAValue(m, v, samples) {
+    if (!samples) return '\u2014';
+    if (m.unit === '%') return v.toFixed(1) + '%';
+    if (m.unit === 'hours' && v >= 48) return (v / 24).toFixed(1) + ' days';
+    return v.toFixed(1) + ' ' + m.unit;
+}
+
+function doraTierBadge(tier) {
+    if (!tier) return $('<span></span>').text('n/a');
+    return $('<span class="dora-tier"></span>').addClass('dora-tier-' + tier).text(tier);
+}
+
+function loadDORASummary(url) {
+    $.get(url, function (data) {
+        var $tbody = $("#dora-table tbody");
+        $tbody.empty();
+        $("#dora-tier").empty().append(data.tier ? doraTierBadge(data.tier) : '');
+        $.each(data.metrics || [], function (i, m) {
+            var $row = $('<tr></tr>');
+            $row.append($('<td></td>').text(m.name));
+            $row.append($('<td class="num"></td>').text(formatDORAValue(m, m.value, m.samples)));
+            $row.append($('<td></td>').append(doraTierBadge(m.tier)));
+            $row.append($('<td class="num"></td>').text(formatDORAValue(m, m.previous, m.previous_samples)));
+            $row.append($('<td></td>').append(doraTierBadge(m.previous_tier)));
+            $row.append($('<td></td>').text(doraTrendArrows[m.trend] || '\u2014'));
+            $row.attr('title', m.samples + ' samples (previous ' + m.previous_samples + ')');
+            $tbody.append($row);
+        });
+        $("#dora-desc").attr('title', data.from + ' to ' + data.to + ' vs ' + data.previous_from + ' to ' + data.previous_to);
+    });
+}
+
 function loadReleaseCadenceChart(url) {
     $.get(url, function (data) {
         if (releaseCadenceChart) releaseCadenceChart.destroy();
@@ -1780,18 +2047,107 @@ function initPeriodSelector() {
     $("#period-select").on("change", function () {
         const months = $(this).val();
         $("#period_months").val(months);
-        resetCharts();
+        reloadSelection(months);
+    });
+}
 
-        let org = "", repo = "", entity = "";
-        if (searchItem) {
-            const scope = ($("#search-bar").val().match(/^(org|repo|entity):/i) || [])[1] || "org";
-            switch (scope.toLowerCase()) {
-                case "org": org = searchItem.value; break;
-                case "repo": repo = searchItem.value; break;
-                case "entity": entity = searchItem.value; break;
-            }
+// reloadSelection reloads all charts for the current search selection.
+function reloadSelection(months) {
+    resetCharts();
+
+    let org = "", repo = "", entity = "";
+    if (searchItem) {
+        const scope = ($("#search-bar").val().match(/^(org|repo|entity):/i) || [])[1] || "org";
+        switch (scope.toLowerCase()) {
+            case "org": org = searchItem.value; break;
+            case "repo": repo = searchItem.value; break;
+            case "entity": entity = searchItem.value; break;
         }
-        loadAllCharts(months, org, repo, entity);
+    }
+    loadAllCharts(months, org, repo, entity);
+}
+
+// cohortParam returns the cohort query parameter for the selected cohort.
+function cohortParam() {
+    const cohort = $("#cohort-select").val() || "";
+    return cohort ? '&c=' + encodeURIComponent(cohort) : '';
+}
+
+function initCohortSelector() {
+    $.get('/data/cohorts', function (data) {
+        if (!data || data.length === 0) return;
+        const sel = $("#cohort-select");
+        $.each(data, function (i, c) {
+            sel.append($('<option>').val(c.name).text(`${c.name} (${c.member_count})`));
+        });
+        $("#cohort-wrap").show();
+    });
+
+    $("#cohort-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+// granularityParam returns the query parameter selecting the day, week,
+// month or quarter buckets of insight series.
+function granularityParam() {
+    return '&g=' + ($("#granularity-select").val() || 'month');
+}
+
+function initFactorWindowSelector() {
+    $("#factor-window-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+function initDiversitySelector() {
+    $("#diversity-unaffiliated-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+function initCohortRetentionSelector() {
+    $("#cohort-retention-split-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+function initGranularitySelector() {
+    $("#granularity-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+// discussionParam returns the query parameter opting discussion activity
+// into contributor retention, momentum and funnel metrics.
+function discussionParam() {
+    return $("#discussions-select").val() === "true" ? '&d=true' : '';
+}
+
+function initDiscussionSelector() {
+    $("#discussions-select").on("change", function () {
+        reloadSelection($("#period_months").val());
+    });
+}
+
+// durationMedian reports whether duration charts show monthly medians
+// instead of means.
+function durationMedian() {
+    return $("#duration-select").val() === "median";
+}
+
+// durationField picks the mean or median values of a duration series.
+function durationField(data, avgKey, p50Key) {
+    return durationMedian() ? data[p50Key] : data[avgKey];
+}
+
+function durationLabel(unit) {
+    return (durationMedian() ? 'Median ' : 'Avg ') + unit;
+}
+
+function initDurationSelector() {
+    $("#duration-select").on("change", function () {
+        reloadSelection($("#period_months").val());
     });
 }
 
@@ -1881,12 +2237,23 @@ function loadReviewLatencyChart(url) {
             data: {
                 labels: data.months,
                 datasets: [{
-                    label: 'Avg Hours',
-                    data: data.avg_hours,
+                    label: durationLabel('Hours'),
+                    data: durationField(data, 'avg_hours', 'p50_hours'),
                     backgroundColor: colors[2],
                     borderWidth: 1,
                     yAxisID: 'y',
                     order: 2
+                }, {
+                    label: 'Business Hours',
+                    type: 'line',
+                    data: durationField(data, 'business_avg_hours', 'business_p50_hours'),
+                    borderColor: colors[0],
+                    borderWidth: 2,
+                    borderDash: [5, 5],
+                    fill: false,
+                    yAxisID: 'y',
+                    order: 1,
+                    tension: 0.3
                 }, {
                     label: 'Count',
                     type: 'line',
@@ -1906,7 +2273,7 @@ function loadReviewLatencyChart(url) {
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
                     y: { beginAtZero: true, position: 'left', ticks: { font: { size: 14 } },
-                        title: { display: true, text: 'Avg Hours' } },
+                        title: { display: true, text: durationLabel('Hours') } },
                     y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
                         ticks: { precision: 0, font: { size: 14 } },
                         title: { display: true, text: 'Count' } }
@@ -1916,21 +2283,37 @@ function loadReviewLatencyChart(url) {
     });
 }
 
-function loadChangeFailureRateChart(url) {
+function loadReviewDepthChart(url) {
     $.get(url, function (data) {
-        if (changeFailureRateChart) changeFailureRateChart.destroy();
-        changeFailureRateChart = new Chart($("#change-failure-rate-chart")[0].getContext("2d"), {
-            type: 'line',
+        if (reviewDepthChart) reviewDepthChart.destroy();
+        reviewDepthChart = new Chart($("#review-depth-chart")[0].getContext("2d"), {
+            type: 'bar',
             data: {
                 labels: data.months,
                 datasets: [{
-                    label: 'Failure Rate %',
-                    data: data.rate,
+                    label: 'Reviews per PR',
+                    data: data.reviews_per_pr,
+                    backgroundColor: colors[0],
+                    borderWidth: 1,
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Rounds to Approval',
+                    data: data.approval_rounds,
+                    backgroundColor: colors[1],
+                    borderWidth: 1,
+                    yAxisID: 'y',
+                    order: 3
+                }, {
+                    label: 'Changes Requested %',
+                    type: 'line',
+                    data: data.change_request_rate,
                     borderColor: colors[3],
-                    backgroundColor: colors[3] + '33',
-                    fill: true,
-                    tension: 0.3,
-                    pointRadius: 3
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
                 }]
             },
             options: {
@@ -1939,75 +2322,89 @@ function loadChangeFailureRateChart(url) {
                 plugins: { legend: { display: true } },
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
-                    y: { beginAtZero: true, ticks: { font: { size: 14 },
-                        callback: function(v) { return v + '%'; } },
-                        title: { display: true, text: 'Failure Rate' } }
+                    y: { beginAtZero: true, position: 'left', ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'Per PR' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 }, callback: function(v) { return v + '%'; } },
+                        title: { display: true, text: 'Changes Requested' } }
                 }
             }
         });
     });
 }
 
-function loadPRSizeChart(url) {
+function loadApproverConcentrationChart(url) {
     $.get(url, function (data) {
-        if (!data.months || data.months.length === 0) {
+        if (data.approvals > 0) {
+            $("#approver-counts").text('Approvals: ' + data.approvals + ' / Approvers: ' + data.approvers + ' / Approver factor: ' + data.factor);
+        } else {
+            $("#approver-counts").text('');
+        }
+        if (approverConcentrationChart) approverConcentrationChart.destroy();
+        if (!data.top || data.top.length === 0) {
             return;
         }
-        if (prSizeChart) prSizeChart.destroy();
-        prSizeChart = new Chart($("#pr-size-chart")[0].getContext("2d"), {
+        approverConcentrationChart = new Chart($("#approver-concentration-chart")[0].getContext("2d"), {
             type: 'bar',
             data: {
-                labels: data.months,
+                labels: data.top.map(function (a) { return a.username; }),
                 datasets: [{
-                    label: 'S (<50)',
-                    data: data.small,
-                    backgroundColor: colors[1]
-                }, {
-                    label: 'M (50-250)',
-                    data: data.medium,
-                    backgroundColor: colors[0]
-                }, {
-                    label: 'L (250-1K)',
-                    data: data.large,
-                    backgroundColor: colors[2]
-                }, {
-                    label: 'XL (>1K)',
-                    data: data.xlarge,
-                    backgroundColor: colors[3]
+                    label: 'Share of Approvals',
+                    data: data.top.map(function (a) { return a.percent; }),
+                    backgroundColor: colors[4],
+                    borderWidth: 0,
+                    barPercentage: 0.6,
+                    categoryPercentage: 0.8
                 }]
             },
             options: {
+                indexAxis: 'y',
                 responsive: true,
                 maintainAspectRatio: false,
-                plugins: { legend: { display: true } },
+                plugins: { legend: { display: false } },
                 scales: {
-                    x: { stacked: true, ticks: { font: { size: 14 } } },
-                    y: { stacked: true, beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } }
+                    x: { beginAtZero: true, ticks: { font: { size: 14 },
+                        callback: function(v) { return v + '%'; } } },
+                    y: { ticks: { font: { size: 14 } } }
+                },
+                onHover: (evt, item) => {
+                    evt.native.target.style.cursor = item.length ? 'pointer' : 'default';
+                },
+                onClick: (evt, item) => {
+                    if (item.length) {
+                        const username = approverConcentrationChart.data.labels[item[0].index];
+                        window.open('https://github.com/' + encodeURIComponent(username), '_blank');
+                    }
                 }
             }
         });
     });
 }
 
-function loadContributorFunnelChart(url) {
+function loadTimeToTriageChart(url) {
     $.get(url, function (data) {
-        if (contributorFunnelChart) contributorFunnelChart.destroy();
-        contributorFunnelChart = new Chart($("#contributor-funnel-chart")[0].getContext("2d"), {
+        if (timeToTriageChart) timeToTriageChart.destroy();
+        timeToTriageChart = new Chart($("#time-to-triage-chart")[0].getContext("2d"), {
             type: 'bar',
             data: {
                 labels: data.months,
                 datasets: [{
-                    label: 'First Comment',
-                    data: data.first_comment,
-                    backgroundColor: colors[0]
-                }, {
-                    label: 'First PR',
-                    data: data.first_pr,
-                    backgroundColor: colors[1]
+                    label: 'Avg Hours',
+                    data: data.avg_hours,
+                    backgroundColor: colors[0],
+                    borderWidth: 1,
+                    yAxisID: 'y',
+                    order: 2
                 }, {
-                    label: 'First Merge',
-                    data: data.first_merge,
-                    backgroundColor: colors[4]
+                    label: 'Untriaged',
+                    type: 'line',
+                    data: data.untriaged,
+                    borderColor: colors[3],
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
                 }]
             },
             options: {
@@ -2016,44 +2413,1123 @@ function loadContributorFunnelChart(url) {
                 plugins: { legend: { display: true } },
                 scales: {
                     x: { ticks: { font: { size: 14 } } },
-                    y: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } }
+                    y: { beginAtZero: true, position: 'left', ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'Avg Hours' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Untriaged' } }
                 }
             }
         });
     });
 }
 
-function loadContributorProfileChart(url) {
+function loadReopenRateChart(url) {
     $.get(url, function (data) {
-        if (contributorProfileChart) contributorProfileChart.destroy();
-        if (data && data.reputation != null) {
-            $("#contributor-score").text('Reputation Score: ' + data.reputation.toFixed(2));
-        } else {
-            $("#contributor-score").text('');
-        }
-        if (!data || !data.metrics) return;
-
-        var barColor = colors[0];
-        var avgColor = barColor.replace('rgb', 'rgba').replace(')', ', 0.35)');
-        if (barColor.startsWith('#')) {
-            var r = parseInt(barColor.slice(1,3), 16);
-            var g = parseInt(barColor.slice(3,5), 16);
-            var b = parseInt(barColor.slice(5,7), 16);
-            avgColor = 'rgba(' + r + ',' + g + ',' + b + ',0.35)';
-            barColor = 'rgba(' + r + ',' + g + ',' + b + ',1)';
-        }
-
-        contributorProfileChart = new Chart($("#contributor-profile-chart")[0].getContext("2d"), {
+        if (reopenRateChart) reopenRateChart.destroy();
+        reopenRateChart = new Chart($("#reopen-rate-chart")[0].getContext("2d"), {
             type: 'bar',
             data: {
-                labels: data.metrics,
-                datasets: [
-                    {
-                        label: 'Contributor',
-                        data: data.values,
-                        backgroundColor: barColor,
-                        borderRadius: 3
-                    },
+                labels: data.months,
+                datasets: [{
+                    label: 'Closed',
+                    data: data.closed,
+                    backgroundColor: colors[1],
+                    borderWidth: 1,
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Reopen Rate %',
+                    type: 'line',
+                    data: data.rate,
+                    borderColor: colors[3],
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Closed' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 }, callback: function(v) { return v + '%'; } },
+                        title: { display: true, text: 'Reopen Rate' } }
+                }
+            }
+        });
+    });
+}
+
+function formatOutcomes(label, o) {
+    if (!o || o.opened === 0) {
+        return '';
+    }
+    return label + ': ' + o.opened + ' PRs, ' + o.merge_rate.toFixed(0) + '% merged, ' + o.abandon_rate.toFixed(0) + '% abandoned';
+}
+
+function loadPRLifecycleChart(url) {
+    $.get(url, function (data) {
+        const counts = [formatOutcomes('First-time', data.first_time), formatOutcomes('Returning', data.returning)]
+            .filter(function (s) { return s !== ''; });
+        $("#pr-lifecycle-counts").text(counts.join(' / '));
+        if (prLifecycleChart) prLifecycleChart.destroy();
+        const open = data.opened.map(function (o, i) {
+            return o - data.merged[i] - data.closed[i] - data.stale[i];
+        });
+        prLifecycleChart = new Chart($("#pr-lifecycle-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'Merged',
+                    data: data.merged,
+                    backgroundColor: colors[1],
+                    borderWidth: 1,
+                    stack: 'prs',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Closed Unmerged',
+                    data: data.closed,
+                    backgroundColor: colors[3],
+                    borderWidth: 1,
+                    stack: 'prs',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Abandoned',
+                    data: data.stale,
+                    backgroundColor: colors[2],
+                    borderWidth: 1,
+                    stack: 'prs',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Open',
+                    data: open,
+                    backgroundColor: colors[5],
+                    borderWidth: 1,
+                    stack: 'prs',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Merge Rate %',
+                    type: 'line',
+                    data: data.merge_rate,
+                    borderColor: colors[0],
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const i = items[0].dataIndex;
+                                if (!data.drafts[i]) {
+                                    return '';
+                                }
+                                return 'Drafts: ' + data.drafts[i] + ' / Avg draft hours: ' + data.avg_draft_hours[i].toFixed(1);
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'PRs' } },
+                    y1: { beginAtZero: true, max: 100, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 }, callback: function(v) { return v + '%'; } },
+                        title: { display: true, text: 'Merge Rate' } }
+                }
+            }
+        });
+    });
+}
+
+function loadActivityHeatmapChart(url) {
+    $.get(url, function (data) {
+        let max = 0;
+        const points = [];
+        $.each(data.counts, function (d, hours) {
+            $.each(hours, function (h, c) {
+                if (c > 0) {
+                    points.push({ x: h, y: d, v: c });
+                    max = Math.max(max, c);
+                }
+            });
+        });
+        $.each(points, function (i, p) {
+            p.r = 2 + 12 * Math.sqrt(p.v / max);
+        });
+        if (activityHeatmapChart) activityHeatmapChart.destroy();
+        activityHeatmapChart = new Chart($("#activity-heatmap-chart")[0].getContext("2d"), {
+            type: 'bubble',
+            data: {
+                datasets: [{
+                    label: 'Events',
+                    data: points,
+                    backgroundColor: colors[0] + '99',
+                    borderColor: colors[0],
+                    borderWidth: 1
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: false },
+                    tooltip: {
+                        callbacks: {
+                            label: function (ctx) {
+                                const p = ctx.raw;
+                                return data.days[p.y] + ' ' + String(p.x).padStart(2, '0') + ':00 UTC: ' + p.v;
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { min: -0.5, max: 23.5, ticks: { stepSize: 1, font: { size: 12 } },
+                        title: { display: true, text: 'Hour (UTC)' } },
+                    y: { min: -0.5, max: 6.5, reverse: true,
+                        ticks: { stepSize: 1, font: { size: 12 },
+                            callback: function (v) { return data.days[v] || ''; } } }
+                }
+            }
+        });
+    });
+}
+
+function loadOffHoursChart(url) {
+    $.get(url, function (data) {
+        if (offHoursChart) offHoursChart.destroy();
+        offHoursChart = new Chart($("#off-hours-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (d) { return d.entity; }),
+                datasets: [{
+                    label: 'Weekend %',
+                    data: data.map(function (d) { return d.weekend_pct; }),
+                    backgroundColor: colors[4],
+                    borderWidth: 1
+                }, {
+                    label: 'After Hours %',
+                    data: data.map(function (d) { return d.after_hours_pct; }),
+                    backgroundColor: colors[2],
+                    borderWidth: 1
+                }]
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                return 'Events: ' + data[items[0].dataIndex].events;
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { stacked: true, beginAtZero: true, max: 100, ticks: { font: { size: 14 } },
+                        title: { display: true, text: '% of Events' } },
+                    y: { stacked: true, ticks: { font: { size: 12 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadExternalShareChart(url) {
+    $.get(url, function (data) {
+        if (externalShareChart) externalShareChart.destroy();
+        const bar = function (label, values, color) {
+            return { label: label, data: values, backgroundColor: color, borderWidth: 1, stack: 'merged', order: 2 };
+        };
+        const line = function (label, values, color) {
+            return {
+                label: label, type: 'line', data: values, borderColor: color, backgroundColor: color,
+                tension: 0.3, pointRadius: 3, fill: false, yAxisID: 'y1', order: 1
+            };
+        };
+        externalShareChart = new Chart($("#external-share-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [
+                    bar('Members', data.merged.member, colors[5]),
+                    bar('Collaborators', data.merged.collaborator, colors[4]),
+                    bar('Contributors', data.merged.contributor, colors[0]),
+                    bar('First-Timers', data.merged.first_timer, colors[1]),
+                    line('External % Opened', data.opened.external_share, colors[2]),
+                    line('External % Merged', data.merged.external_share, colors[3]),
+                    line('External % Reviews', data.reviews.external_share, colors[4])
+                ]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            label: function (item) {
+                                if (item.dataset.yAxisID === 'y1') {
+                                    return item.dataset.label + ': ' + item.raw.toFixed(1) + '%';
+                                }
+                                return item.dataset.label + ': ' + item.raw + ' merged';
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Merged PRs' } },
+                    y1: { beginAtZero: true, max: 100, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'External %' } }
+                }
+            }
+        });
+    });
+}
+
+function loadEntityDiversityChart(url) {
+    $.get(url, function (data) {
+        if (entityDiversityChart) entityDiversityChart.destroy();
+        const hhi = function (label, idx, color) {
+            return { label: label + ' HHI', data: idx.hhi, borderColor: color, backgroundColor: color,
+                tension: 0.3, pointRadius: 3, fill: false, yAxisID: 'y' };
+        };
+        const entropy = function (label, idx, color) {
+            return { label: label + ' Entropy', data: idx.entropy, borderColor: color, backgroundColor: color,
+                borderDash: [5, 5], tension: 0.3, pointRadius: 2, fill: false, yAxisID: 'y1', hidden: true };
+        };
+        entityDiversityChart = new Chart($("#entity-diversity-chart")[0].getContext("2d"), {
+            type: 'line',
+            data: {
+                labels: data.months,
+                datasets: [
+                    hhi('Code', data.code, colors[0]),
+                    hhi('Reviews', data.reviews, colors[1]),
+                    hhi('Maintainership', data.maintainership, colors[3]),
+                    entropy('Code', data.code, colors[0]),
+                    entropy('Reviews', data.reviews, colors[1]),
+                    entropy('Maintainership', data.maintainership, colors[3])
+                ]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                interaction: { mode: 'index', intersect: false },
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            label: function (item) {
+                                const idx = [data.code, data.reviews, data.maintainership][item.datasetIndex % 3];
+                                const v = item.dataset.yAxisID === 'y1' ? item.raw.toFixed(2) + ' bits' : Math.round(item.raw).toLocaleString();
+                                return item.dataset.label + ': ' + v + ' (' + idx.entities[item.dataIndex] + ' entities, ' + idx.actions[item.dataIndex] + ' actions)';
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, max: 10000, position: 'left', ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'HHI' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'Entropy (bits)' } }
+                }
+            }
+        });
+    });
+}
+
+function loadMostWantedChart(url) {
+    $.get(url, function (data) {
+        if (mostWantedChart) mostWantedChart.destroy();
+        mostWantedChart = new Chart($("#most-wanted-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (d) { return d.repo + '#' + d.number; }),
+                datasets: [{
+                    label: '\u{1F44D}',
+                    data: data.map(function (d) { return d.plus_one; }),
+                    backgroundColor: colors[0],
+                    borderWidth: 1
+                }, {
+                    label: 'Commenters',
+                    data: data.map(function (d) { return d.commenters; }),
+                    backgroundColor: colors[1],
+                    borderWidth: 1
+                }]
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                onClick: function (e, items) {
+                    if (items.length > 0) {
+                        window.open(data[items[0].index].url, '_blank');
+                    }
+                },
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            title: function (items) {
+                                return data[items[0].dataIndex].title;
+                            },
+                            footer: function (items) {
+                                const d = data[items[0].dataIndex];
+                                return 'Reactions: ' + d.reactions + ' / Comments: ' + d.comments;
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } },
+                    y: { ticks: { font: { size: 12 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadDiscussionsChart(url) {
+    $.get(url, function (data) {
+        if (discussionsChart) discussionsChart.destroy();
+        discussionsChart = new Chart($("#discussions-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'Opened',
+                    data: data.opened,
+                    backgroundColor: colors[5],
+                    borderWidth: 1,
+                    order: 2
+                }, {
+                    label: 'Answered',
+                    data: data.answered,
+                    backgroundColor: colors[1],
+                    borderWidth: 1,
+                    order: 2
+                }, {
+                    label: 'Hours to First Reply',
+                    type: 'line',
+                    data: data.first_reply_hours,
+                    borderColor: colors[0],
+                    backgroundColor: colors[0],
+                    tension: 0.3,
+                    pointRadius: 3,
+                    yAxisID: 'y1',
+                    order: 1
+                }, {
+                    label: 'Hours to Answer',
+                    type: 'line',
+                    data: data.answer_hours,
+                    borderColor: colors[4],
+                    backgroundColor: colors[4],
+                    tension: 0.3,
+                    pointRadius: 3,
+                    yAxisID: 'y1',
+                    order: 1
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const i = items[0].dataIndex;
+                                if (data.answerable[i] === 0) return '';
+                                return 'Answer rate: ' + data.answer_rate[i].toFixed(1) + '% of ' + data.answerable[i] + ' questions';
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Discussions' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'Hours' } }
+                }
+            }
+        });
+    });
+}
+
+function loadTopAnswerersChart(url) {
+    $.get(url, function (data) {
+        if (topAnswerersChart) topAnswerersChart.destroy();
+        topAnswerersChart = new Chart($("#top-answerers-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (d) { return d.username; }),
+                datasets: [{
+                    label: 'Accepted Answers',
+                    data: data.map(function (d) { return d.answers; }),
+                    backgroundColor: colors[1],
+                    borderWidth: 1
+                }, {
+                    label: 'Replies',
+                    data: data.map(function (d) { return d.replies; }),
+                    backgroundColor: colors[0],
+                    borderWidth: 1
+                }]
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                onClick: function (e, items) {
+                    if (items.length > 0) {
+                        window.open('https://github.com/' + data[items[0].index].username, '_blank');
+                    }
+                },
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const d = data[items[0].dataIndex];
+                                return d.entity ? 'Entity: ' + d.entity : '';
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } },
+                    y: { ticks: { font: { size: 12 } } }
+                }
+            }
+        });
+    });
+}
+
+const milestoneStatusColors = {
+    on_track: colors[1],
+    at_risk: colors[2],
+    overdue: colors[3],
+    no_due_date: colors[5],
+    complete: colors[0]
+};
+
+const milestoneStatusLabels = {
+    on_track: 'On track',
+    at_risk: 'At risk',
+    overdue: 'Overdue',
+    no_due_date: 'No due date',
+    complete: 'Complete'
+};
+
+function loadMilestonesChart(url) {
+    $.get(url, function (data) {
+        if (milestonesChart) milestonesChart.destroy();
+        if (milestoneBurnupChart) milestoneBurnupChart.destroy();
+        $("#milestone-burnup-title").text('');
+        milestonesChart = new Chart($("#milestones-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (d) { return d.repo + ': ' + d.title; }),
+                datasets: [{
+                    label: '% Complete',
+                    data: data.map(function (d) { return d.percent_complete; }),
+                    backgroundColor: data.map(function (d) { return milestoneStatusColors[d.status]; }),
+                    borderWidth: 1
+                }]
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                onClick: function (e, items) {
+                    if (items.length > 0) {
+                        const d = data[items[0].index];
+                        loadMilestoneBurnupChart('/data/insights/milestone-burnup?o=' + d.org + '&r=' + d.repo + '&n=' + d.number);
+                    }
+                },
+                plugins: {
+                    legend: { display: false },
+                    tooltip: {
+                        callbacks: {
+                            label: function (item) {
+                                const d = data[item.dataIndex];
+                                return milestoneStatusLabels[d.status] + ': ' + d.closed_issues + ' of ' +
+                                    (d.open_issues + d.closed_issues) + ' closed (' + d.percent_complete.toFixed(1) + '%)';
+                            },
+                            footer: function (items) {
+                                const d = data[items[0].dataIndex];
+                                const lines = [];
+                                if (d.due_on) lines.push('Due: ' + d.due_on.substring(0, 10));
+                                if (d.projected_on) lines.push('Projected: ' + d.projected_on);
+                                lines.push('Closed per day: ' + d.closed_per_day.toFixed(2));
+                                return lines;
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { beginAtZero: true, max: 100, ticks: { font: { size: 14 } } },
+                    y: { ticks: { font: { size: 12 } } }
+                }
+            }
+        });
+
+        const first = data.find(function (d) { return d.state === 'open'; }) || data[0];
+        if (first) {
+            loadMilestoneBurnupChart('/data/insights/milestone-burnup?o=' + first.org + '&r=' + first.repo + '&n=' + first.number);
+        }
+    });
+}
+
+function loadMilestoneBurnupChart(url) {
+    $.get(url, function (data) {
+        $("#milestone-burnup-title").text(data.org + '/' + data.repo + ': ' + data.title +
+            (data.due_on ? ' (due ' + data.due_on + ')' : ''));
+        const datasets = [{
+            label: 'Scope',
+            data: data.scope,
+            borderColor: colors[5],
+            backgroundColor: colors[5],
+            stepped: true,
+            pointRadius: 0
+        }, {
+            label: 'Done',
+            data: data.done,
+            borderColor: colors[1],
+            backgroundColor: colors[1],
+            fill: true,
+            stepped: true,
+            pointRadius: 0
+        }];
+        if (data.ideal) {
+            datasets.push({
+                label: 'Ideal',
+                data: data.ideal,
+                borderColor: colors[0],
+                borderDash: [6, 4],
+                fill: false,
+                pointRadius: 0
+            });
+        }
+        if (milestoneBurnupChart) milestoneBurnupChart.destroy();
+        milestoneBurnupChart = new Chart($("#milestone-burnup-chart")[0].getContext("2d"), {
+            type: 'line',
+            data: { labels: data.dates, datasets: datasets },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                interaction: { mode: 'index', intersect: false },
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { ticks: { font: { size: 14 }, maxTicksLimit: 12 } },
+                    y: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Items' } }
+                }
+            }
+        });
+    });
+}
+
+function loadProjectStatusChart(url) {
+    $.get(url, function (data) {
+        const statuses = [];
+        data.forEach(function (p) {
+            p.statuses.forEach(function (s) {
+                if (statuses.indexOf(s) === -1) statuses.push(s);
+            });
+        });
+        if (projectStatusChart) projectStatusChart.destroy();
+        projectStatusChart = new Chart($("#project-status-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (p) { return p.repo + ': ' + p.title; }),
+                datasets: statuses.map(function (s, i) {
+                    return {
+                        label: s || 'No Status',
+                        data: data.map(function (p) {
+                            const j = p.statuses.indexOf(s);
+                            return j === -1 ? 0 : p.counts[j];
+                        }),
+                        backgroundColor: s ? colors[i % 5] : colors[5],
+                        borderWidth: 1
+                    };
+                })
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { stacked: true, beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } },
+                    y: { stacked: true, ticks: { font: { size: 12 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadBacklogAgingChart(url) {
+    $.get(url, function (data) {
+        if (data.total > 0) {
+            const silent = data.no_response.reduce(function (a, b) { return a + b; }, 0);
+            $("#backlog-counts").text('Open: ' + data.total + ' / Without maintainer response: ' + silent);
+        } else {
+            $("#backlog-counts").text('');
+        }
+        if (backlogAgingChart) backlogAgingChart.destroy();
+        backlogAgingChart = new Chart($("#backlog-aging-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.buckets,
+                datasets: [{
+                    label: 'Issues',
+                    data: data.issues,
+                    backgroundColor: colors[0],
+                    borderWidth: 1,
+                    stack: 'open',
+                    order: 2
+                }, {
+                    label: 'PRs',
+                    data: data.prs,
+                    backgroundColor: colors[4],
+                    borderWidth: 1,
+                    stack: 'open',
+                    order: 2
+                }, {
+                    label: 'No Maintainer Response',
+                    type: 'line',
+                    data: data.no_response,
+                    borderColor: colors[3],
+                    borderWidth: 3,
+                    fill: false,
+                    order: 1,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const i = items[0].dataIndex;
+                                return data.labels
+                                    .filter(function (l) { return l.counts[i] > 0; })
+                                    .slice(0, 5)
+                                    .map(function (l) { return l.label + ': ' + l.counts[i]; });
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Open Items' } }
+                }
+            }
+        });
+    });
+}
+
+function loadBacklogHistoryChart(url) {
+    $.get(url, function (data) {
+        if (backlogHistoryChart) backlogHistoryChart.destroy();
+        backlogHistoryChart = new Chart($("#backlog-history-chart")[0].getContext("2d"), {
+            type: 'line',
+            data: {
+                labels: data.dates,
+                datasets: [{
+                    label: 'Issues',
+                    data: data.issues,
+                    borderColor: colors[0],
+                    borderWidth: 3,
+                    fill: false,
+                    tension: 0.3
+                }, {
+                    label: 'PRs',
+                    data: data.prs,
+                    borderColor: colors[4],
+                    borderWidth: 3,
+                    fill: false,
+                    tension: 0.3
+                }, {
+                    label: 'Aged (90d+)',
+                    data: data.aged,
+                    borderColor: colors[2],
+                    borderWidth: 2,
+                    borderDash: [5, 5],
+                    fill: false,
+                    tension: 0.3
+                }, {
+                    label: 'No Maintainer Response',
+                    data: data.no_response,
+                    borderColor: colors[3],
+                    borderWidth: 2,
+                    borderDash: [5, 5],
+                    fill: false,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Open Items' } }
+                }
+            }
+        });
+    });
+}
+
+function loadIssueResolutionChart(url) {
+    $.get(url, function (data) {
+        if (issueResolutionChart) issueResolutionChart.destroy();
+        const other = data.closed.map(function (c, i) {
+            return c - data.fixed[i] - data.declined[i];
+        });
+        issueResolutionChart = new Chart($("#issue-resolution-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'Fixed by PR',
+                    data: data.fixed,
+                    backgroundColor: colors[1],
+                    borderWidth: 1,
+                    stack: 'closed',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Stale / Won\'t Fix',
+                    data: data.declined,
+                    backgroundColor: colors[3],
+                    borderWidth: 1,
+                    stack: 'closed',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Other',
+                    data: other,
+                    backgroundColor: colors[5],
+                    borderWidth: 1,
+                    stack: 'closed',
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Fixed by External',
+                    type: 'line',
+                    data: data.external,
+                    borderColor: colors[4],
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y',
+                    order: 1,
+                    tension: 0.3
+                }, {
+                    label: 'Fixed %',
+                    type: 'line',
+                    data: data.fixed_rate,
+                    borderColor: colors[0],
+                    borderWidth: 3,
+                    borderDash: [5, 5],
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Closed Issues' } },
+                    y1: { beginAtZero: true, max: 100, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { font: { size: 14 }, callback: function(v) { return v + '%'; } },
+                        title: { display: true, text: 'Fixed by PR' } }
+                }
+            }
+        });
+    });
+}
+
+function loadLabelDwellChart(url) {
+    $.get(url, function (data) {
+        if (labelDwellChart) labelDwellChart.destroy();
+        if (!data || data.length === 0) {
+            return;
+        }
+        labelDwellChart = new Chart($("#label-dwell-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.map(function (l) { return l.label; }),
+                datasets: [{
+                    label: 'Avg Hours',
+                    data: data.map(function (l) { return l.avg_hours; }),
+                    backgroundColor: colors[2],
+                    borderWidth: 0,
+                    barPercentage: 0.6,
+                    categoryPercentage: 0.8
+                }]
+            },
+            options: {
+                indexAxis: 'y',
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { display: false },
+                    tooltip: {
+                        callbacks: {
+                            afterLabel: function (ctx) {
+                                const l = data[ctx.dataIndex];
+                                return 'Removed: ' + l.removed + ' / Still applied: ' + l.active;
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { beginAtZero: true, ticks: { font: { size: 14 } },
+                        title: { display: true, text: 'Avg Hours' } },
+                    y: { ticks: { font: { size: 14 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadReviewRequestLatencyChart(url) {
+    $.get(url, function (data) {
+        if (reviewRequestLatencyChart) reviewRequestLatencyChart.destroy();
+        reviewRequestLatencyChart = new Chart($("#review-request-latency-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: durationLabel('Hours'),
+                    data: durationField(data, 'avg_hours', 'p50_hours'),
+                    backgroundColor: colors[4],
+                    borderWidth: 1,
+                    yAxisID: 'y',
+                    order: 2
+                }, {
+                    label: 'Count',
+                    type: 'line',
+                    data: data.count,
+                    borderColor: colors[5],
+                    borderWidth: 3,
+                    fill: false,
+                    yAxisID: 'y1',
+                    order: 1,
+                    tension: 0.3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, position: 'left', ticks: { font: { size: 14 } },
+                        title: { display: true, text: durationLabel('Hours') } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Count' } }
+                }
+            }
+        });
+    });
+}
+
+function loadChangeFailureRateChart(url) {
+    $.get(url, function (data) {
+        if (changeFailureRateChart) changeFailureRateChart.destroy();
+        changeFailureRateChart = new Chart($("#change-failure-rate-chart")[0].getContext("2d"), {
+            type: 'line',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'Failure Rate %',
+                    data: data.rate,
+                    borderColor: colors[3],
+                    backgroundColor: colors[3] + '33',
+                    fill: true,
+                    tension: 0.3,
+                    pointRadius: 3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                onClick: function (e, items) {
+                    if (items.length > 0) {
+                        const k = data.months[items[0].index];
+                        window.open(url.replace('change-failure-rate', 'change-failures') + '&k=' + encodeURIComponent(k), '_blank');
+                    }
+                },
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const i = items[0].dataIndex;
+                                return 'Failures: ' + data.failures[i] + ' / Releases: ' + data.deployments[i] + ' (click to list)';
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, ticks: { font: { size: 14 },
+                        callback: function(v) { return v + '%'; } },
+                        title: { display: true, text: 'Failure Rate' } }
+                }
+            }
+        });
+    });
+}
+
+function loadPRSizeChart(url) {
+    $.get(url, function (data) {
+        if (!data.months || data.months.length === 0) {
+            return;
+        }
+        if (prSizeChart) prSizeChart.destroy();
+        prSizeChart = new Chart($("#pr-size-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'S (<50)',
+                    data: data.small,
+                    backgroundColor: colors[1]
+                }, {
+                    label: 'M (50-250)',
+                    data: data.medium,
+                    backgroundColor: colors[0]
+                }, {
+                    label: 'L (250-1K)',
+                    data: data.large,
+                    backgroundColor: colors[2]
+                }, {
+                    label: 'XL (>1K)',
+                    data: data.xlarge,
+                    backgroundColor: colors[3]
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadContributorFunnelChart(url) {
+    $.get(url, function (data) {
+        if (contributorFunnelChart) contributorFunnelChart.destroy();
+        contributorFunnelChart = new Chart($("#contributor-funnel-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'First Comment',
+                    data: data.first_comment,
+                    backgroundColor: colors[0]
+                }, {
+                    label: 'First PR',
+                    data: data.first_pr,
+                    backgroundColor: colors[1]
+                }, {
+                    label: 'First Merge',
+                    data: data.first_merge,
+                    backgroundColor: colors[4]
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } } }
+                }
+            }
+        });
+    });
+}
+
+function loadContributorProfileChart(url) {
+    $.get(url, function (data) {
+        if (contributorProfileChart) contributorProfileChart.destroy();
+        if (data && data.reputation != null) {
+            $("#contributor-score").text('Reputation Score: ' + data.reputation.toFixed(2));
+        } else {
+            $("#contributor-score").text('');
+        }
+        if (!data || !data.metrics) return;
+
+        var barColor = colors[0];
+        var avgColor = barColor.replace('rgb', 'rgba').replace(')', ', 0.35)');
+        if (barColor.startsWith('#')) {
+            var r = parseInt(barColor.slice(1,3), 16);
+            var g = parseInt(barColor.slice(3,5), 16);
+            var b = parseInt(barColor.slice(5,7), 16);
+            avgColor = 'rgba(' + r + ',' + g + ',' + b + ',0.35)';
+            barColor = 'rgba(' + r + ',' + g + ',' + b + ',1)';
+        }
+
+        contributorProfileChart = new Chart($("#contributor-profile-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.metrics,
+                datasets: [
+                    {
+                        label: 'Contributor',
+                        data: data.values,
+                        backgroundColor: barColor,
+                        borderRadius: 3
+                    },
                     {
                         label: 'Average',
                         data: data.averages.map(function(v) { return Math.round(v * 10) / 10; }),
@@ -2173,6 +3649,170 @@ function initContributorSearch(q) {
     });
 }
 
+// factorMemberLines lists factor members with their share of events.
+function factorMemberLines(label, members) {
+    if (!members || members.length === 0) return [];
+    return [label + ': ' + members.map(m => m.name + ' (' + m.share.toFixed(0) + '%)').join(', ')];
+}
+
+// factorChangeLines lists the members that joined or left a factor set.
+function factorChangeLines(label, joined, left) {
+    var lines = [];
+    if (joined && joined.length) lines.push(label + ' joined: ' + joined.join(', '));
+    if (left && left.length) lines.push(label + ' left: ' + left.join(', '));
+    return lines;
+}
+
+function loadFactorTrendChart(url) {
+    $.get(url, function (data) {
+        if (factorTrendChart) factorTrendChart.destroy();
+        factorTrendChart = new Chart($("#factor-trend-chart")[0].getContext("2d"), {
+            type: 'line',
+            data: {
+                labels: data.months,
+                datasets: [{
+                    label: 'Bus Factor',
+                    data: data.bus_factor,
+                    borderColor: colors[0],
+                    backgroundColor: colors[0] + '33',
+                    tension: 0.3,
+                    pointRadius: 3
+                }, {
+                    label: 'Pony Factor',
+                    data: data.pony_factor,
+                    borderColor: colors[3],
+                    backgroundColor: colors[3] + '33',
+                    tension: 0.3,
+                    pointRadius: 3
+                }]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                interaction: { mode: 'index', intersect: false },
+                plugins: {
+                    legend: { display: true },
+                    tooltip: {
+                        callbacks: {
+                            footer: function (items) {
+                                const p = data.periods[items[0].dataIndex];
+                                if (!p) return '';
+                                return [].concat(
+                                    factorMemberLines('Developers', p.developers),
+                                    factorChangeLines('Developers', p.developers_joined, p.developers_left),
+                                    factorMemberLines('Entities', p.entities),
+                                    factorChangeLines('Entities', p.entities_joined, p.entities_left)
+                                );
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: { ticks: { font: { size: 14 } } },
+                    y: { beginAtZero: true, ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Members (' + data.window + '-bucket window)' } }
+                }
+            }
+        });
+    });
+}
+
+var lifecycleStages = ['none', 'casual', 'regular', 'core', 'lapsed'];
+
+function loadLifecycleMatrix(transitions) {
+    var $thead = $("#lifecycle-matrix thead");
+    var $tbody = $("#lifecycle-matrix tbody");
+    $thead.empty();
+    $tbody.empty();
+    var $head = $('<tr></tr>').append($('<th></th>').text('From \u2192 To'));
+    $.each(lifecycleStages, function (i, to) {
+        $head.append($('<th class="num"></th>').text(to));
+    });
+    $thead.append($head);
+    $.each(lifecycleStages, function (i, from) {
+        var $row = $('<tr></tr>').append($('<td></td>').text(from));
+        $.each(lifecycleStages, function (j, to) {
+            var n = (transitions[from] || {})[to] || 0;
+            $row.append($('<td class="num"></td>').text(n));
+        });
+        $tbody.append($row);
+    });
+}
+
+function loadContributorLifecycleChart(url) {
+    $.get(url, function (data) {
+        if (contributorLifecycleChart) contributorLifecycleChart.destroy();
+        const bar = function (label, values, color) {
+            return { label: label, data: values, backgroundColor: color, borderWidth: 1, stack: 'stage', order: 2 };
+        };
+        contributorLifecycleChart = new Chart($("#contributor-lifecycle-chart")[0].getContext("2d"), {
+            type: 'bar',
+            data: {
+                labels: data.months,
+                datasets: [
+                    bar('Core', data.core, colors[0]),
+                    bar('Regular', data.regular, colors[1]),
+                    bar('Casual', data.casual, colors[4]),
+                    bar('Lapsed', data.lapsed, colors[3] + '88'),
+                    {
+                        label: 'Promoted', type: 'line', data: data.promoted, borderColor: colors[2], backgroundColor: colors[2],
+                        tension: 0.3, pointRadius: 3, fill: false, yAxisID: 'y1', order: 1
+                    }
+                ]
+            },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: { legend: { display: true } },
+                scales: {
+                    x: { stacked: true, ticks: { font: { size: 14 } } },
+                    y: { stacked: true, beginAtZero: true, position: 'left', ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Contributors' } },
+                    y1: { beginAtZero: true, position: 'right', grid: { drawOnChartArea: false },
+                        ticks: { precision: 0, font: { size: 14 } },
+                        title: { display: true, text: 'Promoted' } }
+                }
+            }
+        });
+        loadLifecycleMatrix(data.transitions || {});
+        var t = data.thresholds;
+        $("#contributor-lifecycle-desc").attr('title', 'Window: ' + t.window + ' buckets; Regular: ' + t.regular + '+ events; Core: ' + t.core + '+ events');
+    });
+}
+
+function loadCohortRetentionTable(url) {
+    $.get(url, function (data) {
+        var $thead = $("#cohort-retention-table thead");
+        var $tbody = $("#cohort-retention-table tbody");
+        $thead.empty();
+        $tbody.empty();
+        var split = data.split !== '';
+        var $head = $('<tr></tr>').append($('<th></th>').text('Cohort'));
+        if (split) $head.append($('<th></th>').text(data.split === 'entity' ? 'Entity' : 'First'));
+        $head.append($('<th class="num"></th>').text('Size'));
+        for (var i = 0; i < data.months; i++) {
+            $head.append($('<th class="num"></th>').text('M' + i));
+        }
+        $thead.append($head);
+        $.each(data.rows, function (i, row) {
+            var $row = $('<tr></tr>').append($('<td></td>').text(row.cohort));
+            if (split) $row.append($('<td></td>').text(row.group || 'unaffiliated'));
+            $row.append($('<td class="num"></td>').text(row.size));
+            for (var j = 0; j < data.months; j++) {
+                var $cell = $('<td class="num"></td>');
+                if (j < row.retention.length) {
+                    var pct = row.retention[j];
+                    $cell.text(Math.round(pct) + '%')
+                        .attr('title', row.active[j] + ' of ' + row.size)
+                        .css('background-color', 'rgba(54, 162, 235, ' + (pct / 100).toFixed(2) + ')');
+                }
+                $row.append($cell);
+            }
+            $tbody.append($row);
+        });
+    });
+}
+
 function loadContributorMomentumChart(url) {
     $.get(url, function (data) {
         if (contributorMomentumChart) contributorMomentumChart.destroy();
@@ -17,7 +17,7 @@ const (
 	tokenFileName  = "github_token"
 	keyringService = "devpulse"
 	keyringUser    = "github_token"
-	scopeRepo      = "repo read:packages" // OAuth scopes for repo access and GitHub Packages
+	scopeRepo      = "repo read:org read:packages read:project" // OAuth scopes for repo access, org membership, GitHub Packages and Projects
 )
 
 var (
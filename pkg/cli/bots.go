@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+)
+
+var (
+	botUserFlag = &cli.StringFlag{
+		Name:     "user",
+		Usage:    "GitHub username",
+		Required: true,
+		Sources:  cli.EnvVars("DEVPULSE_BOT_USER"),
+	}
+
+	botAllowFlag = &cli.BoolFlag{
+		Name:    "allow",
+		Usage:   "Add to the allow list (never treated as a bot) instead of the deny list",
+		Sources: cli.EnvVars("DEVPULSE_BOT_ALLOW"),
+	}
+
+	botsCmd = &cli.Command{
+		Name:            "bots",
+		Aliases:         []string{"bot"},
+		HideHelpCommand: true,
+		Usage:           "Manage bot account classification",
+		UsageText: `devpulse bots <subcommand> [options]
+
+Examples:
+  devpulse bots add --user k8s-ci-robot          # always treat account as a bot
+  devpulse bots add --user jdoe-bot --allow      # never treat account as a bot
+  devpulse bots remove --user k8s-ci-robot       # revert to automatic detection
+  devpulse bots list                             # list allow/deny entries
+  devpulse bots detect                           # flag bots using activity heuristics`,
+		Commands: []*cli.Command{
+			{
+				Name:   "add",
+				Usage:  "Add an account to the bot deny (or allow) list",
+				Action: cmdBotsAdd,
+				Flags:  append(commonFlags, botUserFlag, botAllowFlag),
+			},
+			{
+				Name:    "remove",
+				Aliases: []string{"rm"},
+				Usage:   "Remove an account from the bot allow/deny list",
+				Action:  cmdBotsRemove,
+				Flags:   append(commonFlags, botUserFlag),
+			},
+			{
+				Name:   "list",
+				Usage:  "List the bot allow/deny list",
+				Action: cmdBotsList,
+				Flags:  commonFlags,
+			},
+			{
+				Name:   "detect",
+				Usage:  "Flag bots using comment cadence and templated title heuristics",
+				Action: cmdBotsDetect,
+				Flags:  commonFlags,
+			},
+		},
+	}
+)
+
+func cmdBotsAdd(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.SaveBotOverride(cmd.String(botUserFlag.Name), !cmd.Bool(botAllowFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to save bot override: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdBotsRemove(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	username := cmd.String(botUserFlag.Name)
+	res, err := cfg.Store.DeleteBotOverride(username)
+	if err != nil {
+		return fmt.Errorf("failed to remove bot override: %w", err)
+	}
+	if res == nil {
+		return fmt.Errorf("no bot override found for %s", username)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdBotsList(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.GetBotOverrides()
+	if err != nil {
+		return fmt.Errorf("failed to list bot overrides: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdBotsDetect(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.DetectBots()
+	if err != nil {
+		return fmt.Errorf("failed to detect bots: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
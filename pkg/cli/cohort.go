@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/urfave/cli/v3"
+)
+
+var (
+	cohortNameFlag = &cli.StringFlag{
+		Name:     "name",
+		Usage:    "Cohort name",
+		Required: true,
+		Sources:  cli.EnvVars("DEVPULSE_COHORT"),
+	}
+
+	cohortDescFlag = &cli.StringFlag{
+		Name:  "description",
+		Usage: "Cohort description",
+	}
+
+	cohortUserFlag = &cli.StringSliceFlag{
+		Name:  "user",
+		Usage: "Static member username (can be specified multiple times)",
+	}
+
+	cohortMinMergedFlag = &cli.IntFlag{
+		Name:  "min-merged-prs",
+		Usage: "Rule: include developers with at least this many merged PRs",
+	}
+
+	cohortMonthsFlag = &cli.IntFlag{
+		Name:  "months",
+		Usage: "Rule: window in months for --min-merged-prs",
+		Value: data.EventAgeMonthsDefault,
+	}
+
+	cohortEntityFlag = &cli.StringFlag{
+		Name:  "entity",
+		Usage: "Rule: include developers affiliated with this entity",
+	}
+
+	cohortCmd = &cli.Command{
+		Name:            "cohort",
+		HideHelpCommand: true,
+		Usage:           "Manage named contributor cohorts used to filter insights",
+		UsageText: `devpulse cohort <subcommand> [options]
+
+Examples:
+  devpulse cohort save --name devrel --user alice --user bob         # static list
+  devpulse cohort save --name core --min-merged-prs 10 --months 6    # dynamic rule
+  devpulse cohort save --name acme --entity ACME                     # entity rule
+  devpulse cohort list                                               # list cohorts
+  devpulse cohort show --name core                                   # list members
+  devpulse cohort delete --name core                                 # delete cohort
+  devpulse cohort refresh                                            # re-evaluate rules`,
+		Commands: []*cli.Command{
+			{
+				Name:   "save",
+				Usage:  "Create or replace a cohort",
+				Action: cmdCohortSave,
+				Flags: append(commonFlags, cohortNameFlag, cohortDescFlag, cohortUserFlag,
+					cohortMinMergedFlag, cohortMonthsFlag, cohortEntityFlag),
+			},
+			{
+				Name:    "delete",
+				Aliases: []string{"rm"},
+				Usage:   "Delete a cohort",
+				Action:  cmdCohortDelete,
+				Flags:   append(commonFlags, cohortNameFlag),
+			},
+			{
+				Name:   "list",
+				Usage:  "List cohorts",
+				Action: cmdCohortList,
+				Flags:  commonFlags,
+			},
+			{
+				Name:   "show",
+				Usage:  "Show a cohort and its members",
+				Action: cmdCohortShow,
+				Flags:  append(commonFlags, cohortNameFlag),
+			},
+			{
+				Name:   "refresh",
+				Usage:  "Re-evaluate dynamic cohort rules against current data",
+				Action: cmdCohortRefresh,
+				Flags:  commonFlags,
+			},
+		},
+	}
+)
+
+func cmdCohortSave(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	c := &data.Cohort{
+		Name:        cmd.String(cohortNameFlag.Name),
+		Description: cmd.String(cohortDescFlag.Name),
+		Members:     cmd.StringSlice(cohortUserFlag.Name),
+	}
+
+	minMerged := cmd.Int(cohortMinMergedFlag.Name)
+	entity := cmd.String(cohortEntityFlag.Name)
+	if minMerged > 0 || entity != "" {
+		c.Rules = &data.CohortRules{Entity: entity}
+		if minMerged > 0 {
+			c.Rules.MinMergedPRs = minMerged
+			c.Rules.Months = cmd.Int(cohortMonthsFlag.Name)
+		}
+	}
+
+	res, err := cfg.Store.SaveCohort(c)
+	if err != nil {
+		return fmt.Errorf("failed to save cohort: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdCohortDelete(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	name := cmd.String(cohortNameFlag.Name)
+	res, err := cfg.Store.DeleteCohort(name)
+	if err != nil {
+		return fmt.Errorf("failed to delete cohort: %w", err)
+	}
+	if res == nil {
+		return fmt.Errorf("cohort not found: %s", name)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdCohortList(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.GetCohorts()
+	if err != nil {
+		return fmt.Errorf("failed to list cohorts: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdCohortShow(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	name := cmd.String(cohortNameFlag.Name)
+	res, err := cfg.Store.GetCohort(name)
+	if err != nil {
+		return fmt.Errorf("failed to get cohort: %w", err)
+	}
+	if res == nil {
+		return fmt.Errorf("cohort not found: %s", name)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdCohortRefresh(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.RefreshCohorts()
+	if err != nil {
+		return fmt.Errorf("failed to refresh cohorts: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mchmarny/devpulse/pkg/data"
 )
@@ -17,6 +19,10 @@ const (
 	categoryOther             = "ALL OTHERS"
 	arraySelector             = "|"
 	maxRequestBodyBytes int64 = 1 << 20 // 1 MB
+
+	// factorWindowDefault is the trailing window, in buckets, of the bus
+	// and pony factor trend.
+	factorWindowDefault = 3
 )
 
 type SeriesData[T any] struct {
@@ -41,9 +47,21 @@ type insightParams struct {
 	months int
 	org    *string
 	repo   *string
+	entity *string
+	cohort *string
+	bots   bool
+	// discussions opts discussion activity into contributor metrics.
+	discussions bool
+	// granularity is the bucket size of series; from and to override the
+	// months lookback with an explicit date range.
+	granularity string
+	from        *string
+	to          *string
 }
 
-func parseInsightParams(r *http.Request) insightParams {
+// parseInsightParams parses the insights query parameters. Returns an error
+// when the from date is after the to date.
+func parseInsightParams(r *http.Request) (insightParams, error) {
 	months := queryParamInt(r, "m", data.EventAgeMonthsDefault)
 	org := r.URL.Query().Get("o")
 	repo := r.URL.Query().Get("r")
@@ -51,12 +69,47 @@ func parseInsightParams(r *http.Request) insightParams {
 		org = *orgStr
 		repo = *repoStr
 	}
-	return insightParams{months: months, org: optional(org), repo: optional(repo)}
+	from, to := queryParamDate(r, "from"), queryParamDate(r, "to")
+	if from != nil && to != nil && *from > *to {
+		return insightParams{}, fmt.Errorf("from date %s is after to date %s", *from, *to)
+	}
+	return insightParams{
+		months:      months,
+		org:         optional(org),
+		repo:        optional(repo),
+		entity:      optional(r.URL.Query().Get("e")),
+		cohort:      optional(r.URL.Query().Get("c")),
+		bots:        queryParamBool(r, "b"),
+		discussions: queryParamBool(r, "d"),
+		granularity: queryParamGranularity(r, "g"),
+		from:        from,
+		to:          to,
+	}, nil
+}
+
+// filter returns the insights filter for the parsed parameters.
+func (p insightParams) filter() *data.InsightsFilter {
+	return &data.InsightsFilter{
+		Org:                p.org,
+		Repo:               p.repo,
+		Entity:             p.entity,
+		Months:             p.months,
+		IncludeBots:        p.bots,
+		Cohort:             p.cohort,
+		IncludeDiscussions: p.discussions,
+		Granularity:        p.granularity,
+		From:               p.from,
+		To:                 p.to,
+	}
 }
 
 func minDateAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		minDate, err := store.GetMinEventDate(p.org, p.repo)
 		if err != nil {
 			slog.Error("failed to get min event date", "error", err)
@@ -189,9 +242,12 @@ func percentageAPIHandler(w http.ResponseWriter, r *http.Request, fn percentageP
 
 func eventDataAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := r.URL.Query().Get("e")
-		res, err := store.GetEventTypeSeries(p.org, p.repo, optional(entity), p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetEventTypeSeries(p.filter())
 		if err != nil {
 			slog.Error("failed to get event type series", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying event type series")
@@ -201,6 +257,47 @@ func eventDataAPIHandler(store data.Store) http.HandlerFunc {
 	}
 }
 
+func queryParamBool(r *http.Request, key string) bool {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		slog.Error("error converting query string to bool", "value", v, "error", err)
+		return false
+	}
+
+	return b
+}
+
+// queryParamGranularity returns the series granularity, defaulting to
+// monthly buckets.
+func queryParamGranularity(r *http.Request, key string) string {
+	v := r.URL.Query().Get(key)
+	if !data.Contains(data.Granularities, v) {
+		return data.GranularityMonth
+	}
+	return v
+}
+
+// queryParamDate returns the value of a YYYY-MM-DD date parameter, or nil
+// when it is missing or invalid.
+func queryParamDate(r *http.Request, key string) *string {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil
+	}
+
+	if _, err := time.Parse(time.DateOnly, v); err != nil {
+		slog.Error("error converting query string to date", "value", v, "error", err)
+		return nil
+	}
+
+	return &v
+}
+
 func queryParamInt(r *http.Request, key string, def int) int {
 	v := r.URL.Query().Get(key)
 	if v == "" {
@@ -242,12 +339,18 @@ func eventSearchAPIHandler(store data.Store) http.HandlerFunc {
 				eType = data.EventTypePR
 			case "PR-Review":
 				eType = data.EventTypePRReview
+			case "PR-Review-Comment":
+				eType = data.EventTypePRReviewComment
 			case "Issue":
 				eType = data.EventTypeIssue
 			case "Issue-Comment":
 				eType = data.EventTypeIssueComment
 			case "Fork":
 				eType = data.EventTypeFork
+			case "Discussion":
+				eType = data.EventTypeDiscussion
+			case "Discussion-Comment":
+				eType = data.EventTypeDiscussionComment
 			default:
 				eType = ""
 			}
@@ -271,9 +374,12 @@ func eventSearchAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsSummaryAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := r.URL.Query().Get("e")
-		res, err := store.GetInsightsSummary(p.org, p.repo, optional(entity), p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetInsightsSummary(p.filter())
 		if err != nil {
 			slog.Error("failed to get insights summary", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying insights summary")
@@ -285,9 +391,12 @@ func insightsSummaryAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsDailyActivityAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := r.URL.Query().Get("e")
-		res, err := store.GetDailyActivity(p.org, p.repo, optional(entity), p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetDailyActivity(p.filter())
 		if err != nil {
 			slog.Error("failed to get daily activity", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying daily activity")
@@ -299,9 +408,12 @@ func insightsDailyActivityAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsRetentionAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetContributorRetention(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetContributorRetention(p.filter())
 		if err != nil {
 			slog.Error("failed to get contributor retention", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying contributor retention")
@@ -313,9 +425,12 @@ func insightsRetentionAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsPRRatioAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetPRReviewRatio(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetPRReviewRatio(p.filter())
 		if err != nil {
 			slog.Error("failed to get PR review ratio", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying PR review ratio")
@@ -344,9 +459,26 @@ func entityDevelopersAPIHandler(store data.Store) http.HandlerFunc {
 	}
 }
 
+func cohortsAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := store.GetCohorts()
+		if err != nil {
+			slog.Error("failed to get cohorts", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying cohorts")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
 func insightsRepoMetaAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		res, err := store.GetRepoMetas(p.org, p.repo)
 		if err != nil {
 			slog.Error("failed to get repo metadata", "error", err)
@@ -359,8 +491,12 @@ func insightsRepoMetaAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsRepoOverviewAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		res, err := store.GetRepoOverview(p.org, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetRepoOverview(p.filter())
 		if err != nil {
 			slog.Error("failed to get repo overview", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying repo overview")
@@ -372,8 +508,12 @@ func insightsRepoOverviewAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsRepoMetricHistoryAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		res, err := store.GetRepoMetricHistory(p.org, p.repo, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetRepoMetricHistory(p.filter())
 		if err != nil {
 			slog.Error("failed to get repo metric history", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying repo metric history")
@@ -385,9 +525,12 @@ func insightsRepoMetricHistoryAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsReleaseCadenceAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetReleaseCadence(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReleaseCadence(p.filter())
 		if err != nil {
 			slog.Error("failed to get release cadence", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying release cadence")
@@ -399,8 +542,12 @@ func insightsReleaseCadenceAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsReleaseDownloadsAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		res, err := store.GetReleaseDownloads(p.org, p.repo, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReleaseDownloads(p.filter())
 		if err != nil {
 			slog.Error("failed to get release downloads", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying release downloads")
@@ -412,8 +559,12 @@ func insightsReleaseDownloadsAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsReleaseDownloadsByTagAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		res, err := store.GetReleaseDownloadsByTag(p.org, p.repo, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReleaseDownloadsByTag(p.filter())
 		if err != nil {
 			slog.Error("failed to get release downloads by tag", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying release downloads by tag")
@@ -425,8 +576,12 @@ func insightsReleaseDownloadsByTagAPIHandler(store data.Store) http.HandlerFunc
 
 func insightsContainerActivityAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		res, err := store.GetContainerActivity(p.org, p.repo, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetContainerActivity(p.filter())
 		if err != nil {
 			slog.Error("failed to get container activity", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying container activity")
@@ -438,9 +593,12 @@ func insightsContainerActivityAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsTimeToMergeAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetTimeToMerge(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetTimeToMerge(p.filter())
 		if err != nil {
 			slog.Error("failed to get time to merge", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying time to merge")
@@ -452,9 +610,12 @@ func insightsTimeToMergeAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsTimeToCloseAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetTimeToClose(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetTimeToClose(p.filter())
 		if err != nil {
 			slog.Error("failed to get time to close", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying time to close")
@@ -466,9 +627,12 @@ func insightsTimeToCloseAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsTimeToRestoreAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetTimeToRestoreBugs(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetTimeToRestoreBugs(p.filter())
 		if err != nil {
 			slog.Error("failed to get time to restore", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying time to restore")
@@ -480,9 +644,12 @@ func insightsTimeToRestoreAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsChangeFailureRateAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetChangeFailureRate(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetChangeFailureRate(p.filter())
 		if err != nil {
 			slog.Error("failed to get change failure rate", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying change failure rate")
@@ -492,11 +659,52 @@ func insightsChangeFailureRateAPIHandler(store data.Store) http.HandlerFunc {
 	}
 }
 
+// insightsChangeFailuresAPIHandler lists the issues and PRs counted as
+// failed changes in a bucket (k) of the change failure rate.
+func insightsChangeFailuresAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetChangeFailures(p.filter(), r.URL.Query().Get("k"))
+		if err != nil {
+			slog.Error("failed to get change failures", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying change failures")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// insightsDORAAPIHandler returns the DORA scorecard of the period with the
+// trend versus the previous period.
+func insightsDORAAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetDORASummary(p.filter())
+		if err != nil {
+			slog.Error("failed to get dora summary", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying dora summary")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
 func insightsReviewLatencyAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetReviewLatency(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReviewLatency(p.filter())
 		if err != nil {
 			slog.Error("failed to get review latency", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying review latency")
@@ -506,11 +714,410 @@ func insightsReviewLatencyAPIHandler(store data.Store) http.HandlerFunc {
 	}
 }
 
+func insightsReviewDepthAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReviewDepth(p.filter())
+		if err != nil {
+			slog.Error("failed to get review depth", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying review depth")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsApproverConcentrationAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetApproverConcentration(p.filter())
+		if err != nil {
+			slog.Error("failed to get approver concentration", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying approver concentration")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsTimeToTriageAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetTimeToTriage(p.filter())
+		if err != nil {
+			slog.Error("failed to get time to triage", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying time to triage")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsReopenRateAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReopenRate(p.filter())
+		if err != nil {
+			slog.Error("failed to get reopen rate", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying reopen rate")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsLabelDwellAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetLabelDwell(p.filter())
+		if err != nil {
+			slog.Error("failed to get label dwell", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying label dwell")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsReviewRequestLatencyAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReviewRequestLatency(p.filter())
+		if err != nil {
+			slog.Error("failed to get review request latency", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying review request latency")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsIssueResolutionAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetIssueResolution(p.filter())
+		if err != nil {
+			slog.Error("failed to get issue resolution", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying issue resolution")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsIssueFixLeadTimeAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetIssueFixLeadTime(p.filter())
+		if err != nil {
+			slog.Error("failed to get issue fix lead time", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying issue fix lead time")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsPRLifecycleAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		staleDays := queryParamInt(r, "stale", data.PRStaleDaysDefault)
+		res, err := store.GetPRLifecycle(p.filter(), staleDays)
+		if err != nil {
+			slog.Error("failed to get PR lifecycle", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying PR lifecycle")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsBacklogAgingAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetBacklogAging(p.org, p.repo, p.entity)
+		if err != nil {
+			slog.Error("failed to get backlog aging", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying backlog aging")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsBacklogHistoryAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetBacklogHistory(p.filter())
+		if err != nil {
+			slog.Error("failed to get backlog history", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying backlog history")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// insightsAnomaliesAPIHandler returns the anomalies recorded during import
+// for the org and repo in the period, optionally of one metric.
+func insightsAnomaliesAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		metric := r.URL.Query().Get("metric")
+		if metric != "" && !data.Contains(data.AnomalyMetrics, metric) {
+			writeError(w, http.StatusBadRequest, "invalid metric")
+			return
+		}
+
+		res, err := store.GetAnomalies(p.filter(), metric)
+		if err != nil {
+			slog.Error("failed to get anomalies", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying anomalies")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsActivityHeatmapAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetActivityHeatmap(p.filter())
+		if err != nil {
+			slog.Error("failed to get activity heatmap", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying activity heatmap")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsOffHoursAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetOffHoursShare(p.filter())
+		if err != nil {
+			slog.Error("failed to get off-hours share", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying off-hours share")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsMostWantedAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		limit := queryParamInt(r, "n", 0)
+		res, err := store.GetMostWanted(p.filter(), limit)
+		if err != nil {
+			slog.Error("failed to get most wanted issues", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying most wanted issues")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsDiscussionsAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetDiscussionSeries(p.filter())
+		if err != nil {
+			slog.Error("failed to get discussion series", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying discussion series")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsTopAnswerersAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		limit := queryParamInt(r, "n", 0)
+		res, err := store.GetTopAnswerers(p.filter(), limit)
+		if err != nil {
+			slog.Error("failed to get top answerers", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying top answerers")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsExternalShareAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetExternalContributionShare(p.filter())
+		if err != nil {
+			slog.Error("failed to get external contribution share", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying external contribution share")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// insightsEntityDiversityAPIHandler returns the monthly entity diversity
+// indexes; xu=true leaves out developers without an entity.
+func insightsEntityDiversityAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetEntityDiversity(p.filter(), queryParamBool(r, "xu"))
+		if err != nil {
+			slog.Error("failed to get entity diversity", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying entity diversity")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsMilestonesAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetMilestones(p.filter())
+		if err != nil {
+			slog.Error("failed to get milestones", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying milestones")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsMilestoneBurnupAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		number := queryParamInt(r, "n", 0)
+		if p.org == nil || p.repo == nil || number <= 0 {
+			writeError(w, http.StatusBadRequest, "org (o), repo (r) and milestone number (n) parameters are required")
+			return
+		}
+		res, err := store.GetMilestoneBurnup(*p.org, *p.repo, number)
+		if err != nil {
+			slog.Error("failed to get milestone burn-up", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying milestone burn-up")
+			return
+		}
+		if res == nil {
+			writeError(w, http.StatusNotFound, "milestone not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsProjectStatusAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetProjectStatus(p.org, p.repo)
+		if err != nil {
+			slog.Error("failed to get project status", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying project status")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
 func insightsPRSizeAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetPRSizeDistribution(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetPRSizeDistribution(p.filter())
 		if err != nil {
 			slog.Error("failed to get PR size distribution", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying PR size distribution")
@@ -522,9 +1129,12 @@ func insightsPRSizeAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsContributorMomentumAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetContributorMomentum(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetContributorMomentum(p.filter())
 		if err != nil {
 			slog.Error("failed to get contributor momentum", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying contributor momentum")
@@ -534,11 +1144,107 @@ func insightsContributorMomentumAPIHandler(store data.Store) http.HandlerFunc {
 	}
 }
 
+// insightsFactorTrendAPIHandler returns the bus and pony factor per bucket
+// over a trailing window (w) of buckets.
+func insightsFactorTrendAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetFactorTrend(p.filter(), queryParamInt(r, "w", factorWindowDefault))
+		if err != nil {
+			slog.Error("failed to get factor trend", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying factor trend")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// insightsContributorLifecycleAPIHandler returns contributor lifecycle
+// stages per bucket. The trailing window (lw) and the regular (lr) and core
+// (lc) event thresholds default to data.DefaultLifecycleThresholds.
+func insightsContributorLifecycleAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		t := &data.LifecycleThresholds{
+			Window:  queryParamInt(r, "lw", data.LifecycleWindowDefault),
+			Regular: queryParamInt(r, "lr", data.LifecycleRegularDefault),
+			Core:    queryParamInt(r, "lc", data.LifecycleCoreDefault),
+		}
+		if err := t.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, err := store.GetContributorLifecycle(p.filter(), t)
+		if err != nil {
+			slog.Error("failed to get contributor lifecycle", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying contributor lifecycle")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func insightsCohortRetentionAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		split := r.URL.Query().Get("split")
+		if !data.Contains(data.RetentionSplits, split) {
+			writeError(w, http.StatusBadRequest, "invalid split, must be entity or type")
+			return
+		}
+
+		res, err := store.GetCohortRetention(p.filter(), split)
+		if err != nil {
+			slog.Error("failed to get cohort retention", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying cohort retention")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// insightsContributorsAtRiskAPIHandler lists the contributors whose
+// activity in the last 30 days fell well below their baseline in the m
+// months before the last 60 days.
+func insightsContributorsAtRiskAPIHandler(store data.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		limit := queryParamInt(r, "n", 0)
+		res, err := store.GetContributorsAtRisk(p.filter(), limit)
+		if err != nil {
+			slog.Error("failed to get contributors at risk", "error", err)
+			writeError(w, http.StatusInternalServerError, "error querying contributors at risk")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
 func insightsContributorFunnelAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetContributorFunnel(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetContributorFunnel(p.filter())
 		if err != nil {
 			slog.Error("failed to get contributor funnel", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying contributor funnel")
@@ -550,14 +1256,17 @@ func insightsContributorFunnelAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsContributorProfileAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		username := r.URL.Query().Get("u")
 		if username == "" {
 			writeError(w, http.StatusBadRequest, "username parameter (u) is required")
 			return
 		}
-		res, err := store.GetContributorProfile(username, p.org, p.repo, entity, p.months)
+		res, err := store.GetContributorProfile(username, p.filter())
 		if err != nil {
 			slog.Error("failed to get contributor profile", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying contributor profile")
@@ -569,7 +1278,11 @@ func insightsContributorProfileAPIHandler(store data.Store) http.HandlerFunc {
 
 func developerSearchAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		q := r.URL.Query().Get("q")
 		if q == "" {
 			writeError(w, http.StatusBadRequest, "query parameter (q) is required")
@@ -587,9 +1300,12 @@ func developerSearchAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsTimeToFirstResponseAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetTimeToFirstResponse(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetTimeToFirstResponse(p.filter())
 		if err != nil {
 			slog.Error("failed to get time to first response", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying time to first response")
@@ -601,9 +1317,12 @@ func insightsTimeToFirstResponseAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsIssueRatioAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetIssueOpenCloseRatio(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetIssueOpenCloseRatio(p.filter())
 		if err != nil {
 			slog.Error("failed to get issue open/close ratio", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying issue open/close ratio")
@@ -615,9 +1334,12 @@ func insightsIssueRatioAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsForksAndActivityAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetForksAndActivity(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetForksAndActivity(p.filter())
 		if err != nil {
 			slog.Error("failed to get forks and activity", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying forks and activity")
@@ -629,9 +1351,12 @@ func insightsForksAndActivityAPIHandler(store data.Store) http.HandlerFunc {
 
 func insightsReputationAPIHandler(store data.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		p := parseInsightParams(r)
-		entity := optional(r.URL.Query().Get("e"))
-		res, err := store.GetReputationDistribution(p.org, p.repo, entity, p.months)
+		p, err := parseInsightParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := store.GetReputationDistribution(p.filter())
 		if err != nil {
 			slog.Error("failed to get reputation distribution", "error", err)
 			writeError(w, http.StatusInternalServerError, "error querying reputation distribution")
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/urfave/cli/v3"
+)
+
+var (
+	dryRunFlag = &cli.BoolFlag{
+		Name:    "dry-run",
+		Usage:   "Preview changes without applying them",
+		Sources: cli.EnvVars("DEVPULSE_DRY_RUN"),
+	}
+
+	entityCmd = &cli.Command{
+		Name:            "entity",
+		HideHelpCommand: true,
+		Usage:           "Manage developer entity (company) normalization",
+		UsageText: `devpulse entity <subcommand> [options]
+
+Examples:
+  devpulse entity normalize --dry-run               # preview how entities would be regrouped
+  devpulse entity normalize                         # apply normalization rules
+  devpulse entity rules --format yaml               # print the effective rules`,
+		Commands: []*cli.Command{
+			{
+				Name:   "normalize",
+				Usage:  "Apply entity normalization rules to existing developers",
+				Action: cmdEntityNormalize,
+				Flags:  append(commonFlags, dryRunFlag),
+			},
+			{
+				Name:   "rules",
+				Usage:  fmt.Sprintf("Print the effective normalization rules (customize via %s in the data dir)", data.EntityRulesFileName),
+				Action: cmdEntityRules,
+				Flags:  commonFlags,
+			},
+		},
+	}
+)
+
+func cmdEntityNormalize(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.NormalizeEntities(cmd.Bool(dryRunFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to normalize entities: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdEntityRules(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+
+	path := filepath.Join(filepath.Dir(cmd.String(dbFilePathFlag.Name)), data.EntityRulesFileName)
+	res, err := data.LoadEntityRules(path)
+	if err != nil {
+		return fmt.Errorf("failed to load entity rules: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
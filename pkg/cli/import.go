@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"time"
 
 	"github.com/mchmarny/devpulse/pkg/data"
@@ -46,6 +47,19 @@ var (
 		Sources: cli.EnvVars("DEVPULSE_CONCURRENCY"),
 	}
 
+	affiliationsOfflineFlag = &cli.BoolFlag{
+		Name:    "affiliations-offline",
+		Usage:   "Use only locally cached CNCF affiliation files (no download)",
+		Sources: cli.EnvVars("DEVPULSE_AFFILIATIONS_OFFLINE"),
+	}
+
+	affiliationsMaxAgeFlag = &cli.DurationFlag{
+		Name:    "affiliations-max-age",
+		Usage:   "How long cached CNCF affiliation files are used before revalidation",
+		Value:   sqlite.AffiliationCacheMaxAgeDefault,
+		Sources: cli.EnvVars("DEVPULSE_AFFILIATIONS_MAX_AGE"),
+	}
+
 	importCmd = &cli.Command{
 		Name:            "import",
 		Aliases:         []string{"imp"},
@@ -66,6 +80,8 @@ Examples:
 			monthsFlag,
 			freshFlag,
 			concurrencyFlag,
+			affiliationsOfflineFlag,
+			affiliationsMaxAgeFlag,
 			formatFlag,
 			debugFlag,
 			logJSONFlag,
@@ -80,6 +96,8 @@ type ImportResult struct {
 	Events       map[string]int                `json:"events,omitempty" yaml:"events,omitempty"`
 	Affiliations *data.AffiliationImportResult `json:"affiliations,omitempty" yaml:"affiliations,omitempty"`
 	Substituted  []*data.Substitution          `json:"substituted,omitempty" yaml:"substituted,omitempty"`
+	Bots         []*data.BotCandidate          `json:"bots,omitempty" yaml:"bots,omitempty"`
+	Cohorts      []*data.Cohort                `json:"cohorts,omitempty" yaml:"cohorts,omitempty"`
 	Reputation   *data.ReputationResult        `json:"reputation,omitempty" yaml:"reputation,omitempty"`
 }
 
@@ -105,7 +123,7 @@ func cmdImport(ctx context.Context, cmd *cli.Command) error {
 
 	// If no org specified, update all previously imported data.
 	if org == "" {
-		return cmdUpdate(ctx, cfg, token, concurrency, start)
+		return cmdUpdate(ctx, cmd, cfg, token, concurrency, start)
 	}
 
 	// At least one repo is required when org is specified
@@ -147,7 +165,7 @@ func cmdImport(ctx context.Context, cmd *cli.Command) error {
 
 	// 2. affiliations
 	slog.Info("updating affiliations")
-	a, err := importAffiliations(ctx, cfg.Store, pool.Token())
+	a, err := importAffiliations(ctx, cfg, pool.Token(), affiliationCacheOptions(cmd, cfg))
 	if err != nil {
 		slog.Error("affiliations failed", "error", err)
 	} else {
@@ -163,10 +181,28 @@ func cmdImport(ctx context.Context, cmd *cli.Command) error {
 		res.Substituted = sub
 	}
 
-	// 4. metadata + releases
+	// 4. bots
+	slog.Info("detecting bots")
+	bots, err := cfg.Store.DetectBots()
+	if err != nil {
+		slog.Error("bot detection failed", "error", err)
+	} else {
+		res.Bots = bots
+	}
+
+	// 5. cohorts
+	slog.Info("refreshing cohorts")
+	cohorts, err := cfg.Store.RefreshCohorts()
+	if err != nil {
+		slog.Error("cohort refresh failed", "error", err)
+	} else {
+		res.Cohorts = cohorts
+	}
+
+	// 6. org members, metadata, releases and milestones
 	importRepoExtras(ctx, cfg.Store, token, org, repos)
 
-	// 5. reputation (shallow — local DB only, no API calls)
+	// 7. reputation (shallow — local DB only, no API calls)
 	orgPtr := &org
 	slog.Info("computing reputation")
 	repResult, repErr := cfg.Store.ImportReputation(orgPtr, nil)
@@ -176,6 +212,18 @@ func cmdImport(ctx context.Context, cmd *cli.Command) error {
 		res.Reputation = repResult
 	}
 
+	// 8. backlog snapshot
+	slog.Info("recording backlog snapshot")
+	if _, err := cfg.Store.SnapshotBacklog(orgPtr, nil); err != nil {
+		slog.Error("backlog snapshot failed", "error", err)
+	}
+
+	// 9. anomalies
+	slog.Info("detecting anomalies")
+	if _, err := cfg.Store.DetectAnomalies(orgPtr, nil); err != nil {
+		slog.Error("anomaly detection failed", "error", err)
+	}
+
 	res.Duration = time.Since(start).String()
 
 	if err := encode(res); err != nil {
@@ -185,7 +233,7 @@ func cmdImport(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
-func cmdUpdate(ctx context.Context, cfg *appConfig, token string, concurrency int, start time.Time) error {
+func cmdUpdate(ctx context.Context, cmd *cli.Command, cfg *appConfig, token string, concurrency int, start time.Time) error {
 	slog.Info("updating all previously imported data", "concurrency", concurrency)
 
 	pool := ghutil.NewTokenPool(token)
@@ -195,7 +243,7 @@ func cmdUpdate(ctx context.Context, cfg *appConfig, token string, concurrency in
 	}
 
 	slog.Info("updating affiliations")
-	a, err := importAffiliations(ctx, cfg.Store, pool.Token())
+	a, err := importAffiliations(ctx, cfg, pool.Token(), affiliationCacheOptions(cmd, cfg))
 	if err != nil {
 		slog.Error("affiliations failed", "error", err)
 	}
@@ -206,6 +254,18 @@ func cmdUpdate(ctx context.Context, cfg *appConfig, token string, concurrency in
 		slog.Error("substitutions failed", "error", err)
 	}
 
+	slog.Info("detecting bots")
+	bots, err := cfg.Store.DetectBots()
+	if err != nil {
+		slog.Error("bot detection failed", "error", err)
+	}
+
+	slog.Info("refreshing cohorts")
+	cohorts, err := cfg.Store.RefreshCohorts()
+	if err != nil {
+		slog.Error("cohort refresh failed", "error", err)
+	}
+
 	slog.Info("updating metadata")
 	if metaErr := cfg.Store.ImportAllRepoMeta(ctx, token); metaErr != nil {
 		slog.Error("metadata failed", "error", metaErr)
@@ -226,16 +286,38 @@ func cmdUpdate(ctx context.Context, cfg *appConfig, token string, concurrency in
 		slog.Error("container versions failed", "error", cvErr)
 	}
 
+	slog.Info("updating org members")
+	if memErr := cfg.Store.ImportAllOrgMembers(ctx, token); memErr != nil {
+		slog.Error("org members failed", "error", memErr)
+	}
+
+	slog.Info("updating milestones")
+	if msErr := cfg.Store.ImportAllMilestones(ctx, token); msErr != nil {
+		slog.Error("milestones failed", "error", msErr)
+	}
+
 	slog.Info("computing reputation")
 	repResult, repErr := cfg.Store.ImportReputation(nil, nil)
 	if repErr != nil {
 		slog.Error("reputation failed", "error", repErr)
 	}
 
+	slog.Info("recording backlog snapshot")
+	if _, err := cfg.Store.SnapshotBacklog(nil, nil); err != nil {
+		slog.Error("backlog snapshot failed", "error", err)
+	}
+
+	slog.Info("detecting anomalies")
+	if _, err := cfg.Store.DetectAnomalies(nil, nil); err != nil {
+		slog.Error("anomaly detection failed", "error", err)
+	}
+
 	res := &ImportResult{
 		Events:       m,
 		Affiliations: a,
 		Substituted:  sub,
+		Bots:         bots,
+		Cohorts:      cohorts,
 		Reputation:   repResult,
 		Duration:     time.Since(start).String(),
 	}
@@ -248,6 +330,10 @@ func cmdUpdate(ctx context.Context, cfg *appConfig, token string, concurrency in
 }
 
 func importRepoExtras(ctx context.Context, store data.Store, token, org string, repos []string) {
+	if _, err := store.ImportOrgMembers(ctx, token, org); err != nil {
+		slog.Error("failed to import org members", "org", org, "error", err)
+	}
+
 	for _, r := range repos {
 		slog.Info("updating extras", "repo", org+"/"+r)
 
@@ -263,16 +349,28 @@ func importRepoExtras(ctx context.Context, store data.Store, token, org string,
 		if err := store.ImportContainerVersions(ctx, token, org, r); err != nil {
 			slog.Error("failed to import container versions", "org", org, "repo", r, "error", err)
 		}
+		if err := store.ImportMilestones(ctx, token, org, r); err != nil {
+			slog.Error("failed to import milestones", "org", org, "repo", r, "error", err)
+		}
 	}
 }
 
-func importAffiliations(ctx context.Context, store data.Store, token string) (*data.AffiliationImportResult, error) {
+func importAffiliations(ctx context.Context, cfg *appConfig, token string, opts *sqlite.AffiliationCacheOptions) (*data.AffiliationImportResult, error) {
 	client := net.GetOAuthClient(ctx, token)
 
-	res, err := sqlite.UpdateDevelopersWithCNCFEntityAffiliations(ctx, store, store, client)
+	res, err := sqlite.UpdateDevelopersWithCNCFEntityAffiliations(ctx, cfg.Store, cfg.Store, client, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import affiliations: %w", err)
 	}
 
 	return res, nil
 }
+
+// affiliationCacheOptions caches CNCF affiliation files next to the database.
+func affiliationCacheOptions(cmd *cli.Command, cfg *appConfig) *sqlite.AffiliationCacheOptions {
+	return &sqlite.AffiliationCacheOptions{
+		Dir:     filepath.Dir(cfg.DSN),
+		MaxAge:  cmd.Duration(affiliationsMaxAgeFlag.Name),
+		Offline: cmd.Bool(affiliationsOfflineFlag.Name),
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 const (
 	queryResultLimitDefault = 500
+	queryMonthsDefault      = 3
 )
 
 var (
@@ -58,7 +59,7 @@ var (
 
 	eventTypeFlag = &cli.StringFlag{
 		Name:    "type",
-		Usage:   "Event type (pr, issue, issue_comment, pr_review, fork)",
+		Usage:   "Event type (pr, issue, issue_comment, pr_review, pr_review_comment, fork, discussion, discussion_comment)",
 		Sources: cli.EnvVars("DEVPULSE_EVENT_TYPE"),
 	}
 
@@ -80,6 +81,19 @@ var (
 		Sources: cli.EnvVars("DEVPULSE_LABEL"),
 	}
 
+	eventMinReactionsFlag = &cli.IntFlag{
+		Name:    "min-reactions",
+		Usage:   "Minimum number of reactions on the issue or PR",
+		Sources: cli.EnvVars("DEVPULSE_MIN_REACTIONS"),
+	}
+
+	queryMonthsFlag = &cli.IntFlag{
+		Name:    "months",
+		Usage:   fmt.Sprintf("Number of months in the period (default: %d)", queryMonthsDefault),
+		Value:   queryMonthsDefault,
+		Sources: cli.EnvVars("DEVPULSE_MONTHS"),
+	}
+
 	queryLimitFlag = &cli.IntFlag{
 		Name:    "limit",
 		Usage:   fmt.Sprintf("Limits number of result returned (default: %d)", queryResultLimitDefault),
@@ -100,6 +114,9 @@ var (
 Examples:
   devpulse query events --org <ORG> --repo <REPO>                  # list events for a repo
   devpulse query events --org <ORG> --type pr --since 2025-01-01   # PRs since date
+  devpulse query backlog --org <ORG> --repo <REPO>                 # open backlog by age
+  devpulse query dora --org <ORG> --repo <REPO> --months 3         # DORA scorecard
+  devpulse query at-risk --org <ORG> --months 6                    # contributors at risk
   devpulse query developer list --org <ORG>                        # list developers
   devpulse query entity list --org <ORG>                           # list entities
   devpulse query org repos --org <ORG>                             # list org repos`,
@@ -184,6 +201,40 @@ Examples:
 					eventEntityFlag,
 					eventMentionFlag,
 					eventLabelFlag,
+					eventMinReactionsFlag,
+					queryLimitFlag,
+				),
+			},
+			{
+				Name:   "backlog",
+				Usage:  "Show currently open issues and PRs by age, label and maintainer response",
+				Action: cmdQueryBacklog,
+				Flags: append(commonFlags,
+					orgNameFlag,
+					repoNameFlag,
+					eventEntityFlag,
+				),
+			},
+			{
+				Name:   "dora",
+				Usage:  "Show the DORA keys, performance tiers and trend versus the previous period",
+				Action: cmdQueryDORA,
+				Flags: append(commonFlags,
+					orgNameFlag,
+					repoNameFlag,
+					eventEntityFlag,
+					queryMonthsFlag,
+				),
+			},
+			{
+				Name:   "at-risk",
+				Usage:  "List key contributors whose recent activity fell well below their baseline",
+				Action: cmdQueryAtRisk,
+				Flags: append(commonFlags,
+					orgNameFlag,
+					repoNameFlag,
+					eventEntityFlag,
+					queryMonthsFlag,
 					queryLimitFlag,
 				),
 			},
@@ -233,6 +284,7 @@ func cmdQueryEvents(_ context.Context, cmd *cli.Command) error {
 	etype := cmd.String(eventTypeFlag.Name)
 	mention := cmd.String(eventMentionFlag.Name)
 	label := cmd.String(eventLabelFlag.Name)
+	minReactions := cmd.Int(eventMinReactionsFlag.Name)
 
 	limit := cmd.Int(queryLimitFlag.Name)
 	if limit == 0 || limit > queryResultLimitDefault {
@@ -249,6 +301,7 @@ func cmdQueryEvents(_ context.Context, cmd *cli.Command) error {
 		"limit", limit,
 		"mention", mention,
 		"label", label,
+		"min_reactions", minReactions,
 	)
 
 	q := &data.EventSearchCriteria{
@@ -263,6 +316,9 @@ func cmdQueryEvents(_ context.Context, cmd *cli.Command) error {
 		Page:     1,
 		PageSize: limit,
 	}
+	if minReactions > 0 {
+		q.MinReactions = &minReactions
+	}
 
 	cfg := getConfig(cmd)
 
@@ -278,6 +334,96 @@ func cmdQueryEvents(_ context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+func cmdQueryBacklog(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	org := cmd.String(orgNameFlag.Name)
+	repoSlice := cmd.StringSlice(repoNameFlag.Name)
+	var repo string
+	if len(repoSlice) > 0 {
+		repo = repoSlice[0]
+	}
+	entity := cmd.String(eventEntityFlag.Name)
+
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.GetBacklogAging(optional(org), optional(repo), optional(entity))
+	if err != nil {
+		return fmt.Errorf("error getting backlog: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding: %w", err)
+	}
+
+	return nil
+}
+
+func cmdQueryDORA(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	org := cmd.String(orgNameFlag.Name)
+	repoSlice := cmd.StringSlice(repoNameFlag.Name)
+	var repo string
+	if len(repoSlice) > 0 {
+		repo = repoSlice[0]
+	}
+	months := cmd.Int(queryMonthsFlag.Name)
+	if months < 1 {
+		return fmt.Errorf("months must be positive: %d", months)
+	}
+
+	cfg := getConfig(cmd)
+
+	f := &data.InsightsFilter{
+		Org:    optional(org),
+		Repo:   optional(repo),
+		Entity: optional(cmd.String(eventEntityFlag.Name)),
+		Months: months,
+	}
+	res, err := cfg.Store.GetDORASummary(f)
+	if err != nil {
+		return fmt.Errorf("error getting dora summary: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding: %w", err)
+	}
+
+	return nil
+}
+
+func cmdQueryAtRisk(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	org := cmd.String(orgNameFlag.Name)
+	repoSlice := cmd.StringSlice(repoNameFlag.Name)
+	var repo string
+	if len(repoSlice) > 0 {
+		repo = repoSlice[0]
+	}
+	months := cmd.Int(queryMonthsFlag.Name)
+	if months < 1 {
+		return fmt.Errorf("months must be positive: %d", months)
+	}
+
+	cfg := getConfig(cmd)
+
+	f := &data.InsightsFilter{
+		Org:    optional(org),
+		Repo:   optional(repo),
+		Entity: optional(cmd.String(eventEntityFlag.Name)),
+		Months: months,
+	}
+	res, err := cfg.Store.GetContributorsAtRisk(f, cmd.Int(queryLimitFlag.Name))
+	if err != nil {
+		return fmt.Errorf("error getting contributors at risk: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding: %w", err)
+	}
+
+	return nil
+}
+
 func cmdQueryList[T any](cmd *cli.Command, flag *cli.StringFlag, fn func(string, int) ([]*T, error)) error {
 	applyFlags(cmd)
 	val := cmd.String(flag.Name)
@@ -172,6 +172,7 @@ func makeRouter(store data.Store, basePath string) *http.ServeMux {
 	mux.HandleFunc("GET /data/developer", developerDataAPIHandler(store))
 	mux.HandleFunc("POST /data/search", eventSearchAPIHandler(store))
 	mux.HandleFunc("GET /data/entity/developers", entityDevelopersAPIHandler(store))
+	mux.HandleFunc("GET /data/cohorts", cohortsAPIHandler(store))
 
 	// Insights API
 	mux.HandleFunc("GET /data/insights/summary", insightsSummaryAPIHandler(store))
@@ -182,13 +183,41 @@ func makeRouter(store data.Store, basePath string) *http.ServeMux {
 	mux.HandleFunc("GET /data/insights/time-to-close", insightsTimeToCloseAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/time-to-restore", insightsTimeToRestoreAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/review-latency", insightsReviewLatencyAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/review-depth", insightsReviewDepthAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/approver-concentration", insightsApproverConcentrationAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/time-to-triage", insightsTimeToTriageAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/reopen-rate", insightsReopenRateAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/label-dwell", insightsLabelDwellAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/review-request-latency", insightsReviewRequestLatencyAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/issue-resolution", insightsIssueResolutionAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/issue-fix-lead-time", insightsIssueFixLeadTimeAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/pr-lifecycle", insightsPRLifecycleAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/backlog-aging", insightsBacklogAgingAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/backlog-history", insightsBacklogHistoryAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/anomalies", insightsAnomaliesAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/activity-heatmap", insightsActivityHeatmapAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/off-hours", insightsOffHoursAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/most-wanted", insightsMostWantedAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/discussions", insightsDiscussionsAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/top-answerers", insightsTopAnswerersAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/external-share", insightsExternalShareAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/entity-diversity", insightsEntityDiversityAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/milestones", insightsMilestonesAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/milestone-burnup", insightsMilestoneBurnupAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/project-status", insightsProjectStatusAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/forks-and-activity", insightsForksAndActivityAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/repo-meta", insightsRepoMetaAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/repo-overview", insightsRepoOverviewAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/repo-metric-history", insightsRepoMetricHistoryAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/change-failure-rate", insightsChangeFailureRateAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/change-failures", insightsChangeFailuresAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/dora", insightsDORAAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/pr-size", insightsPRSizeAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/contributor-momentum", insightsContributorMomentumAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/factor-trend", insightsFactorTrendAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/contributor-lifecycle", insightsContributorLifecycleAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/cohort-retention", insightsCohortRetentionAPIHandler(store))
+	mux.HandleFunc("GET /data/insights/at-risk", insightsContributorsAtRiskAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/contributor-funnel", insightsContributorFunnelAPIHandler(store))
 	mux.HandleFunc("GET /data/insights/contributor-profile", insightsContributorProfileAPIHandler(store))
 	mux.HandleFunc("GET /data/developer/search", developerSearchAPIHandler(store))
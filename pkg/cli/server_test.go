@@ -80,3 +80,17 @@ func TestNoBasePathRouting(t *testing.T) {
 	mux.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestInsightsReversedRange(t *testing.T) {
+	mux := makeRouter(nil, "")
+
+	for _, path := range []string{
+		"/data/insights/repo-overview?from=2025-03-01&to=2025-01-01",
+		"/data/insights/milestones?from=2025-03-01&to=2025-01-01",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+}
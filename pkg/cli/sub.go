@@ -2,11 +2,14 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -17,17 +20,21 @@ var (
 	}
 
 	oldValFlag = &cli.StringFlag{
-		Name:     "old",
-		Usage:    "Old value",
-		Required: true,
-		Sources:  cli.EnvVars("DEVPULSE_OLD"),
+		Name:    "old",
+		Usage:   "Old value",
+		Sources: cli.EnvVars("DEVPULSE_OLD"),
 	}
 
 	newValFlag = &cli.StringFlag{
-		Name:     "new",
-		Usage:    "New value",
-		Required: true,
-		Sources:  cli.EnvVars("DEVPULSE_NEW"),
+		Name:    "new",
+		Usage:   "New value",
+		Sources: cli.EnvVars("DEVPULSE_NEW"),
+	}
+
+	subFileFlag = &cli.StringFlag{
+		Name:    "file",
+		Usage:   "Substitutions file path (JSON or YAML)",
+		Sources: cli.EnvVars("DEVPULSE_SUB_FILE"),
 	}
 
 	substituteCmd = &cli.Command{
@@ -35,9 +42,41 @@ var (
 		Aliases: []string{"sub"},
 		Usage:   "Create a global data substitution (e.g. standardize entity name)",
 		UsageText: `devpulse substitute --type entity --old "Old Corp" --new "NEW CORP"   # rename entity
-   devpulse sub --type entity --old "ACME INC" --new "ACME"              # standardize name`,
+   devpulse sub --type entity --old "ACME INC" --new "ACME"              # standardize name
+   devpulse sub --type username --old jdoe-work --new jdoe               # map account to person
+   devpulse sub list                                                     # list substitutions
+   devpulse sub remove --type entity --old "ACME INC"                    # undo substitution
+   devpulse sub export --format yaml > subs.yaml                         # export substitutions
+   devpulse sub import --file subs.yaml                                  # import and apply`,
 		HideHelpCommand: true,
 		Action:          cmdSubstitutes,
+		Commands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List substitutions and the number of records each changed",
+				Action: cmdSubList,
+				Flags:  commonFlags,
+			},
+			{
+				Name:    "remove",
+				Aliases: []string{"rm"},
+				Usage:   "Remove a substitution and restore the original values",
+				Action:  cmdSubRemove,
+				Flags:   append(commonFlags, subTypeFlag, oldValFlag),
+			},
+			{
+				Name:   "export",
+				Usage:  "Export substitutions (to stdout unless --file is set)",
+				Action: cmdSubExport,
+				Flags:  append(commonFlags, subFileFlag),
+			},
+			{
+				Name:   "import",
+				Usage:  "Import and apply substitutions from a file",
+				Action: cmdSubImport,
+				Flags:  append(commonFlags, subFileFlag),
+			},
+		},
 		Flags: []cli.Flag{
 			dbFilePathFlag,
 			subTypeFlag,
@@ -73,3 +112,119 @@ func cmdSubstitutes(_ context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+func cmdSubList(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.GetSubstitutions()
+	if err != nil {
+		return fmt.Errorf("failed to list substitutions: %w", err)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSubRemove(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	sub := cmd.String(subTypeFlag.Name)
+	old := cmd.String(oldValFlag.Name)
+
+	if sub == "" || old == "" {
+		return cli.ShowSubcommandHelp(cmd)
+	}
+
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.DeleteSub(sub, old)
+	if err != nil {
+		return fmt.Errorf("failed to remove substitution: %w", err)
+	}
+	if res == nil {
+		return fmt.Errorf("no %s substitution found for %s", sub, old)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSubExport(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	cfg := getConfig(cmd)
+
+	res, err := cfg.Store.GetSubstitutions()
+	if err != nil {
+		return fmt.Errorf("failed to list substitutions: %w", err)
+	}
+
+	for _, sub := range res {
+		sub.Records = 0
+	}
+
+	path := cmd.String(subFileFlag.Name)
+	if path == "" {
+		if err := encode(res); err != nil {
+			return fmt.Errorf("error encoding result: %w", err)
+		}
+		return nil
+	}
+
+	var b []byte
+	if outputFormat == formatYAML {
+		b, err = yaml.Marshal(res)
+	} else {
+		b, err = json.MarshalIndent(res, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding substitutions: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		return fmt.Errorf("failed to write substitutions file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func cmdSubImport(_ context.Context, cmd *cli.Command) error {
+	applyFlags(cmd)
+	path := cmd.String(subFileFlag.Name)
+	if path == "" {
+		return cli.ShowSubcommandHelp(cmd)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read substitutions file %s: %w", path, err)
+	}
+
+	// YAML is a superset of JSON so both export formats decode here.
+	subs := make([]*data.Substitution, 0)
+	if err := yaml.Unmarshal(b, &subs); err != nil {
+		return fmt.Errorf("failed to parse substitutions file %s: %w", path, err)
+	}
+
+	cfg := getConfig(cmd)
+
+	res := make([]*data.Substitution, 0, len(subs))
+	for _, sub := range subs {
+		applied, err := cfg.Store.SaveAndApplyDeveloperSub(sub.Prop, sub.Old, sub.New)
+		if err != nil {
+			return fmt.Errorf("failed to apply %s substitution %s: %w", sub.Prop, sub.Old, err)
+		}
+		res = append(res, applied)
+	}
+
+	if err := encode(res); err != nil {
+		return fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return nil
+}
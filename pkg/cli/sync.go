@@ -53,6 +53,8 @@ Examples:
 			syncConfigFlag,
 			syncOrgFlag,
 			syncRepoFlag,
+			affiliationsOfflineFlag,
+			affiliationsMaxAgeFlag,
 			debugFlag,
 			logJSONFlag,
 		},
@@ -137,7 +139,7 @@ func cmdSync(ctx context.Context, cmd *urfave.Command) error {
 	// Affiliations
 	phaseStart = time.Now()
 	slog.Info("updating affiliations")
-	if _, affErr := importAffiliations(ctx, cfg.Store, pool.Token()); affErr != nil {
+	if _, affErr := importAffiliations(ctx, cfg, pool.Token(), affiliationCacheOptions(cmd, cfg)); affErr != nil {
 		errors++
 		slog.Error("affiliations failed", "error", affErr)
 	}
@@ -151,6 +153,22 @@ func cmdSync(ctx context.Context, cmd *urfave.Command) error {
 	}
 	substitutionsSec := time.Since(phaseStart).Seconds()
 
+	// Bots
+	phaseStart = time.Now()
+	if _, botErr := cfg.Store.DetectBots(); botErr != nil {
+		errors++
+		slog.Error("bot detection failed", "error", botErr)
+	}
+	botsSec := time.Since(phaseStart).Seconds()
+
+	// Cohorts
+	phaseStart = time.Now()
+	if _, cohortErr := cfg.Store.RefreshCohorts(); cohortErr != nil {
+		errors++
+		slog.Error("cohort refresh failed", "error", cohortErr)
+	}
+	cohortsSec := time.Since(phaseStart).Seconds()
+
 	// Extras
 	phaseStart = time.Now()
 	importRepoExtras(ctx, cfg.Store, pool.Token(), target.Org, []string{target.Repo})
@@ -165,6 +183,22 @@ func cmdSync(ctx context.Context, cmd *urfave.Command) error {
 	}
 	reputationSec := time.Since(phaseStart).Seconds()
 
+	// Backlog
+	phaseStart = time.Now()
+	if _, backlogErr := cfg.Store.SnapshotBacklog(&org, &target.Repo); backlogErr != nil {
+		errors++
+		slog.Error("backlog snapshot failed", "error", backlogErr)
+	}
+	backlogSec := time.Since(phaseStart).Seconds()
+
+	// Anomalies
+	phaseStart = time.Now()
+	if _, anomalyErr := cfg.Store.DetectAnomalies(&org, &target.Repo); anomalyErr != nil {
+		errors++
+		slog.Error("anomaly detection failed", "error", anomalyErr)
+	}
+	anomaliesSec := time.Since(phaseStart).Seconds()
+
 	// Score
 	phaseStart = time.Now()
 	repo := target.Repo
@@ -192,8 +226,12 @@ func cmdSync(ctx context.Context, cmd *urfave.Command) error {
 		"import_sec", importSec,
 		"affiliations_sec", affiliationsSec,
 		"substitutions_sec", substitutionsSec,
+		"bots_sec", botsSec,
+		"cohorts_sec", cohortsSec,
 		"extras_sec", extrasSec,
 		"reputation_sec", reputationSec,
+		"backlog_sec", backlogSec,
+		"anomalies_sec", anomaliesSec,
 		"scoring_sec", scoringSec,
 	)
 
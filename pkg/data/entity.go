@@ -0,0 +1,111 @@
+package data
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EntityRulesFileName is the name of the entity normalization rules file
+	// in the data directory.
+	EntityRulesFileName string = "entity-rules.yaml"
+
+	// EntityRulesVersion is the latest supported rules file version.
+	EntityRulesVersion int = 1
+)
+
+//go:embed entity_rules.yaml
+var defaultEntityRules []byte
+
+// EntityRules defines how developer affiliations are normalized.
+type EntityRules struct {
+	Version    int                 `json:"version" yaml:"version"`
+	Rewrites   []*EntityRewrite    `json:"rewrites,omitempty" yaml:"rewrites,omitempty"`
+	Noise      []string            `json:"noise,omitempty" yaml:"noise,omitempty"`
+	Aliases    map[string][]string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Exclusions []string            `json:"exclusions,omitempty" yaml:"exclusions,omitempty"`
+}
+
+// EntityRewrite replaces matches of Pattern with Replace.
+type EntityRewrite struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Replace string `json:"replace" yaml:"replace"`
+}
+
+// EntityChange describes how normalization regroups developers.
+type EntityChange struct {
+	From       string `json:"from" yaml:"from"`
+	To         string `json:"to" yaml:"to"`
+	Developers int    `json:"developers" yaml:"developers"`
+}
+
+// DefaultEntityRules returns the built-in entity normalization rules.
+func DefaultEntityRules() *EntityRules {
+	r, err := ParseEntityRules(defaultEntityRules)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default entity rules: %v", err))
+	}
+	return r
+}
+
+// LoadEntityRules reads rules from path, falling back to the built-in rules
+// when the file does not exist.
+func LoadEntityRules(path string) (*EntityRules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultEntityRules(), nil
+		}
+		return nil, fmt.Errorf("reading entity rules %s: %w", path, err)
+	}
+
+	r, err := ParseEntityRules(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing entity rules %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ParseEntityRules parses and validates YAML (or JSON) encoded rules.
+func ParseEntityRules(b []byte) (*EntityRules, error) {
+	r := &EntityRules{}
+	if err := yaml.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("decoding entity rules: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks the rules version and rewrite patterns.
+func (r *EntityRules) Validate() error {
+	if r.Version < 1 || r.Version > EntityRulesVersion {
+		return fmt.Errorf("unsupported entity rules version: %d (supported: 1-%d)", r.Version, EntityRulesVersion)
+	}
+	for i, rw := range r.Rewrites {
+		if rw == nil || rw.Pattern == "" {
+			return fmt.Errorf("entity rewrite %d: pattern is required", i)
+		}
+		if _, err := regexp.Compile(rw.Pattern); err != nil {
+			return fmt.Errorf("entity rewrite %d: invalid pattern %q: %w", i, rw.Pattern, err)
+		}
+	}
+	for name, aliases := range r.Aliases {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("entity alias group name is required")
+		}
+		for _, a := range aliases {
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("entity alias group %s: empty alias", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultEntityRules(t *testing.T) {
+	r := DefaultEntityRules()
+	assert.Equal(t, EntityRulesVersion, r.Version)
+	assert.Contains(t, r.Aliases["GOOGLE"], "GCP")
+	assert.Contains(t, r.Noise, "LLC")
+	assert.Contains(t, r.Exclusions, "N/A")
+}
+
+func TestLoadEntityRules_Missing(t *testing.T) {
+	r, err := LoadEntityRules(filepath.Join(t.TempDir(), EntityRulesFileName))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEntityRules(), r)
+}
+
+func TestLoadEntityRules_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EntityRulesFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`version: 1
+rewrites:
+  - pattern: '^ACME\b.*'
+    replace: ACME
+aliases:
+  INITECH: [INITECH LABS]
+exclusions: [STEALTH]
+`), 0600))
+
+	r, err := LoadEntityRules(path)
+	require.NoError(t, err)
+	require.Len(t, r.Rewrites, 1)
+	assert.Equal(t, "ACME", r.Rewrites[0].Replace)
+	assert.Equal(t, []string{"INITECH LABS"}, r.Aliases["INITECH"])
+	assert.Equal(t, []string{"STEALTH"}, r.Exclusions)
+}
+
+func TestParseEntityRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no version":     `noise: [INC]`,
+		"future version": `version: 99`,
+		"bad pattern":    "version: 1\nrewrites:\n  - pattern: '('\n    replace: x",
+		"empty pattern":  "version: 1\nrewrites:\n  - replace: x",
+		"empty alias":    "version: 1\naliases:\n  ACME: ['']",
+		"not yaml":       `version: [`,
+	}
+
+	for name, in := range tests {
+		_, err := ParseEntityRules([]byte(in))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,156 @@
+package data
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FailureRulesFileName is the name of the change failure rules file in
+	// the data directory.
+	FailureRulesFileName string = "failure-rules.yaml"
+
+	// FailureRulesVersion is the latest supported rules file version.
+	FailureRulesVersion int = 1
+)
+
+//go:embed failure_rules.yaml
+var defaultFailureRules []byte
+
+// FailureRules defines which issues and PRs count as failed changes.
+type FailureRules struct {
+	Version int            `json:"version" yaml:"version"`
+	Rules   []*FailureRule `json:"rules" yaml:"rules"`
+}
+
+// FailureRule classifies the issues and PRs of the repos it applies to.
+// Org and Repo scope the rule; a rule without them is the default.
+type FailureRule struct {
+	Org           string   `json:"org,omitempty" yaml:"org,omitempty"`
+	Repo          string   `json:"repo,omitempty" yaml:"repo,omitempty"`
+	Labels        []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	TitlePatterns []string `json:"title_patterns,omitempty" yaml:"titlePatterns,omitempty"`
+	// WindowDays limits labeled issues to those opened within the given
+	// days after a release; 0 counts them regardless of releases.
+	WindowDays int `json:"window_days" yaml:"windowDays"`
+	// SeverityLabels lists severity labels from most to least severe.
+	SeverityLabels []string `json:"severity_labels,omitempty" yaml:"severityLabels,omitempty"`
+	// MinSeverity is the least severe label of SeverityLabels that counts.
+	MinSeverity string `json:"min_severity,omitempty" yaml:"minSeverity,omitempty"`
+}
+
+// Scope returns the org/repo the rule applies to, or * for the default.
+func (r *FailureRule) Scope() string {
+	switch {
+	case r.Repo != "":
+		return r.Org + "/" + r.Repo
+	case r.Org != "":
+		return r.Org
+	default:
+		return "*"
+	}
+}
+
+// DefaultFailureRules returns the built-in change failure rules.
+func DefaultFailureRules() *FailureRules {
+	r, err := ParseFailureRules(defaultFailureRules)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default failure rules: %v", err))
+	}
+	return r
+}
+
+// LoadFailureRules reads rules from path, falling back to the built-in rules
+// when the file does not exist.
+func LoadFailureRules(path string) (*FailureRules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultFailureRules(), nil
+		}
+		return nil, fmt.Errorf("reading failure rules %s: %w", path, err)
+	}
+
+	r, err := ParseFailureRules(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing failure rules %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ParseFailureRules parses and validates YAML (or JSON) encoded rules.
+func ParseFailureRules(b []byte) (*FailureRules, error) {
+	r := &FailureRules{}
+	if err := yaml.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("decoding failure rules: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks the rules version, scopes, title patterns and severity.
+func (r *FailureRules) Validate() error {
+	if r.Version < 1 || r.Version > FailureRulesVersion {
+		return fmt.Errorf("unsupported failure rules version: %d (supported: 1-%d)", r.Version, FailureRulesVersion)
+	}
+
+	scopes := make(map[string]bool, len(r.Rules))
+	for i, fr := range r.Rules {
+		if fr == nil {
+			return fmt.Errorf("failure rule %d: rule is empty", i)
+		}
+		if fr.Repo != "" && fr.Org == "" {
+			return fmt.Errorf("failure rule %d: repo %s requires an org", i, fr.Repo)
+		}
+		if scopes[fr.Scope()] {
+			return fmt.Errorf("failure rule %d: duplicate rule for %s", i, fr.Scope())
+		}
+		scopes[fr.Scope()] = true
+
+		if fr.WindowDays < 0 {
+			return fmt.Errorf("failure rule %d: windowDays must not be negative", i)
+		}
+		for _, l := range fr.Labels {
+			if strings.TrimSpace(l) == "" {
+				return fmt.Errorf("failure rule %d: empty label", i)
+			}
+		}
+		for _, p := range fr.TitlePatterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("failure rule %d: invalid title pattern %q: %w", i, p, err)
+			}
+		}
+		if fr.MinSeverity != "" && !ContainsFold(fr.SeverityLabels, fr.MinSeverity) {
+			return fmt.Errorf("failure rule %d: minSeverity %s is not one of severityLabels", i, fr.MinSeverity)
+		}
+	}
+	return nil
+}
+
+// Rule returns the most specific rule for a repo, or nil when none applies.
+func (r *FailureRules) Rule(org, repo string) *FailureRule {
+	var orgRule, defRule *FailureRule
+	for _, fr := range r.Rules {
+		switch {
+		case fr.Org == "" && fr.Repo == "":
+			defRule = fr
+		case !strings.EqualFold(fr.Org, org):
+		case strings.EqualFold(fr.Repo, repo):
+			return fr
+		case fr.Repo == "":
+			orgRule = fr
+		}
+	}
+	if orgRule != nil {
+		return orgRule
+	}
+	return defRule
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultFailureRules(t *testing.T) {
+	r := DefaultFailureRules()
+	assert.Equal(t, FailureRulesVersion, r.Version)
+	require.Len(t, r.Rules, 1)
+	assert.Contains(t, r.Rules[0].Labels, "bug")
+	assert.Equal(t, 7, r.Rules[0].WindowDays)
+	assert.Equal(t, "*", r.Rules[0].Scope())
+}
+
+func TestLoadFailureRules_Missing(t *testing.T) {
+	r, err := LoadFailureRules(filepath.Join(t.TempDir(), FailureRulesFileName))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultFailureRules(), r)
+}
+
+func TestLoadFailureRules_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FailureRulesFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`version: 1
+rules:
+  - labels: [bug]
+    windowDays: 7
+  - org: acme
+    labels: [incident]
+  - org: acme
+    repo: api
+    labels: [regression]
+    severityLabels: [sev1, sev2, sev3]
+    minSeverity: sev2
+`), 0600))
+
+	r, err := LoadFailureRules(path)
+	require.NoError(t, err)
+	require.Len(t, r.Rules, 3)
+
+	assert.Equal(t, "acme/api", r.Rule("acme", "api").Scope())
+	assert.Equal(t, "acme/api", r.Rule("ACME", "API").Scope())
+	assert.Equal(t, "acme", r.Rule("acme", "web").Scope())
+	assert.Equal(t, "*", r.Rule("other", "api").Scope())
+}
+
+func TestFailureRules_NoDefault(t *testing.T) {
+	r, err := ParseFailureRules([]byte("version: 1\nrules:\n  - org: acme\n    labels: [bug]"))
+	require.NoError(t, err)
+	assert.Nil(t, r.Rule("other", "repo"))
+	assert.NotNil(t, r.Rule("acme", "repo"))
+}
+
+func TestParseFailureRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no version":       `rules: []`,
+		"future version":   `version: 99`,
+		"bad pattern":      "version: 1\nrules:\n  - titlePatterns: ['(']",
+		"empty label":      "version: 1\nrules:\n  - labels: ['']",
+		"negative window":  "version: 1\nrules:\n  - windowDays: -1",
+		"repo without org": "version: 1\nrules:\n  - repo: api",
+		"duplicate scope":  "version: 1\nrules:\n  - org: acme\n  - org: acme",
+		"unknown severity": "version: 1\nrules:\n  - severityLabels: [sev1]\n    minSeverity: sev2",
+		"not yaml":         `version: [`,
+	}
+
+	for name, in := range tests {
+		_, err := ParseFailureRules([]byte(in))
+		assert.Error(t, err, name)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +16,17 @@ import (
 	"github.com/mchmarny/devpulse/pkg/data"
 )
 
-var usernameRegEx = regexp.MustCompile(`@([A-Za-z0-9_]+)`)
+// UserTypeBot is the GitHub account type reported for app and bot accounts.
+const UserTypeBot = "Bot"
+
+var (
+	usernameRegEx = regexp.MustCompile(`@([A-Za-z0-9_]+)`)
+
+	// closingRefRegEx matches GitHub closing keywords followed by an issue
+	// reference: #123, org/repo#123 or a full issue URL.
+	closingRefRegEx = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+` +
+		`(?:https://github\.com/([\w.-]+)/([\w.-]+)/issues/|([\w.-]+)/([\w.-]+)#|#)(\d+)\b`)
+)
 
 func MapUserToDeveloper(u *github.User) *data.Developer {
 	return &data.Developer{
@@ -25,6 +36,7 @@ func MapUserToDeveloper(u *github.User) *data.Developer {
 		AvatarURL:  Deref(u.AvatarURL),
 		ProfileURL: Deref(u.HTMLURL),
 		Entity:     Trim(u.Company),
+		IsBot:      u.GetType() == UserTypeBot,
 	}
 }
 
@@ -56,6 +68,14 @@ func ParseDate(t *time.Time) string {
 	return time.Now().UTC().Format("2006-01-02")
 }
 
+// ParseTime returns the full UTC timestamp of t, or of now when t is nil.
+func ParseTime(t *time.Time) string {
+	if t != nil {
+		return t.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return time.Now().UTC().Format("2006-01-02T15:04:05Z")
+}
+
 func RateInfo(r *github.Rate) string {
 	if r == nil {
 		return ""
@@ -149,6 +169,37 @@ func ParseUsers(body *string) []string {
 	return usernameRegEx.FindAllString(*body, -1)
 }
 
+// ParseClosingRefs returns the unique issues a PR body closes with keywords
+// like "fixes #123" or "closes org/repo#45". Short references resolve
+// against the given org and repo.
+func ParseClosingRefs(body *string, org, repo string) []*data.IssueRef {
+	r := make([]*data.IssueRef, 0)
+	if body == nil {
+		return r
+	}
+
+	seen := make(map[data.IssueRef]bool)
+	for _, m := range closingRefRegEx.FindAllStringSubmatch(*body, -1) {
+		n, err := strconv.Atoi(m[5])
+		if err != nil || n == 0 {
+			continue
+		}
+		ref := data.IssueRef{Org: org, Repo: repo, Number: n}
+		switch {
+		case m[1] != "":
+			ref.Org, ref.Repo = m[1], m[2]
+		case m[3] != "":
+			ref.Org, ref.Repo = m[3], m[4]
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		r = append(r, &ref)
+	}
+	return r
+}
+
 func MapRepo(r *github.Repository) *data.Repo {
 	return &data.Repo{
 		Name:        Trim(r.Name),
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParsingBody(t *testing.T) {
@@ -28,6 +30,12 @@ func TestParseDate(t *testing.T) {
 	assert.NotEmpty(t, ParseDate(nil))
 }
 
+func TestParseTime(t *testing.T) {
+	now := time.Date(2025, 6, 15, 10, 30, 5, 0, time.FixedZone("PDT", -7*3600))
+	assert.Equal(t, "2025-06-15T17:30:05Z", ParseTime(&now))
+	assert.NotEmpty(t, ParseTime(nil))
+}
+
 func TestTrim(t *testing.T) {
 	s := " @hello "
 	assert.Equal(t, "hello", Trim(&s))
@@ -72,6 +80,18 @@ func TestParseUsers_NilBody(t *testing.T) {
 	assert.Empty(t, ParseUsers(nil))
 }
 
+func TestParseClosingRefs(t *testing.T) {
+	body := "Fixes #12, closes other/lib#3 and Resolved: https://github.com/o/r/issues/7.\n" +
+		"See #99, prefix#5 and fixes #12 again."
+	refs := ParseClosingRefs(&body, "o", "r")
+	require.Len(t, refs, 3)
+	assert.Equal(t, data.IssueRef{Org: "o", Repo: "r", Number: 12}, *refs[0])
+	assert.Equal(t, data.IssueRef{Org: "other", Repo: "lib", Number: 3}, *refs[1])
+	assert.Equal(t, data.IssueRef{Org: "o", Repo: "r", Number: 7}, *refs[2])
+
+	assert.Empty(t, ParseClosingRefs(nil, "o", "r"))
+}
+
 func TestMapUserToDeveloper(t *testing.T) {
 	login := "testuser"
 	name := "Test User"
@@ -1,6 +1,9 @@
 package data
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 // ErrDBNotInitialized is returned when the database has not been opened.
 var ErrDBNotInitialized = errors.New("database not initialized")
@@ -17,3 +20,13 @@ func Contains[T comparable](list []T, val T) bool {
 	}
 	return false
 }
+
+// ContainsFold checks for val in list, ignoring case
+func ContainsFold(list []string, val string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, val) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package data
+
+import "fmt"
+
+// Contributor lifecycle stages. None is a contributor without activity in
+// the current or the previous window, used only in transitions.
+const (
+	LifecycleNone    string = "none"
+	LifecycleCasual  string = "casual"
+	LifecycleRegular string = "regular"
+	LifecycleCore    string = "core"
+	LifecycleLapsed  string = "lapsed"
+
+	LifecycleWindowDefault  int = 3
+	LifecycleRegularDefault int = 5
+	LifecycleCoreDefault    int = 20
+
+	// LifecycleWindowMax is the largest window, in buckets.
+	LifecycleWindowMax int = 24
+)
+
+// LifecycleStages lists the lifecycle stages from least to most engaged,
+// followed by lapsed.
+var LifecycleStages = []string{
+	LifecycleNone,
+	LifecycleCasual,
+	LifecycleRegular,
+	LifecycleCore,
+	LifecycleLapsed,
+}
+
+// LifecycleThresholds configures contributor lifecycle stages. The stage of
+// a contributor in a bucket is based on their events in the trailing Window
+// buckets ending with it: Core at or above Core events, Regular at or above
+// Regular events, Casual below that, and Lapsed when active in the window
+// before but not in this one.
+type LifecycleThresholds struct {
+	Window  int `json:"window" yaml:"window"`
+	Regular int `json:"regular" yaml:"regular"`
+	Core    int `json:"core" yaml:"core"`
+}
+
+// DefaultLifecycleThresholds returns the default lifecycle thresholds.
+func DefaultLifecycleThresholds() *LifecycleThresholds {
+	return &LifecycleThresholds{
+		Window:  LifecycleWindowDefault,
+		Regular: LifecycleRegularDefault,
+		Core:    LifecycleCoreDefault,
+	}
+}
+
+// Validate checks the window is between 1 and LifecycleWindowMax and the
+// thresholds increase.
+func (t *LifecycleThresholds) Validate() error {
+	if t.Window < 1 {
+		return fmt.Errorf("lifecycle window must be positive: %d", t.Window)
+	}
+	if t.Window > LifecycleWindowMax {
+		return fmt.Errorf("lifecycle window must be at most %d: %d", LifecycleWindowMax, t.Window)
+	}
+	if t.Regular < 2 {
+		return fmt.Errorf("lifecycle regular threshold must be at least 2: %d", t.Regular)
+	}
+	if t.Core <= t.Regular {
+		return fmt.Errorf("lifecycle core threshold %d must be above regular threshold %d", t.Core, t.Regular)
+	}
+	return nil
+}
+
+// Stage returns the active stage for the events in a window, or
+// LifecycleNone when there are none.
+func (t *LifecycleThresholds) Stage(events int) string {
+	switch {
+	case events >= t.Core:
+		return LifecycleCore
+	case events >= t.Regular:
+		return LifecycleRegular
+	case events > 0:
+		return LifecycleCasual
+	default:
+		return LifecycleNone
+	}
+}
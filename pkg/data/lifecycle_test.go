@@ -0,0 +1,32 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleThresholds_Validate(t *testing.T) {
+	assert.NoError(t, DefaultLifecycleThresholds().Validate())
+
+	tests := map[string]*LifecycleThresholds{
+		"no window":       {Window: 0, Regular: 5, Core: 20},
+		"window too long": {Window: 25, Regular: 5, Core: 20},
+		"regular too low": {Window: 3, Regular: 1, Core: 20},
+		"core not above":  {Window: 3, Regular: 5, Core: 5},
+		"core below":      {Window: 3, Regular: 5, Core: 2},
+	}
+	for name, lt := range tests {
+		assert.Error(t, lt.Validate(), name)
+	}
+}
+
+func TestLifecycleThresholds_Stage(t *testing.T) {
+	lt := &LifecycleThresholds{Window: 3, Regular: 5, Core: 20}
+	assert.Equal(t, LifecycleNone, lt.Stage(0))
+	assert.Equal(t, LifecycleCasual, lt.Stage(1))
+	assert.Equal(t, LifecycleCasual, lt.Stage(4))
+	assert.Equal(t, LifecycleRegular, lt.Stage(5))
+	assert.Equal(t, LifecycleRegular, lt.Stage(19))
+	assert.Equal(t, LifecycleCore, lt.Stage(20))
+}
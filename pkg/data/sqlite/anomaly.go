@@ -0,0 +1,477 @@
+package sqlite
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// anomalyThreshold is the absolute robust z-score at or above which a
+	// bucket is an anomaly (Iglewicz and Hoaglin).
+	anomalyThreshold = 3.5
+
+	// anomalyMADScale and anomalyMeanADScale scale the median absolute
+	// deviation, or the mean absolute deviation when the median one is
+	// zero, to the standard deviation of a normal distribution.
+	anomalyMADScale    = 0.6745
+	anomalyMeanADScale = 1.253314
+
+	// anomalyWindow is the number of previous buckets with data each bucket
+	// is compared with, of which at least anomalyMinHistory are required.
+	anomalyWindow     = 12
+	anomalyMinHistory = 6
+
+	// anomalyMinCount is the count below which neither the value nor the
+	// median of a count series is large enough to be an anomaly.
+	anomalyMinCount = 5
+
+	selectAnomalyReposSQL = `SELECT DISTINCT org, repo
+		FROM event
+		WHERE org = COALESCE(?, org)
+		  AND repo = COALESCE(?, repo)
+		ORDER BY org, repo
+	`
+
+	// selectAnomalyEventsSQL counts the events by type per bucket,
+	// excluding bots.
+	selectAnomalyEventsSQL = `SELECT substr(e.date, 1, 7) AS bucket, e.type, COUNT(*) AS cnt
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND e.date >= ?
+		  AND e.date < ?
+		  ` + developerFilterSQL + `
+		GROUP BY bucket, e.type
+	`
+
+	// selectAnomalyStarsSQL returns the stars of each repo at the end of
+	// every bucket with recorded history.
+	selectAnomalyStarsSQL = `SELECT substr(date, 1, 7) AS bucket, org, repo, MAX(stars) AS stars
+		FROM repo_metric_history
+		WHERE org = COALESCE(?, org)
+		  AND repo = COALESCE(?, repo)
+		  AND date >= ?
+		  AND date < ?
+		GROUP BY bucket, org, repo
+		ORDER BY org, repo, bucket
+	`
+
+	deleteAnomaliesSQL = `DELETE FROM anomaly WHERE org = ? AND repo = ?`
+
+	insertAnomalySQL = `INSERT INTO anomaly (org, repo, granularity, metric, period, value, median, mad, score, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	selectAnomaliesSQL = `SELECT org, repo, granularity, metric, period, value, median, mad, score, detected_at
+		FROM anomaly
+		WHERE org = IFNULL(?, '')
+		  AND repo = IFNULL(?, '')
+		  AND granularity = ?
+		  AND metric = COALESCE(?, metric)
+		  AND period >= ?
+		  AND period <= ?
+		ORDER BY period, metric
+	`
+)
+
+// anomalyGranularities are the series checked for anomalies with the
+// number of buckets of history of each.
+var anomalyGranularities = []struct {
+	granularity string
+	buckets     int
+}{
+	{data.GranularityMonth, 36},
+	{data.GranularityWeek, 52},
+}
+
+// anomalyEventTypes are the event count metrics.
+var anomalyEventTypes = []string{
+	data.EventTypePR,
+	data.EventTypePRReview,
+	data.EventTypePRReviewComment,
+	data.EventTypeIssue,
+	data.EventTypeIssueComment,
+	data.EventTypeFork,
+}
+
+// anomalySeries is a metric series aligned to bucket keys; buckets
+// without data are not valid.
+type anomalySeries struct {
+	values []float64
+	valid  []bool
+	// minValue is the value below which neither a bucket nor the median
+	// is large enough to be an anomaly.
+	minValue float64
+}
+
+func newAnomalySeries(n int, minValue float64) *anomalySeries {
+	return &anomalySeries{
+		values:   make([]float64, n),
+		valid:    make([]bool, n),
+		minValue: minValue,
+	}
+}
+
+func (a *anomalySeries) set(i int, v float64) {
+	a.values[i] = v
+	a.valid[i] = true
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return percentile(sorted, 50)
+}
+
+// robustZ returns the robust z-score of x versus the history with the
+// median and the median absolute deviation of the history, and false when
+// the history has no spread.
+func robustZ(history []float64, x float64) (z, med, mad float64, ok bool) {
+	med = median(history)
+	dev := make([]float64, len(history))
+	var sum float64
+	for i, v := range history {
+		dev[i] = math.Abs(v - med)
+		sum += dev[i]
+	}
+	mad = median(dev)
+
+	if mad > 0 {
+		return anomalyMADScale * (x - med) / mad, med, mad, true
+	}
+	if mean := sum / float64(len(dev)); mean > 0 {
+		return (x - med) / (anomalyMeanADScale * mean), med, mad, true
+	}
+	return 0, med, mad, false
+}
+
+// detectAnomalies returns the buckets of the series whose robust z-score
+// versus the previous anomalyWindow buckets with data reaches the
+// threshold.
+func detectAnomalies(keys []string, sr *anomalySeries) []*data.Anomaly {
+	list := make([]*data.Anomaly, 0)
+	history := make([]float64, 0, anomalyWindow)
+	for i, k := range keys {
+		if !sr.valid[i] {
+			continue
+		}
+		x := sr.values[i]
+		if len(history) >= anomalyMinHistory {
+			z, med, mad, ok := robustZ(history, x)
+			if ok && math.Abs(z) >= anomalyThreshold && math.Max(x, med) >= sr.minValue {
+				list = append(list, &data.Anomaly{
+					Period: k,
+					Value:  x,
+					Median: med,
+					MAD:    mad,
+					Score:  math.Round(z*100) / 100,
+				})
+			}
+		}
+		history = append(history, x)
+		if len(history) > anomalyWindow {
+			history = history[1:]
+		}
+	}
+	return list
+}
+
+// DetectAnomalies checks the monthly and weekly metric series of each repo
+// matching the optional org and repo, of their orgs and across all orgs,
+// and replaces the recorded anomalies of each. Only complete buckets are
+// checked, with the default filters (no bots, no discussions). Returns the
+// number of anomalies recorded.
+func (s *Store) DetectAnomalies(org, repo *string) (int, error) {
+	if s.db == nil {
+		return 0, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectAnomalyReposSQL, org, repo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query anomaly repos: %w", err)
+	}
+
+	type scope struct{ org, repo string }
+	scopes := make([]scope, 0)
+	orgs := make(map[string]bool)
+	for rows.Next() {
+		var sc scope
+		if err := rows.Scan(&sc.org, &sc.repo); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan anomaly repo: %w", err)
+		}
+		scopes = append(scopes, sc)
+		if !orgs[sc.org] {
+			orgs[sc.org] = true
+			scopes = append(scopes, scope{org: sc.org})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+	if len(scopes) > 0 {
+		scopes = append(scopes, scope{})
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	detected := make(map[scope][]*data.Anomaly)
+	for _, sc := range scopes {
+		for _, g := range anomalyGranularities {
+			br := &bucketRange{granularity: g.granularity, bounded: true, aligned: true}
+			br.end = br.bucketStart(today).AddDate(0, 0, -1)
+			br.start = br.bucketStart(br.end)
+			br.start = br.lookback(g.buckets - 1)
+
+			var o, r *string
+			if sc.org != "" {
+				o = &sc.org
+			}
+			if sc.repo != "" {
+				r = &sc.repo
+			}
+			series, err := s.getAnomalySeries(br, o, r)
+			if err != nil {
+				return 0, err
+			}
+
+			keys := br.keys()
+			for _, metric := range data.AnomalyMetrics {
+				for _, a := range detectAnomalies(keys, series[metric]) {
+					a.Org, a.Repo, a.Granularity, a.Metric = sc.org, sc.repo, g.granularity, metric
+					detected[sc] = append(detected[sc], a)
+				}
+			}
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var count int
+	detectedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, sc := range scopes {
+		if _, err = tx.Exec(deleteAnomaliesSQL, sc.org, sc.repo); err != nil {
+			rollbackTransaction(tx)
+			return 0, fmt.Errorf("failed to delete anomalies for %s/%s: %w", sc.org, sc.repo, err)
+		}
+		for _, a := range detected[sc] {
+			if _, err = tx.Exec(insertAnomalySQL, a.Org, a.Repo, a.Granularity, a.Metric, a.Period,
+				a.Value, a.Median, a.MAD, a.Score, detectedAt); err != nil {
+				rollbackTransaction(tx)
+				return 0, fmt.Errorf("failed to save anomaly for %s/%s: %w", sc.org, sc.repo, err)
+			}
+			count++
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	slog.Debug("anomalies detected", "scopes", len(scopes), "anomalies", count)
+
+	return count, nil
+}
+
+// getAnomalySeries returns the series of every anomaly metric of the org
+// and repo, when given, aligned to the buckets of the range.
+func (s *Store) getAnomalySeries(br *bucketRange, org, repo *string) (map[string]*anomalySeries, error) {
+	keys := br.keys()
+	index := make(map[string]int, len(keys))
+	for i, k := range keys {
+		index[k] = i
+	}
+
+	series := make(map[string]*anomalySeries)
+	for _, metric := range data.AnomalyMetrics {
+		minValue := float64(anomalyMinCount)
+		if metric == data.AnomalyMetricReviewLatency || metric == data.AnomalyMetricTimeToMerge {
+			minValue = 0
+		}
+		series[metric] = newAnomalySeries(len(keys), minValue)
+	}
+
+	if err := s.getAnomalyEventSeries(br, org, repo, index, series); err != nil {
+		return nil, err
+	}
+	if err := s.getAnomalyStarSeries(br, org, repo, series[data.AnomalyMetricStars]); err != nil {
+		return nil, err
+	}
+
+	f := &data.InsightsFilter{Org: org, Repo: repo, Granularity: br.granularity}
+	from, to := br.since(), br.end.Format(dateLayout)
+	f.From, f.To = &from, &to
+
+	latency, err := s.GetReviewLatency(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, k := range latency.Months {
+		if j, ok := index[k]; ok && latency.Count[i] > 0 {
+			series[data.AnomalyMetricReviewLatency].set(j, latency.P50Hours[i])
+		}
+	}
+
+	merge, err := s.GetTimeToMerge(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, k := range merge.Months {
+		if j, ok := index[k]; ok && merge.Count[i] > 0 {
+			series[data.AnomalyMetricTimeToMerge].set(j, merge.P50Days[i])
+		}
+	}
+
+	return series, nil
+}
+
+// getAnomalyEventSeries sets the event counts by type. Buckets before the
+// first event of a type are left without data so a new repo does not
+// start with a spike.
+func (s *Store) getAnomalyEventSeries(br *bucketRange, org, repo *string, index map[string]int, series map[string]*anomalySeries) error {
+	rows, err := s.db.Query(br.query(selectAnomalyEventsSQL), org, repo, br.since(), br.until(), false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query anomaly events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket, eventType string
+		var cnt int
+		if err := rows.Scan(&bucket, &eventType, &cnt); err != nil {
+			return fmt.Errorf("failed to scan anomaly events row: %w", err)
+		}
+		if i, ok := index[bucket]; ok && series[eventType] != nil {
+			series[eventType].set(i, float64(cnt))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, eventType := range anomalyEventTypes {
+		sr := series[eventType]
+		first := -1
+		for i, ok := range sr.valid {
+			if ok {
+				first = i
+				break
+			}
+		}
+		for i := first + 1; first >= 0 && i < len(sr.valid); i++ {
+			sr.valid[i] = true
+		}
+	}
+
+	return nil
+}
+
+// getAnomalyStarSeries sets the stars gained in each bucket, summed across
+// repos with recorded stars at the end of the bucket and the one before.
+func (s *Store) getAnomalyStarSeries(br *bucketRange, org, repo *string, sr *anomalySeries) error {
+	lookback := br.lookback(1)
+	keys := br.keysFrom(lookback)
+	index := make(map[string]int, len(keys))
+	for i, k := range keys {
+		index[k] = i
+	}
+	// the series starts at the second key
+	offset := len(keys) - len(sr.values)
+
+	rows, err := s.db.Query(br.query(selectAnomalyStarsSQL), org, repo, lookback.Format(dateLayout), br.until())
+	if err != nil {
+		return fmt.Errorf("failed to query anomaly stars: %w", err)
+	}
+	defer rows.Close()
+
+	prevRepo, prevIndex, prevStars := "", -1, 0
+	for rows.Next() {
+		var bucket, o, r string
+		var stars int
+		if err := rows.Scan(&bucket, &o, &r, &stars); err != nil {
+			return fmt.Errorf("failed to scan anomaly stars row: %w", err)
+		}
+		i, ok := index[bucket]
+		if !ok {
+			continue
+		}
+		if name := o + "/" + r; name != prevRepo {
+			prevRepo, prevIndex = name, -1
+		}
+		if prevIndex == i-1 && i >= offset {
+			sr.values[i-offset] += float64(stars - prevStars)
+			sr.valid[i-offset] = true
+		}
+		prevIndex, prevStars = i, stars
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+// GetAnomalies returns the recorded anomalies of the org and repo of the
+// filter, or of their totals when not set, for the buckets of the range
+// at the filter granularity (month or week), optionally of one metric.
+// Other filters do not apply.
+func (s *Store) GetAnomalies(f *data.InsightsFilter, metric string) ([]*data.Anomaly, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+	if metric != "" && !data.Contains(data.AnomalyMetrics, metric) {
+		return nil, fmt.Errorf("invalid anomaly metric: %s", metric)
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var m *string
+	if metric != "" {
+		m = &metric
+	}
+
+	rows, err := s.db.Query(selectAnomaliesSQL, f.Org, f.Repo, br.granularity, m,
+		br.key(br.start), br.key(br.end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.Anomaly, 0)
+	for rows.Next() {
+		a := &data.Anomaly{}
+		if err := rows.Scan(&a.Org, &a.Repo, &a.Granularity, &a.Metric, &a.Period,
+			&a.Value, &a.Median, &a.MAD, &a.Score, &a.DetectedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan anomaly: %w", err)
+		}
+		a.Direction = data.AnomalyDirectionUp
+		if a.Value < a.Median {
+			a.Direction = data.AnomalyDirectionDown
+		}
+		if a.Median != 0 {
+			a.Change = math.Round((a.Value-a.Median)/a.Median*1000) / 10
+		}
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
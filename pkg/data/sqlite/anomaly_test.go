@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobustZ(t *testing.T) {
+	z, med, mad, ok := robustZ([]float64{10, 12, 9, 11, 10, 10}, 30)
+	require.True(t, ok)
+	assert.InDelta(t, 10, med, 0.001)
+	assert.InDelta(t, 0.5, mad, 0.001)
+	assert.InDelta(t, 26.98, z, 0.01)
+
+	// falls back to the mean absolute deviation
+	z, _, mad, ok = robustZ([]float64{10, 10, 10, 10, 20, 10}, 30)
+	require.True(t, ok)
+	assert.Zero(t, mad)
+	assert.Greater(t, z, anomalyThreshold)
+
+	_, _, _, ok = robustZ([]float64{4, 4, 4, 4, 4, 4}, 30)
+	assert.False(t, ok)
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}
+	sr := newAnomalySeries(len(keys), anomalyMinCount)
+	for i, v := range []float64{10, 12, 9, 11, 10, 10, 30, 11, 2} {
+		sr.set(i, v)
+	}
+
+	list := detectAnomalies(keys, sr)
+	require.Len(t, list, 2)
+	assert.Equal(t, "g", list[0].Period)
+	assert.Greater(t, list[0].Score, anomalyThreshold)
+	assert.Equal(t, "i", list[1].Period)
+	assert.Less(t, list[1].Score, -anomalyThreshold)
+
+	// too little history
+	sr.valid[0], sr.valid[1] = false, false
+	list = detectAnomalies(keys, sr)
+	require.Len(t, list, 1)
+	assert.Equal(t, "i", list[0].Period)
+
+	// too small to matter
+	small := newAnomalySeries(len(keys), anomalyMinCount)
+	for i, v := range []float64{1, 2, 1, 1, 2, 1, 4, 1, 1} {
+		small.set(i, v)
+	}
+	assert.Empty(t, detectAnomalies(keys, small))
+}
+
+func TestDetectAnomalies_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.DetectAnomalies(nil, nil)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+	_, err = s.GetAnomalies(&data.InsightsFilter{Months: 6}, "")
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetAnomalies_InvalidMetric(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.GetAnomalies(&data.InsightsFilter{Months: 6}, "downloads")
+	assert.Error(t, err)
+}
+
+func TestDetectAnomalies_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('u0', 'U0'), ('u1', 'U1'), ('u2', 'U2'), ('u3', 'U3')`)
+	require.NoError(t, err)
+
+	// about 10 PRs a month for a year, then 40 in the last complete month
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := 13; m >= 1; m-- {
+		month := thisMonth.AddDate(0, -m, 0)
+		users, days := 1, 9+m%3
+		if m == 1 {
+			users, days = 4, 10
+		}
+		for u := range users {
+			for d := 1; d <= days; d++ {
+				day := month.AddDate(0, 0, d-1).Format(dateLayout)
+				_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+					VALUES ('org1', 'repo1', ?, 'pr', ?, ?, '', '')`,
+					fmt.Sprintf("u%d", u), day, fmt.Sprintf("http://p/%d/%s", u, day))
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	n, err := store.DetectAnomalies(nil, nil)
+	require.NoError(t, err)
+	assert.Positive(t, n)
+
+	// detection replaces the recorded anomalies
+	again, err := store.DetectAnomalies(strPtr("org1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, n, again)
+
+	period := thisMonth.AddDate(0, -1, 0).Format("2006-01")
+	f := &data.InsightsFilter{Org: strPtr("org1"), Repo: strPtr("repo1"), Granularity: data.GranularityMonth, Months: 3}
+	list, err := store.GetAnomalies(f, data.EventTypePR)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	a := list[0]
+	assert.Equal(t, "org1", a.Org)
+	assert.Equal(t, "repo1", a.Repo)
+	assert.Equal(t, period, a.Period)
+	assert.InDelta(t, 40, a.Value, 0.001)
+	assert.Equal(t, data.AnomalyDirectionUp, a.Direction)
+	assert.Greater(t, a.Score, anomalyThreshold)
+	assert.Positive(t, a.Change)
+	assert.NotEmpty(t, a.DetectedAt)
+
+	// org and overall totals
+	for _, f := range []*data.InsightsFilter{
+		{Org: strPtr("org1"), Granularity: data.GranularityMonth, Months: 3},
+		{Granularity: data.GranularityMonth, Months: 3},
+	} {
+		list, err = store.GetAnomalies(f, data.EventTypePR)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Empty(t, list[0].Repo)
+		assert.Equal(t, period, list[0].Period)
+	}
+
+	list, err = store.GetAnomalies(&data.InsightsFilter{Org: strPtr("org2"), Months: 3}, "")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
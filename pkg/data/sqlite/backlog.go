@@ -0,0 +1,283 @@
+package sqlite
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	backlogLabelLimit = 10
+
+	// selectOpenBacklogSQL lists currently open issues and PRs with their age
+	// in days. The state of an item is taken from its most recent row since
+	// an item updated on several days is stored once per day. An item counts
+	// as responded when a maintainer of the repo other than the author
+	// commented on or reviewed it.
+	selectOpenBacklogSQL = `WITH latest AS (
+		SELECT e.org, e.repo, e.number, e.type, e.username, e.state, e.labels, e.created_at,
+			ROW_NUMBER() OVER (PARTITION BY e.org, e.repo, e.type, e.number ORDER BY e.date DESC) AS rn
+		FROM event e
+		WHERE e.type IN ('issue', 'pr')
+		  AND e.number IS NOT NULL
+		  AND e.created_at IS NOT NULL
+		  AND (e.type = 'pr' OR e.url NOT LIKE '%/pull/%')
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+	)
+	SELECT
+		l.type,
+		julianday('now') - julianday(l.created_at) AS age_days,
+		l.labels,
+		EXISTS (
+			SELECT 1 FROM event r
+			WHERE r.org = l.org AND r.repo = l.repo AND r.number = l.number
+			  AND r.type IN ('issue_comment', 'pr_review', 'pr_review_comment')
+			  AND r.username != l.username
+			  AND (r.org, r.repo, r.username) IN (` + maintainerSQL + `)
+		) AS responded
+	FROM latest l
+	JOIN developer d ON l.username = d.username
+	WHERE l.rn = 1
+	  AND l.state = 'open'
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  ` + botExcludeSQL + `
+	`
+
+	selectBacklogReposSQL = `SELECT DISTINCT org, repo
+		FROM event
+		WHERE type IN ('issue', 'pr')
+		  AND org = COALESCE(?, org)
+		  AND repo = COALESCE(?, repo)
+		ORDER BY org, repo
+	`
+
+	upsertBacklogSnapshotSQL = `INSERT INTO backlog_snapshot (
+			org, repo, date, issues, prs, no_response,
+			age_7d, age_30d, age_90d, age_1y, age_older
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(org, repo, date) DO UPDATE SET
+			issues = excluded.issues,
+			prs = excluded.prs,
+			no_response = excluded.no_response,
+			age_7d = excluded.age_7d,
+			age_30d = excluded.age_30d,
+			age_90d = excluded.age_90d,
+			age_1y = excluded.age_1y,
+			age_older = excluded.age_older
+	`
+
+	selectBacklogHistorySQL = `SELECT date,
+			SUM(issues) AS issues,
+			SUM(prs) AS prs,
+			SUM(no_response) AS no_response,
+			SUM(age_1y + age_older) AS aged
+		FROM backlog_snapshot
+		WHERE org = COALESCE(?, org)
+		  AND repo = COALESCE(?, repo)
+		  AND date >= ?
+		  AND date < ?
+		GROUP BY date
+		ORDER BY date
+	`
+)
+
+// backlogBucket returns the index in data.BacklogBuckets for an age in days.
+func backlogBucket(days float64) int {
+	switch {
+	case days < 7:
+		return 0
+	case days < 30:
+		return 1
+	case days < 90:
+		return 2
+	case days < 365:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// GetBacklogAging returns currently open issues and PRs bucketed by age,
+// split by type, maintainer response and the most common labels.
+func (s *Store) GetBacklogAging(org, repo, entity *string) (*data.BacklogAging, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectOpenBacklogSQL, org, repo, entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open backlog: %w", err)
+	}
+	defer rows.Close()
+
+	n := len(data.BacklogBuckets)
+	res := &data.BacklogAging{
+		Buckets:    data.BacklogBuckets,
+		Issues:     make([]int, n),
+		PRs:        make([]int, n),
+		Responded:  make([]int, n),
+		NoResponse: make([]int, n),
+		Labels:     make([]*data.BacklogLabel, 0),
+	}
+
+	labels := make(map[string]*data.BacklogLabel)
+	for rows.Next() {
+		var itemType, itemLabels string
+		var age float64
+		var responded bool
+		if err := rows.Scan(&itemType, &age, &itemLabels, &responded); err != nil {
+			return nil, fmt.Errorf("failed to scan open backlog row: %w", err)
+		}
+
+		b := backlogBucket(age)
+		res.Total++
+		if itemType == data.EventTypePR {
+			res.PRs[b]++
+		} else {
+			res.Issues[b]++
+		}
+		if responded {
+			res.Responded[b]++
+		} else {
+			res.NoResponse[b]++
+		}
+
+		for _, l := range strings.Split(itemLabels, ",") {
+			if l = strings.TrimSpace(l); l == "" {
+				continue
+			}
+			bl, ok := labels[l]
+			if !ok {
+				bl = &data.BacklogLabel{Label: l, Counts: make([]int, n)}
+				labels[l] = bl
+				res.Labels = append(res.Labels, bl)
+			}
+			bl.Counts[b]++
+			bl.Total++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	sort.Slice(res.Labels, func(i, j int) bool {
+		if res.Labels[i].Total != res.Labels[j].Total {
+			return res.Labels[i].Total > res.Labels[j].Total
+		}
+		return res.Labels[i].Label < res.Labels[j].Label
+	})
+	if len(res.Labels) > backlogLabelLimit {
+		res.Labels = res.Labels[:backlogLabelLimit]
+	}
+
+	return res, nil
+}
+
+// SnapshotBacklog records today's backlog size for each repo matching the
+// optional org and repo and returns the number of repos recorded.
+func (s *Store) SnapshotBacklog(org, repo *string) (int, error) {
+	if s.db == nil {
+		return 0, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectBacklogReposSQL, org, repo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query backlog repos: %w", err)
+	}
+
+	type repoRef struct{ org, repo string }
+	repos := make([]repoRef, 0)
+	for rows.Next() {
+		var r repoRef
+		if err := rows.Scan(&r.org, &r.repo); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan backlog repo: %w", err)
+		}
+		repos = append(repos, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	date := time.Now().UTC().Format("2006-01-02")
+	for _, r := range repos {
+		a, err := s.GetBacklogAging(&r.org, &r.repo, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get backlog for %s/%s: %w", r.org, r.repo, err)
+		}
+
+		var issues, prs, noResponse int
+		ages := make([]int, len(data.BacklogBuckets))
+		for i := range a.Buckets {
+			issues += a.Issues[i]
+			prs += a.PRs[i]
+			noResponse += a.NoResponse[i]
+			ages[i] = a.Issues[i] + a.PRs[i]
+		}
+
+		if _, err := s.db.Exec(upsertBacklogSnapshotSQL, r.org, r.repo, date, issues, prs, noResponse,
+			ages[0], ages[1], ages[2], ages[3], ages[4]); err != nil {
+			return 0, fmt.Errorf("failed to save backlog snapshot for %s/%s: %w", r.org, r.repo, err)
+		}
+	}
+
+	slog.Debug("backlog snapshot", "repos", len(repos), "date", date)
+
+	return len(repos), nil
+}
+
+// GetBacklogHistory returns the daily open backlog size from the recorded
+// snapshots, summed across repos when no repo is given. Snapshots are point
+// in time values, so only the date range of the filter applies.
+func (s *Store) GetBacklogHistory(f *data.InsightsFilter) (*data.BacklogHistory, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectBacklogHistorySQL, f.Org, f.Repo, since, br.until())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backlog history: %w", err)
+	}
+	defer rows.Close()
+
+	res := &data.BacklogHistory{
+		Dates:      make([]string, 0),
+		Issues:     make([]int, 0),
+		PRs:        make([]int, 0),
+		NoResponse: make([]int, 0),
+		Aged:       make([]int, 0),
+	}
+
+	for rows.Next() {
+		var date string
+		var issues, prs, noResponse, aged int
+		if err := rows.Scan(&date, &issues, &prs, &noResponse, &aged); err != nil {
+			return nil, fmt.Errorf("failed to scan backlog history row: %w", err)
+		}
+		res.Dates = append(res.Dates, date)
+		res.Issues = append(res.Issues, issues)
+		res.PRs = append(res.PRs, prs)
+		res.NoResponse = append(res.NoResponse, noResponse)
+		res.Aged = append(res.Aged, aged)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertBacklogTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob')`)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	ago := func(d int) string { return now.AddDate(0, 0, -d).Format(time.RFC3339) }
+	day := func(d int) string { return now.AddDate(0, 0, -d).Format("2006-01-02") }
+
+	// issue 1: open 2 days, labeled bug, answered by bob (maintainer)
+	// issue 2: open 45 days, labeled bug and help wanted, no response
+	// issue 3: was open, closed on a later day
+	// PR 10: open 400 days, no response
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at) VALUES
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/1', '', 'bug', 'open', 1, ?),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/2', '', 'bug,help wanted', 'open', 2, ?),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/3', '', '', 'open', 3, ?),
+		('org1', 'repo1', 'bob', 'issue', ?, 'http://i/issues/3', '', '', 'closed', 3, ?),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://i/pull/10', '', '', 'open', 10, ?),
+		('org1', 'repo1', 'bob', 'issue_comment', ?, 'http://i/issues/1#issuecomment-1', '', '', '', 1, ?),
+		('org1', 'repo1', 'bob', 'pr_review', ?, 'http://i/pull/11#pullrequestreview-1', '', '', 'APPROVED', 11, ?)`,
+		day(2), ago(2),
+		day(45), ago(45),
+		day(20), ago(20),
+		day(10), ago(20),
+		day(400), ago(400),
+		day(1), ago(1),
+		day(5), ago(5))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+		VALUES (1, 'org1', 'repo1', 11, 'bob', 'APPROVED', ?)`, ago(5))
+	require.NoError(t, err)
+}
+
+func TestBacklogBucket(t *testing.T) {
+	assert.Equal(t, 0, backlogBucket(0))
+	assert.Equal(t, 1, backlogBucket(7))
+	assert.Equal(t, 2, backlogBucket(45))
+	assert.Equal(t, 3, backlogBucket(90))
+	assert.Equal(t, 4, backlogBucket(365))
+}
+
+func TestGetBacklogAging_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetBacklogAging(nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestGetBacklogAging_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetBacklogAging(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	assert.Len(t, res.Issues, len(data.BacklogBuckets))
+	assert.Empty(t, res.Labels)
+}
+
+func TestGetBacklogAging_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertBacklogTestData(t, store)
+
+	res, err := store.GetBacklogAging(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, []int{1, 0, 1, 0, 0}, res.Issues)
+	assert.Equal(t, []int{0, 0, 0, 0, 1}, res.PRs)
+	assert.Equal(t, []int{1, 0, 0, 0, 0}, res.Responded)
+	assert.Equal(t, []int{0, 0, 1, 0, 1}, res.NoResponse)
+
+	require.Len(t, res.Labels, 2)
+	assert.Equal(t, "bug", res.Labels[0].Label)
+	assert.Equal(t, 2, res.Labels[0].Total)
+	assert.Equal(t, "help wanted", res.Labels[1].Label)
+
+	org := "org2"
+	res, err = store.GetBacklogAging(&org, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+}
+
+func TestSnapshotBacklog_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.SnapshotBacklog(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestGetBacklogHistory_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetBacklogHistory(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetBacklogHistory_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertBacklogTestData(t, store)
+
+	n, err := store.SnapshotBacklog(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// snapshots are idempotent per day
+	_, err = store.SnapshotBacklog(nil, nil)
+	require.NoError(t, err)
+
+	res, err := store.GetBacklogHistory(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, res.Dates, 1)
+	assert.Equal(t, time.Now().UTC().Format("2006-01-02"), res.Dates[0])
+	assert.Equal(t, 2, res.Issues[0])
+	assert.Equal(t, 1, res.PRs[0])
+	assert.Equal(t, 2, res.NoResponse[0])
+	assert.Equal(t, 1, res.Aged[0])
+}
@@ -0,0 +1,365 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// botMinComments is the minimum number of comments before the
+	// comment cadence heuristic is applied to a developer.
+	botMinComments = 20
+
+	// botFastReplySeconds is the delay after an issue or PR is opened within
+	// which a comment is considered automated.
+	botFastReplySeconds = 120
+
+	// botFastReplyShare is the share of fast replies above which a developer
+	// is classified as a bot.
+	botFastReplyShare = 0.8
+
+	// botMinTitles is the minimum number of issues and PRs before the
+	// templated title heuristic is applied to a developer.
+	botMinTitles = 10
+
+	// botTemplatedTitleShare is the share of titles matching a single
+	// template above which a developer is classified as a bot.
+	botTemplatedTitleShare = 0.8
+
+	insertBotOverrideSQL = `INSERT INTO bot_override (username, is_bot, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(username) DO UPDATE SET
+			is_bot = ?,
+			updated_at = ?
+	`
+
+	selectBotOverrideSQL = `SELECT username, is_bot, updated_at
+		FROM bot_override
+		WHERE username = ?
+	`
+
+	selectBotOverridesSQL = `SELECT username, is_bot, updated_at
+		FROM bot_override
+		ORDER BY username
+	`
+
+	deleteBotOverrideSQL = `DELETE FROM bot_override WHERE username = ?`
+
+	updateDeveloperBotSQL = `UPDATE developer SET is_bot = ? WHERE username = ?`
+
+	// updateDetectedBotSQL flags a developer as a bot unless the developer
+	// is on the allow list.
+	updateDetectedBotSQL = `UPDATE developer SET is_bot = 1
+		WHERE username = ?
+		  AND NOT EXISTS (SELECT 1 FROM bot_override b WHERE b.username = developer.username)
+	`
+
+	selectCommentCadenceSQL = `SELECT
+			c.username,
+			COUNT(*) AS comments,
+			SUM(CASE WHEN (julianday(c.created_at) - julianday(p.created_at)) * 86400 <= ? THEN 1 ELSE 0 END) AS fast
+		FROM event c
+		JOIN event p ON p.org = c.org AND p.repo = c.repo AND p.number = c.number
+			AND p.type IN ('pr', 'issue')
+		JOIN developer d ON c.username = d.username
+		WHERE c.type IN ('issue_comment', 'pr_review', 'pr_review_comment')
+		  AND c.created_at IS NOT NULL
+		  AND p.created_at IS NOT NULL
+		  AND p.username != c.username
+		  ` + botExcludeSQL + `
+		  AND NOT EXISTS (SELECT 1 FROM bot_override b WHERE b.username = d.username)
+		GROUP BY c.username
+		HAVING comments >= ?
+	`
+
+	selectDeveloperTitlesSQL = `SELECT e.username, e.title
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type IN ('pr', 'issue')
+		  AND e.title IS NOT NULL
+		  AND e.title != ''
+		  ` + botExcludeSQL + `
+		  AND NOT EXISTS (SELECT 1 FROM bot_override b WHERE b.username = d.username)
+		ORDER BY e.username
+	`
+)
+
+var knownBotUsernames = map[string]bool{
+	"copilot":          true,
+	"github-copilot":   true,
+	"claude":           true,
+	"anthropic-claude": true,
+}
+
+// isBotUsername reports whether the username follows a bot naming convention.
+func isBotUsername(username string) bool {
+	u := strings.ToLower(strings.TrimSpace(username))
+	if knownBotUsernames[u] {
+		return true
+	}
+	for _, suffix := range []string{"[bot]", "-bot", "-robot", "_bot"} {
+		if strings.HasSuffix(u, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleTemplate reduces a title to its template by replacing tokens that
+// carry variable content (versions, paths, numbers) with a placeholder.
+func titleTemplate(title string) string {
+	parts := strings.Fields(strings.ToLower(title))
+	for i, p := range parts {
+		if strings.ContainsAny(p, "0123456789/.@#") {
+			parts[i] = "*"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// SaveBotOverride adds the username to the deny list (isBot) or allow list
+// (!isBot) and applies the classification to the developer.
+func (s *Store) SaveBotOverride(username string, isBot bool) (*data.BotOverride, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err = tx.Exec(insertBotOverrideSQL, username, isBot, now, isBot, now); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to save bot override for %s: %w", username, err)
+	}
+
+	if _, err = tx.Exec(updateDeveloperBotSQL, isBot, username); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to update developer %s: %w", username, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &data.BotOverride{Username: username, IsBot: isBot, UpdatedAt: now}, nil
+}
+
+// DeleteBotOverride removes the username from the allow/deny list and resets
+// the developer to the naming heuristic. The GitHub account type is applied
+// again on the next import. Returns nil if no override exists.
+func (s *Store) DeleteBotOverride(username string) (*data.BotOverride, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	o := &data.BotOverride{}
+	if err := s.db.QueryRow(selectBotOverrideSQL, username).Scan(&o.Username, &o.IsBot, &o.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bot override for %s: %w", username, err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err = tx.Exec(deleteBotOverrideSQL, username); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to delete bot override for %s: %w", username, err)
+	}
+
+	if _, err = tx.Exec(updateDeveloperBotSQL, isBotUsername(username), username); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to update developer %s: %w", username, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return o, nil
+}
+
+// GetBotOverrides returns the allow/deny list.
+func (s *Store) GetBotOverrides() ([]*data.BotOverride, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectBotOverridesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bot overrides: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.BotOverride, 0)
+	for rows.Next() {
+		o := &data.BotOverride{}
+		if err := rows.Scan(&o.Username, &o.IsBot, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan bot override row: %w", err)
+		}
+		list = append(list, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// DetectBots applies the comment cadence and templated title heuristics to
+// developers that are neither flagged as bots nor on the allow/deny list,
+// and flags the matches.
+func (s *Store) DetectBots() ([]*data.BotCandidate, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	list := make([]*data.BotCandidate, 0)
+	seen := make(map[string]bool)
+
+	cadence, err := s.detectCommentCadenceBots()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cadence {
+		seen[c.Username] = true
+		list = append(list, c)
+	}
+
+	templated, err := s.detectTemplatedTitleBots()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range templated {
+		if !seen[c.Username] {
+			list = append(list, c)
+		}
+	}
+
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, c := range list {
+		if _, err = tx.Exec(updateDetectedBotSQL, c.Username); err != nil {
+			rollbackTransaction(tx)
+			return nil, fmt.Errorf("failed to flag bot %s: %w", c.Username, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	slog.Debug("bots detected", "count", len(list))
+
+	return list, nil
+}
+
+func (s *Store) detectCommentCadenceBots() ([]*data.BotCandidate, error) {
+	rows, err := s.db.Query(selectCommentCadenceSQL, botFastReplySeconds, botMinComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment cadence: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.BotCandidate, 0)
+	for rows.Next() {
+		var username string
+		var comments, fast int
+		if err := rows.Scan(&username, &comments, &fast); err != nil {
+			return nil, fmt.Errorf("failed to scan comment cadence row: %w", err)
+		}
+		share := float64(fast) / float64(comments)
+		if share >= botFastReplyShare {
+			list = append(list, &data.BotCandidate{
+				Username: username,
+				Reason: fmt.Sprintf("%.0f%% of %d comments posted within %ds",
+					share*100, comments, botFastReplySeconds),
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+func (s *Store) detectTemplatedTitleBots() ([]*data.BotCandidate, error) {
+	rows, err := s.db.Query(selectDeveloperTitlesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query developer titles: %w", err)
+	}
+	defer rows.Close()
+
+	titles := make(map[string][]string)
+	order := make([]string, 0)
+	for rows.Next() {
+		var username, title string
+		if err := rows.Scan(&username, &title); err != nil {
+			return nil, fmt.Errorf("failed to scan developer title row: %w", err)
+		}
+		if _, ok := titles[username]; !ok {
+			order = append(order, username)
+		}
+		titles[username] = append(titles[username], title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	list := make([]*data.BotCandidate, 0)
+	for _, username := range order {
+		userTitles := titles[username]
+		if len(userTitles) < botMinTitles {
+			continue
+		}
+
+		counts := make(map[string]int)
+		var top string
+		for _, t := range userTitles {
+			tpl := titleTemplate(t)
+			counts[tpl]++
+			if counts[tpl] > counts[top] {
+				top = tpl
+			}
+		}
+
+		share := float64(counts[top]) / float64(len(userTitles))
+		if share >= botTemplatedTitleShare {
+			list = append(list, &data.BotCandidate{
+				Username: username,
+				Reason:   fmt.Sprintf("%d of %d titles match %q", counts[top], len(userTitles), top),
+			})
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBotUsername(t *testing.T) {
+	assert.True(t, isBotUsername("dependabot[bot]"))
+	assert.True(t, isBotUsername("k8s-ci-robot"))
+	assert.True(t, isBotUsername("Copilot"))
+	assert.True(t, isBotUsername("release_bot"))
+	assert.False(t, isBotUsername("abbot"))
+	assert.False(t, isBotUsername("alice"))
+}
+
+func TestTitleTemplate(t *testing.T) {
+	assert.Equal(t, "bump * from * to *", titleTemplate("Bump golang.org/x/net from 0.1.0 to 0.2.0"))
+	assert.Equal(t, titleTemplate("Bump a/b from 1 to 2"), titleTemplate("bump c/d from 3 to 4"))
+	assert.Equal(t, "fix typo in *", titleTemplate("Fix typo in README.md"))
+}
+
+func TestSaveDevelopers_BotFlag(t *testing.T) {
+	store := setupTestDB(t)
+	devs := []*data.Developer{
+		{Username: "alice"},
+		{Username: "renovate[bot]"},
+		{Username: "some-app", IsBot: true},
+	}
+	require.NoError(t, store.SaveDevelopers(devs))
+
+	for u, want := range map[string]bool{"alice": false, "renovate[bot]": true, "some-app": true} {
+		dev, err := store.GetDeveloper(u)
+		require.NoError(t, err)
+		assert.Equal(t, want, dev.IsBot, u)
+	}
+
+	// re-saving without the GitHub type must not clear the flag
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{{Username: "some-app"}}))
+	dev, err := store.GetDeveloper("some-app")
+	require.NoError(t, err)
+	assert.True(t, dev.IsBot)
+}
+
+func TestBotOverrides(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "k8s-ci-robot"},
+		{Username: "codecov-commenter"},
+	}))
+
+	_, err := store.SaveBotOverride("codecov-commenter", true)
+	require.NoError(t, err)
+	_, err = store.SaveBotOverride("k8s-ci-robot", false)
+	require.NoError(t, err)
+
+	dev, err := store.GetDeveloper("codecov-commenter")
+	require.NoError(t, err)
+	assert.True(t, dev.IsBot)
+
+	dev, err = store.GetDeveloper("k8s-ci-robot")
+	require.NoError(t, err)
+	assert.False(t, dev.IsBot)
+
+	// allow list wins over GitHub account type on re-import
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{{Username: "k8s-ci-robot", IsBot: true}}))
+	dev, err = store.GetDeveloper("k8s-ci-robot")
+	require.NoError(t, err)
+	assert.False(t, dev.IsBot)
+
+	list, err := store.GetBotOverrides()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "codecov-commenter", list[0].Username)
+
+	removed, err := store.DeleteBotOverride("k8s-ci-robot")
+	require.NoError(t, err)
+	require.NotNil(t, removed)
+	assert.False(t, removed.IsBot)
+
+	dev, err = store.GetDeveloper("k8s-ci-robot")
+	require.NoError(t, err)
+	assert.True(t, dev.IsBot)
+
+	removed, err = store.DeleteBotOverride("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, removed)
+}
+
+func TestSaveBotOverride_EmptyUsername(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.SaveBotOverride(" ", true)
+	assert.Error(t, err)
+}
+
+func TestBotOverrides_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.SaveBotOverride("u", true)
+	assert.Error(t, err)
+	_, err = s.DeleteBotOverride("u")
+	assert.Error(t, err)
+	_, err = s.GetBotOverrides()
+	assert.Error(t, err)
+	_, err = s.DetectBots()
+	assert.Error(t, err)
+}
+
+func TestDetectBots_CommentCadence(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "alice"}, {Username: "codecov-commenter"}, {Username: "bob"},
+	}))
+
+	var err error
+	base := time.Now().UTC().AddDate(0, -1, 0)
+	for i := 0; i < botMinComments; i++ {
+		opened := base.Add(time.Duration(i) * 24 * time.Hour)
+		day := opened.Format("2006-01-02")
+		_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, number, created_at)
+			VALUES ('org1', 'repo1', 'alice', 'pr', ?, 'http://a', '', '', ?, ?)`,
+			day, i+1, opened.Format(time.RFC3339))
+		require.NoError(t, err)
+		_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, number, created_at)
+			VALUES ('org1', 'repo1', 'codecov-commenter', 'issue_comment', ?, 'http://c', '', '', ?, ?)`,
+			day, i+1, opened.Add(30*time.Second).Format(time.RFC3339))
+		require.NoError(t, err)
+		_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, number, created_at)
+			VALUES ('org1', 'repo1', 'bob', 'pr_review', ?, 'http://b', '', '', ?, ?)`,
+			day, i+1, opened.Add(3*time.Hour).Format(time.RFC3339))
+		require.NoError(t, err)
+	}
+
+	list, err := store.DetectBots()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "codecov-commenter", list[0].Username)
+	assert.NotEmpty(t, list[0].Reason)
+
+	dev, err := store.GetDeveloper("codecov-commenter")
+	require.NoError(t, err)
+	assert.True(t, dev.IsBot)
+
+	// already flagged developers are not reported again
+	list, err = store.DetectBots()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestDetectBots_TemplatedTitles(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "internal-automation"}, {Username: "alice"},
+	}))
+
+	_, err := store.SaveBotOverride("alice", false)
+	require.NoError(t, err)
+
+	base := time.Now().UTC().AddDate(0, -1, 0)
+	for i := 0; i < botMinTitles; i++ {
+		day := base.AddDate(0, 0, i).Format("2006-01-02")
+		title := fmt.Sprintf("Bump github.com/foo/lib%d from 1.%d.0 to 1.%d.1", i, i, i)
+		for _, u := range []string{"internal-automation", "alice"} {
+			_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, title)
+				VALUES ('org1', 'repo1', ?, 'pr', ?, 'http://x', '', '', ?)`, u, day, title)
+			require.NoError(t, err)
+		}
+	}
+
+	list, err := store.DetectBots()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "internal-automation", list[0].Username)
+
+	dev, err := store.GetDeveloper("alice")
+	require.NoError(t, err)
+	assert.False(t, dev.IsBot)
+}
+
+func TestInsights_IncludeBots(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "alice"},
+		{Username: "ci-app", IsBot: true},
+	}))
+
+	day := time.Now().UTC().AddDate(0, 0, -3).Format("2006-01-02")
+	for _, u := range []string{"alice", "ci-app"} {
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+			VALUES ('org1', 'repo1', ?, 'pr', ?, 'http://x', '', '')`, u, day)
+		require.NoError(t, err)
+	}
+
+	summary, err := store.GetInsightsSummary(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Contributors)
+
+	summary, err = store.GetInsightsSummary(&data.InsightsFilter{Months: 6, IncludeBots: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Contributors)
+}
@@ -0,0 +1,236 @@
+package sqlite
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// bucketOverflow is the bucket of rows after the end of a bounded range.
+	// It sorts after every bucket key and is dropped when series are aligned.
+	bucketOverflow = "9999"
+)
+
+// monthBucketRegEx matches the monthly bucket expression the series queries
+// are written with, e.g. substr(e.created_at, 1, 7), of a column or of a
+// COALESCE of columns.
+var monthBucketRegEx = regexp.MustCompile(`substr\(((?:COALESCE\([^()]*\))|[a-z_]+(?:\.[a-z_]+)?), 1, 7\)`)
+
+// bucketRange is the time range and bucket granularity of an insights
+// filter.
+type bucketRange struct {
+	granularity string
+	start       time.Time
+	end         time.Time
+	// bounded is set when the filter has an explicit end date.
+	bounded bool
+	// aligned is set when series are expected to hold every bucket.
+	aligned bool
+}
+
+// newBucketRange validates the granularity and the from/to dates of the
+// filter.
+func newBucketRange(f *data.InsightsFilter) (*bucketRange, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	b := &bucketRange{
+		granularity: f.Granularity,
+		start:       today.AddDate(0, -f.Months, 0),
+		end:         today,
+		aligned:     f.Granularity != "",
+	}
+
+	if b.granularity == "" {
+		b.granularity = data.GranularityMonth
+	}
+	if !data.Contains(data.Granularities, b.granularity) {
+		return nil, fmt.Errorf("invalid granularity: %s", b.granularity)
+	}
+
+	if f.From != nil {
+		t, err := time.Parse(dateLayout, *f.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date %q: %w", *f.From, err)
+		}
+		b.start = t
+	}
+	if f.To != nil {
+		t, err := time.Parse(dateLayout, *f.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %q: %w", *f.To, err)
+		}
+		b.end = t
+		b.bounded = true
+	}
+	if b.end.Before(b.start) {
+		return nil, fmt.Errorf("from date %s is after to date %s", b.start.Format(dateLayout), b.end.Format(dateLayout))
+	}
+
+	return b, nil
+}
+
+// since returns the first day of the range.
+func (b *bucketRange) since() string {
+	return b.start.Format(dateLayout)
+}
+
+// until returns the exclusive upper bound of the range, which compares
+// after any date or timestamp when the range has no end date.
+func (b *bucketRange) until() string {
+	if !b.bounded {
+		return bucketOverflow
+	}
+	return b.end.AddDate(0, 0, 1).Format(dateLayout)
+}
+
+// expr returns the SQL bucket key of a date or timestamp column. Values
+// after a bounded range map to bucketOverflow.
+func (b *bucketRange) expr(col string) string {
+	var key string
+	switch b.granularity {
+	case data.GranularityDay:
+		key = fmt.Sprintf("substr(%s, 1, 10)", col)
+	case data.GranularityWeek:
+		key = fmt.Sprintf("strftime('%%G-W%%V', substr(%s, 1, 10))", col)
+	case data.GranularityQuarter:
+		key = fmt.Sprintf("(substr(%[1]s, 1, 4) || '-Q' || ((CAST(substr(%[1]s, 6, 2) AS INTEGER) + 2) / 3))", col)
+	default:
+		key = fmt.Sprintf("substr(%s, 1, 7)", col)
+	}
+
+	if !b.bounded {
+		return key
+	}
+	// the bound is a formatted date, safe to inline
+	return fmt.Sprintf("(CASE WHEN %s < '%s' THEN %s ELSE '%s' END)", col, b.until(), key, bucketOverflow)
+}
+
+// query rewrites the monthly bucket expressions of a series query to the
+// granularity and range.
+func (b *bucketRange) query(q string) string {
+	return monthBucketRegEx.ReplaceAllStringFunc(q, func(m string) string {
+		return b.expr(monthBucketRegEx.FindStringSubmatch(m)[1])
+	})
+}
+
+// key returns the bucket key of a day, matching expr.
+func (b *bucketRange) key(t time.Time) string {
+	switch b.granularity {
+	case data.GranularityDay:
+		return t.Format(dateLayout)
+	case data.GranularityWeek:
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", y, w)
+	case data.GranularityQuarter:
+		return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())+2)/3)
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// bucketStart returns the first day of the bucket holding t.
+func (b *bucketRange) bucketStart(t time.Time) time.Time {
+	switch b.granularity {
+	case data.GranularityDay:
+		return t
+	case data.GranularityWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case data.GranularityQuarter:
+		return time.Date(t.Year(), time.Month((int(t.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// lookback returns the first day of the bucket n buckets before the first
+// bucket of the range.
+func (b *bucketRange) lookback(n int) time.Time {
+	t := b.bucketStart(b.start)
+	for range n {
+		t = b.bucketStart(t.AddDate(0, 0, -1))
+	}
+	return t
+}
+
+// keysFrom returns the keys of every bucket from the bucket holding the
+// day through the last bucket of the range.
+func (b *bucketRange) keysFrom(day time.Time) []string {
+	keys := make([]string, 0)
+	for t := day; !t.After(b.end); t = t.AddDate(0, 0, 1) {
+		if k := b.key(t); len(keys) == 0 || keys[len(keys)-1] != k {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// keys returns the keys of every bucket of the range.
+func (b *bucketRange) keys() []string {
+	return b.keysFrom(b.start)
+}
+
+// align sets the Months field of a series struct to the buckets of the
+// range and realigns the slices parallel to it, zero-filling buckets
+// without data; structs referenced by pointer fields are realigned too.
+// Unaligned ranges keep the buckets with data and only drop rows after
+// the end of the range.
+func (b *bucketRange) align(series any) {
+	v := reflect.ValueOf(series).Elem()
+	field := v.FieldByName("Months")
+	old := field.Interface().([]string)
+
+	var keys []string
+	if b.aligned {
+		keys = b.keys()
+	} else {
+		keys = make([]string, 0, len(old))
+		for _, k := range old {
+			if k != bucketOverflow {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == len(old) {
+			return
+		}
+	}
+
+	pos := make(map[string]int, len(old))
+	for i, k := range old {
+		pos[k] = i
+	}
+
+	field.Set(reflect.ValueOf(keys))
+	alignSlices(v, pos, len(old), keys)
+}
+
+func alignSlices(v reflect.Value, pos map[string]int, size int, keys []string) {
+	for i := range v.NumField() {
+		f := v.Field(i)
+		if v.Type().Field(i).Name == "Months" || !f.CanSet() {
+			continue
+		}
+
+		switch f.Kind() {
+		case reflect.Slice:
+			if f.IsNil() || f.Len() != size {
+				continue
+			}
+			out := reflect.MakeSlice(f.Type(), len(keys), len(keys))
+			for j, k := range keys {
+				if o, ok := pos[k]; ok {
+					out.Index(j).Set(f.Index(o))
+				}
+			}
+			f.Set(out)
+		case reflect.Pointer:
+			if !f.IsNil() && f.Elem().Kind() == reflect.Struct {
+				alignSlices(f.Elem(), pos, size, keys)
+			}
+		}
+	}
+}
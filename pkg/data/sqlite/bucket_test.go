@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestNewBucketRange_Validation(t *testing.T) {
+	_, err := newBucketRange(&data.InsightsFilter{Months: 6, Granularity: "hour"})
+	require.Error(t, err)
+
+	_, err = newBucketRange(&data.InsightsFilter{Months: 6, From: strPtr("2025-13-01")})
+	require.Error(t, err)
+
+	_, err = newBucketRange(&data.InsightsFilter{From: strPtr("2025-03-01"), To: strPtr("2025-02-01")})
+	require.Error(t, err)
+
+	b, err := newBucketRange(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, data.GranularityMonth, b.granularity)
+	assert.False(t, b.aligned)
+	assert.False(t, b.bounded)
+	assert.Equal(t, bucketOverflow, b.until())
+
+	b, err = newBucketRange(&data.InsightsFilter{Granularity: data.GranularityWeek, From: strPtr("2025-01-01"), To: strPtr("2025-01-31")})
+	require.NoError(t, err)
+	assert.True(t, b.aligned)
+	assert.Equal(t, "2025-01-01", b.since())
+	assert.Equal(t, "2025-02-01", b.until())
+}
+
+func TestBucketRange_Keys(t *testing.T) {
+	tests := []struct {
+		granularity string
+		from, to    string
+		keys        []string
+	}{
+		{data.GranularityDay, "2024-02-27", "2024-03-01", []string{"2024-02-27", "2024-02-28", "2024-02-29", "2024-03-01"}},
+		// 2024-12-30 starts ISO week 1 of 2025
+		{data.GranularityWeek, "2024-12-20", "2025-01-06", []string{"2024-W51", "2024-W52", "2025-W01", "2025-W02"}},
+		{data.GranularityMonth, "2024-11-15", "2025-01-02", []string{"2024-11", "2024-12", "2025-01"}},
+		{data.GranularityQuarter, "2024-08-01", "2025-04-01", []string{"2024-Q3", "2024-Q4", "2025-Q1", "2025-Q2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.granularity, func(t *testing.T) {
+			b, err := newBucketRange(&data.InsightsFilter{Granularity: tt.granularity, From: &tt.from, To: &tt.to})
+			require.NoError(t, err)
+			assert.Equal(t, tt.keys, b.keys())
+		})
+	}
+}
+
+func TestBucketRange_Lookback(t *testing.T) {
+	b, err := newBucketRange(&data.InsightsFilter{Granularity: data.GranularityWeek, From: strPtr("2025-01-15"), To: strPtr("2025-01-31")})
+	require.NoError(t, err)
+
+	// 2025-01-15 is a Wednesday; two weeks before its Monday
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), b.lookback(2))
+
+	b.granularity = data.GranularityQuarter
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), b.lookback(1))
+}
+
+func TestBucketRange_ExprMatchesKey(t *testing.T) {
+	store := setupTestDB(t)
+
+	days := []string{"2024-12-29", "2024-12-30", "2025-01-01", "2025-03-31", "2025-04-01T10:00:00Z"}
+	for _, g := range data.Granularities {
+		b, err := newBucketRange(&data.InsightsFilter{Granularity: g, From: strPtr("2024-01-01")})
+		require.NoError(t, err)
+
+		for _, d := range days {
+			var got string
+			require.NoError(t, store.db.QueryRow(`SELECT `+b.expr("?1"), d).Scan(&got))
+
+			day, err := time.Parse(dateLayout, d[:10])
+			require.NoError(t, err)
+			assert.Equal(t, b.key(day), got, "%s %s", g, d)
+		}
+	}
+}
+
+func TestBucketRange_QueryRewrite(t *testing.T) {
+	b, err := newBucketRange(&data.InsightsFilter{Granularity: data.GranularityDay, From: strPtr("2025-01-01"), To: strPtr("2025-01-31")})
+	require.NoError(t, err)
+
+	q := b.query(`SELECT substr(e.created_at, 1, 7) AS month, substr(COALESCE(f.a, f.b), 1, 7) FROM x`)
+	assert.Equal(t, `SELECT (CASE WHEN e.created_at < '2025-02-01' THEN substr(e.created_at, 1, 10) ELSE '9999' END) AS month, `+
+		`(CASE WHEN COALESCE(f.a, f.b) < '2025-02-01' THEN substr(COALESCE(f.a, f.b), 1, 10) ELSE '9999' END) FROM x`, q)
+}
+
+func TestBucketRange_Align(t *testing.T) {
+	from, to := "2025-01-01", "2025-04-30"
+
+	b, err := newBucketRange(&data.InsightsFilter{Granularity: data.GranularityMonth, From: &from, To: &to})
+	require.NoError(t, err)
+
+	sr := &data.ExternalContributionShare{
+		Months: []string{"2025-02", "2025-04", bucketOverflow},
+		Opened: &data.ContributionBreakdown{Member: []int{2, 4, 9}},
+	}
+	b.align(sr)
+	assert.Equal(t, []string{"2025-01", "2025-02", "2025-03", "2025-04"}, sr.Months)
+	assert.Equal(t, []int{0, 2, 0, 4}, sr.Opened.Member)
+	assert.Nil(t, sr.Merged)
+
+	// unaligned ranges only drop the rows after the range
+	b.aligned = false
+	rs := &data.ReopenSeries{
+		Months: []string{"2025-02", bucketOverflow},
+		Closed: []int{3, 7},
+	}
+	b.align(rs)
+	assert.Equal(t, []string{"2025-02"}, rs.Months)
+	assert.Equal(t, []int{3}, rs.Closed)
+}
+
+func TestGetPRReviewRatio_Granularity(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice')`)
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, created_at)
+		VALUES
+		('org1', 'repo1', 'alice', 'pr', '2025-01-06', 'http://p1', '', '', 'open', '2025-01-06T00:00:00Z'),
+		('org1', 'repo1', 'alice', 'pr', '2025-01-20', 'http://p2', '', '', 'open', '2025-01-20T00:00:00Z'),
+		('org1', 'repo1', 'alice', 'pr', '2025-02-10', 'http://p3', '', '', 'open', '2025-02-10T00:00:00Z')`)
+	require.NoError(t, err)
+
+	f := &data.InsightsFilter{Granularity: data.GranularityWeek, From: strPtr("2025-01-06"), To: strPtr("2025-02-02")}
+	sr, err := store.GetPRReviewRatio(f)
+	require.NoError(t, err)
+
+	// every week of the range, zero-filled, without the PR after the range
+	assert.Equal(t, []string{"2025-W02", "2025-W03", "2025-W04", "2025-W05"}, sr.Months)
+	assert.Equal(t, []int{1, 0, 1, 0}, sr.PRs)
+}
@@ -16,13 +16,13 @@ import (
 	"github.com/mchmarny/devpulse/pkg/net"
 )
 
-const (
-	affilFileURL = "https://raw.githubusercontent.com/cncf/gitdm/master/developers_affiliations%d.txt"
-)
+// affilFileURL is a var so tests can point it at a local server.
+var affilFileURL = "https://raw.githubusercontent.com/cncf/gitdm/master/developers_affiliations%d.txt"
 
 // UpdateDevelopersWithCNCFEntityAffiliations updates the developers with the CNCF entity affiliations.
 // It accepts a data.DeveloperStore so it can be used with any Store implementation.
-func UpdateDevelopersWithCNCFEntityAffiliations(ctx context.Context, store data.DeveloperStore, entityStore data.EntityStore, client *http.Client) (*data.AffiliationImportResult, error) {
+// When opts is nil or has no Dir, the affiliation files are downloaded without caching.
+func UpdateDevelopersWithCNCFEntityAffiliations(ctx context.Context, store data.DeveloperStore, entityStore data.EntityStore, client *http.Client, opts *AffiliationCacheOptions) (*data.AffiliationImportResult, error) {
 	if client == nil {
 		return nil, fmt.Errorf("client is required")
 	}
@@ -32,7 +32,7 @@ func UpdateDevelopersWithCNCFEntityAffiliations(ctx context.Context, store data.
 		return nil, fmt.Errorf("error getting developers from db: %w", err)
 	}
 
-	cncfDevs, err := GetCNCFEntityAffiliations(ctx)
+	cncfDevs, cache, err := getCNCFEntityAffiliations(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("error getting CNCF affiliations: %w", err)
 	}
@@ -43,6 +43,12 @@ func UpdateDevelopersWithCNCFEntityAffiliations(ctx context.Context, store data.
 		CNCFDevs: len(cncfDevs),
 	}
 
+	if cache != nil {
+		res.CacheVersion = cache.version()
+		res.CacheCheckedAt = cache.CheckedAt
+		res.Offline = opts.Offline
+	}
+
 	const maxConcurrent = 10
 
 	var (
@@ -105,22 +111,47 @@ func UpdateDevelopersWithCNCFEntityAffiliations(ctx context.Context, store data.
 	return res, nil
 }
 
-func GetCNCFEntityAffiliations(ctx context.Context) (map[string]*data.CNCFDeveloper, error) {
+// GetCNCFEntityAffiliations loads the CNCF gitdm affiliation files, through
+// the local cache when opts has a Dir.
+func GetCNCFEntityAffiliations(ctx context.Context, opts *AffiliationCacheOptions) (map[string]*data.CNCFDeveloper, error) {
+	devs, _, err := getCNCFEntityAffiliations(ctx, opts)
+	return devs, err
+}
+
+func getCNCFEntityAffiliations(ctx context.Context, opts *AffiliationCacheOptions) (map[string]*data.CNCFDeveloper, *affilCacheManifest, error) {
 	start := time.Now()
 	devs := make(map[string]*data.CNCFDeveloper)
+
+	if opts != nil && opts.Dir != "" {
+		m, err := loadCachedAffiliations(ctx, opts, devs)
+		if err != nil {
+			return devs, nil, err
+		}
+
+		slog.Debug("CNCF affiliations loaded",
+			"files", len(m.Files),
+			"developers", len(devs),
+			"cache_version", m.version(),
+			"offline", opts.Offline,
+			"duration", time.Since(start).String(),
+		)
+
+		return devs, m, nil
+	}
+
 	completed := 0
 
 	for i := 1; ; i++ {
 		select {
 		case <-ctx.Done():
-			return devs, ctx.Err()
+			return devs, nil, ctx.Err()
 		default:
 		}
 
 		url := fmt.Sprintf(affilFileURL, i)
 		ok, err := loadAffiliations(ctx, url, devs)
 		if err != nil {
-			return devs, fmt.Errorf("loading affiliation file %d (%s): %w", i, url, err)
+			return devs, nil, fmt.Errorf("loading affiliation file %d (%s): %w", i, url, err)
 		}
 		if !ok {
 			break
@@ -134,7 +165,7 @@ func GetCNCFEntityAffiliations(ctx context.Context) (map[string]*data.CNCFDevelo
 		"duration", time.Since(start).String(),
 	)
 
-	return devs, nil
+	return devs, nil, nil
 }
 
 func loadAffiliations(ctx context.Context, url string, devs map[string]*data.CNCFDeveloper) (bool, error) {
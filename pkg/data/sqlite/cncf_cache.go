@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/mchmarny/devpulse/pkg/net"
+)
+
+const (
+	affilCacheDirName      = "affiliations"
+	affilCacheManifestName = "manifest.json"
+	affilCacheFileName     = "developers_affiliations%d.txt"
+
+	// AffiliationCacheMaxAgeDefault is how long cached affiliation files are
+	// used without revalidating them against GitHub.
+	AffiliationCacheMaxAgeDefault = 24 * time.Hour
+)
+
+// AffiliationCacheOptions configures the local CNCF affiliation file cache.
+type AffiliationCacheOptions struct {
+	// Dir is the data directory; files are cached in its affiliations subdir.
+	// Empty disables caching.
+	Dir string
+	// MaxAge is how long cached files are used without revalidation.
+	MaxAge time.Duration
+	// Offline uses only cached files and never hits the network.
+	Offline bool
+}
+
+// affilCacheManifest describes the cached affiliation files.
+type affilCacheManifest struct {
+	CheckedAt string             `json:"checked_at"`
+	Files     []*affilCachedFile `json:"files"`
+}
+
+type affilCachedFile struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	net.CacheValidators
+	SHA256 string `json:"sha256"`
+}
+
+// version returns a short content hash identifying the cached file set.
+func (m *affilCacheManifest) version() string {
+	h := sha256.New()
+	for _, f := range m.Files {
+		fmt.Fprintf(h, "%s:%s\n", f.Name, f.SHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func (m *affilCacheManifest) file(name string) *affilCachedFile {
+	for _, f := range m.Files {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// loadCachedAffiliations loads the CNCF affiliation files through the cache
+// in opts.Dir, revalidating them with ETag/Last-Modified once MaxAge elapses.
+func loadCachedAffiliations(ctx context.Context, opts *AffiliationCacheOptions, devs map[string]*data.CNCFDeveloper) (*affilCacheManifest, error) {
+	dir := filepath.Join(opts.Dir, affilCacheDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating affiliation cache dir %s: %w", dir, err)
+	}
+
+	m, err := readAffilCacheManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Offline && !affilCacheFresh(m, opts.MaxAge) {
+		if m, err = refreshAffilCache(ctx, dir, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(m.Files) == 0 {
+		return nil, fmt.Errorf("no cached affiliation files in %s (run once without offline mode)", dir)
+	}
+
+	for _, f := range m.Files {
+		path := filepath.Join(dir, f.Name)
+		slog.Debug("extracting", "path", path)
+		if err := extractAffiliations(path, devs); err != nil {
+			return nil, fmt.Errorf("error extracting cached file: %s: %w", path, err)
+		}
+	}
+
+	return m, nil
+}
+
+func affilCacheFresh(m *affilCacheManifest, maxAge time.Duration) bool {
+	if len(m.Files) == 0 || maxAge <= 0 {
+		return false
+	}
+	checked, err := time.Parse(time.RFC3339, m.CheckedAt)
+	if err != nil {
+		return false
+	}
+	return time.Since(checked) < maxAge
+}
+
+// refreshAffilCache revalidates every affiliation file against GitHub. Files
+// that cannot be fetched fall back to their cached copy when one exists.
+func refreshAffilCache(ctx context.Context, dir string, prev *affilCacheManifest) (*affilCacheManifest, error) {
+	m := &affilCacheManifest{Files: make([]*affilCachedFile, 0)}
+	failed := false
+
+	for i := 1; ; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		name := fmt.Sprintf(affilCacheFileName, i)
+		path := filepath.Join(dir, name)
+		f := prev.file(name)
+		if f == nil || !fileExists(path) {
+			f = &affilCachedFile{Name: name}
+		}
+		f.URL = fmt.Sprintf(affilFileURL, i)
+
+		slog.Debug("revalidating", "url", f.URL, "path", path)
+		v, modified, err := net.DownloadIfModified(ctx, f.URL, path, &f.CacheValidators)
+		if err != nil {
+			if errors.Is(err, net.ErrURLNotFound) {
+				os.Remove(path)
+				break
+			}
+			if len(prev.Files) == 0 {
+				return nil, fmt.Errorf("loading affiliation file %d (%s): %w", i, f.URL, err)
+			}
+			failed = true
+			if f.SHA256 == "" {
+				slog.Warn("using cached affiliation files", "error", err)
+				break
+			}
+			slog.Warn("using cached affiliation file", "file", name, "error", err)
+			m.Files = append(m.Files, f)
+			continue
+		}
+
+		if modified {
+			f.CacheValidators = *v
+			if f.SHA256, err = fileSHA256(path); err != nil {
+				return nil, err
+			}
+		}
+		m.Files = append(m.Files, f)
+	}
+
+	// keep the previous set and check time when revalidation failed so the
+	// next run retries
+	if failed {
+		for _, f := range prev.Files {
+			if m.file(f.Name) == nil {
+				m.Files = append(m.Files, f)
+			}
+		}
+		m.CheckedAt = prev.CheckedAt
+		return m, writeAffilCacheManifest(dir, m)
+	}
+
+	// drop files no longer published upstream
+	for _, f := range prev.Files {
+		if m.file(f.Name) == nil {
+			os.Remove(filepath.Join(dir, f.Name))
+		}
+	}
+
+	m.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := writeAffilCacheManifest(dir, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func readAffilCacheManifest(dir string) (*affilCacheManifest, error) {
+	m := &affilCacheManifest{Files: make([]*affilCachedFile, 0)}
+
+	b, err := os.ReadFile(filepath.Join(dir, affilCacheManifestName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("error reading affiliation cache manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(b, m); err != nil {
+		slog.Warn("ignoring invalid affiliation cache manifest", "dir", dir, "error", err)
+		return &affilCacheManifest{Files: make([]*affilCachedFile, 0)}, nil
+	}
+
+	// ignore entries whose files were removed
+	files := make([]*affilCachedFile, 0, len(m.Files))
+	for _, f := range m.Files {
+		if f != nil && fileExists(filepath.Join(dir, f.Name)) {
+			files = append(files, f)
+		}
+	}
+	m.Files = files
+
+	return m, nil
+}
+
+func writeAffilCacheManifest(dir string, m *affilCacheManifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding affiliation cache manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, affilCacheManifestName), b, 0600); err != nil {
+		return fmt.Errorf("error writing affiliation cache manifest: %w", err)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec,nolintlint // G304: path within cache dir
+	if err != nil {
+		return "", fmt.Errorf("error opening file: %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error hashing file: %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -1,9 +1,13 @@
 package sqlite
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/stretchr/testify/assert"
@@ -42,3 +46,74 @@ func TestExtractAffiliations_NonExistentFile(t *testing.T) {
 	err := extractAffiliations("/nonexistent/path/file.txt", devs)
 	assert.Error(t, err)
 }
+
+func TestGetCNCFEntityAffiliations_Cache(t *testing.T) {
+	files := map[string]string{
+		"/developers_affiliations1.txt": "jdoe: jdoe!gmail.com\nGoogle from 2020-01-01\n",
+		"/developers_affiliations2.txt": "asmith: asmith!corp.com\nMicrosoft\n",
+	}
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := `"` + r.URL.Path + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	orig := affilFileURL
+	affilFileURL = srv.URL + "/developers_affiliations%d.txt"
+	t.Cleanup(func() { affilFileURL = orig })
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// offline with an empty cache fails
+	_, _, err := getCNCFEntityAffiliations(ctx, &AffiliationCacheOptions{Dir: dir, Offline: true})
+	assert.Error(t, err)
+
+	opts := &AffiliationCacheOptions{Dir: dir, MaxAge: time.Hour}
+	devs, m, err := getCNCFEntityAffiliations(ctx, opts)
+	require.NoError(t, err)
+	assert.Len(t, devs, 2)
+	require.Len(t, m.Files, 2)
+	assert.Equal(t, 3, requests)
+	version := m.version()
+	assert.Len(t, version, 12)
+
+	// within max age: no network
+	devs, m, err = getCNCFEntityAffiliations(ctx, opts)
+	require.NoError(t, err)
+	assert.Len(t, devs, 2)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, version, m.version())
+
+	// expired: revalidated with ETag
+	opts.MaxAge = 0
+	_, m, err = getCNCFEntityAffiliations(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, notModified)
+	assert.Equal(t, version, m.version())
+
+	// offline reads the cache only
+	srv.Close()
+	devs, m, err = getCNCFEntityAffiliations(ctx, &AffiliationCacheOptions{Dir: dir, Offline: true})
+	require.NoError(t, err)
+	assert.Len(t, devs, 2)
+	assert.Equal(t, version, m.version())
+
+	// network failure falls back to cached copies
+	devs, _, err = getCNCFEntityAffiliations(ctx, &AffiliationCacheOptions{Dir: dir})
+	require.NoError(t, err)
+	assert.Len(t, devs, 2)
+}
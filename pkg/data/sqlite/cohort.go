@@ -0,0 +1,304 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	upsertCohortSQL = `INSERT INTO cohort (name, description, min_merged_prs, months, entity, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			description = excluded.description,
+			min_merged_prs = excluded.min_merged_prs,
+			months = excluded.months,
+			entity = excluded.entity,
+			updated_at = excluded.updated_at
+	`
+
+	selectCohortSQL = `SELECT name, description, min_merged_prs, months, entity, updated_at
+		FROM cohort
+		WHERE name = ?
+	`
+
+	selectCohortsSQL = `SELECT c.name, c.description, c.min_merged_prs, c.months, c.entity, c.updated_at,
+			(SELECT COUNT(*) FROM cohort_member m WHERE m.cohort = c.name)
+		FROM cohort c
+		ORDER BY c.name
+	`
+
+	selectCohortMembersSQL = `SELECT username, static
+		FROM cohort_member
+		WHERE cohort = ?
+		ORDER BY username
+	`
+
+	deleteCohortSQL = `DELETE FROM cohort WHERE name = ?`
+
+	deleteCohortMembersSQL = `DELETE FROM cohort_member WHERE cohort = ?`
+
+	deleteCohortRuleMembersSQL = `DELETE FROM cohort_member WHERE cohort = ? AND static = 0`
+
+	insertCohortMemberSQL = `INSERT INTO cohort_member (cohort, username, static) VALUES (?, ?, 1)
+		ON CONFLICT(cohort, username) DO UPDATE SET static = 1
+	`
+
+	// insertCohortRuleMembersSQL materializes developers matching the cohort
+	// rules. Merged PRs are counted once per number however many days they
+	// were synced on. Args: cohort, entity, entity, min PRs, since, min PRs.
+	insertCohortRuleMembersSQL = `INSERT OR IGNORE INTO cohort_member (cohort, username, static)
+		SELECT ?, d.username, 0
+		FROM developer d
+		WHERE (? = '' OR UPPER(IFNULL(d.entity, '')) = UPPER(?))
+		  AND (? = 0 OR (
+			SELECT COUNT(DISTINCT e.org || '/' || e.repo || '#' || e.number) FROM event e
+			WHERE e.username = d.username
+			  AND e.type = 'pr'
+			  AND e.number IS NOT NULL
+			  AND e.merged_at >= ?
+		  ) >= ?)
+	`
+)
+
+// SaveCohort creates or replaces the cohort definition and its static members,
+// then materializes the developers matching its rules.
+func (s *Store) SaveCohort(c *data.Cohort) (*data.Cohort, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	if c == nil || strings.TrimSpace(c.Name) == "" {
+		return nil, errors.New("cohort name is required")
+	}
+	c.Name = strings.TrimSpace(c.Name)
+
+	if c.Rules != nil {
+		if c.Rules.MinMergedPRs < 0 || c.Rules.Months < 0 {
+			return nil, errors.New("cohort rules must not be negative")
+		}
+		if c.Rules.MinMergedPRs > 0 && c.Rules.Months == 0 {
+			c.Rules.Months = data.EventAgeMonthsDefault
+		}
+	}
+
+	if len(c.Members) == 0 && !cohortHasRules(c.Rules) {
+		return nil, errors.New("cohort requires members or rules")
+	}
+
+	rules := c.Rules
+	if rules == nil {
+		rules = &data.CohortRules{}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err = tx.Exec(upsertCohortSQL, c.Name, c.Description, rules.MinMergedPRs, rules.Months, rules.Entity, now); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to save cohort %s: %w", c.Name, err)
+	}
+
+	if _, err = tx.Exec(deleteCohortMembersSQL, c.Name); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to clear cohort %s members: %w", c.Name, err)
+	}
+
+	for _, u := range c.Members {
+		if u = strings.TrimSpace(u); u == "" {
+			continue
+		}
+		if _, err = tx.Exec(insertCohortMemberSQL, c.Name, u); err != nil {
+			rollbackTransaction(tx)
+			return nil, fmt.Errorf("failed to add cohort %s member %s: %w", c.Name, u, err)
+		}
+	}
+
+	if err = refreshCohortRuleMembers(tx, c.Name, rules); err != nil {
+		rollbackTransaction(tx)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetCohort(c.Name)
+}
+
+// DeleteCohort removes the cohort and its members. Returns nil if the cohort
+// does not exist.
+func (s *Store) DeleteCohort(name string) (*data.Cohort, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	c, err := s.GetCohort(name)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err = tx.Exec(deleteCohortMembersSQL, name); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to delete cohort %s members: %w", name, err)
+	}
+
+	if _, err = tx.Exec(deleteCohortSQL, name); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to delete cohort %s: %w", name, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return c, nil
+}
+
+// GetCohort returns the cohort with its static and rule-matched members.
+// Returns nil if the cohort does not exist.
+func (s *Store) GetCohort(name string) (*data.Cohort, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	c := &data.Cohort{}
+	r := &data.CohortRules{}
+	if err := s.db.QueryRow(selectCohortSQL, name).Scan(&c.Name, &c.Description, &r.MinMergedPRs, &r.Months, &r.Entity, &c.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to select cohort %s: %w", name, err)
+	}
+	if cohortHasRules(r) {
+		c.Rules = r
+	}
+
+	rows, err := s.db.Query(selectCohortMembersSQL, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute cohort members select statement: %w", err)
+	}
+	defer rows.Close()
+
+	c.Members = make([]string, 0)
+	c.Matched = make([]string, 0)
+	for rows.Next() {
+		var username string
+		var static bool
+		if err := rows.Scan(&username, &static); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if static {
+			c.Members = append(c.Members, username)
+		} else {
+			c.Matched = append(c.Matched, username)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	c.MemberCount = len(c.Members) + len(c.Matched)
+
+	return c, nil
+}
+
+// GetCohorts lists cohort definitions with member counts (without members).
+func (s *Store) GetCohorts() ([]*data.Cohort, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectCohortsSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute cohort select statement: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.Cohort, 0)
+	for rows.Next() {
+		c := &data.Cohort{}
+		r := &data.CohortRules{}
+		if err := rows.Scan(&c.Name, &c.Description, &r.MinMergedPRs, &r.Months, &r.Entity, &c.UpdatedAt, &c.MemberCount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if cohortHasRules(r) {
+			c.Rules = r
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// RefreshCohorts re-evaluates the rules of all dynamic cohorts against the
+// current data. Run after imports so rule-based membership stays current.
+func (s *Store) RefreshCohorts() ([]*data.Cohort, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	list, err := s.GetCohorts()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, c := range list {
+		if c.Rules == nil {
+			continue
+		}
+		if err = refreshCohortRuleMembers(tx, c.Name, c.Rules); err != nil {
+			rollbackTransaction(tx)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetCohorts()
+}
+
+func refreshCohortRuleMembers(tx *sql.Tx, name string, r *data.CohortRules) error {
+	if _, err := tx.Exec(deleteCohortRuleMembersSQL, name); err != nil {
+		return fmt.Errorf("failed to clear cohort %s rule members: %w", name, err)
+	}
+
+	if !cohortHasRules(r) {
+		return nil
+	}
+
+	if _, err := tx.Exec(insertCohortRuleMembersSQL, name, r.Entity, r.Entity,
+		r.MinMergedPRs, sinceDate(r.Months), r.MinMergedPRs); err != nil {
+		return fmt.Errorf("failed to match cohort %s rule members: %w", name, err)
+	}
+
+	return nil
+}
+
+func cohortHasRules(r *data.CohortRules) bool {
+	return r != nil && (r.MinMergedPRs > 0 || r.Entity != "")
+}
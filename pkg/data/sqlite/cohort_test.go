@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedCohortData(t *testing.T, store *Store) {
+	t.Helper()
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "alice", Entity: "ACME"},
+		{Username: "bob", Entity: "ACME"},
+		{Username: "carol", Entity: "INITECH"},
+	}))
+
+	base := time.Now().UTC().AddDate(0, -1, 0)
+	for i := 0; i < 3; i++ {
+		at := base.AddDate(0, 0, i)
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, merged_at)
+			VALUES ('org1', 'repo1', 'carol', 'pr', ?, 'http://x', '', '', 'merged', ?, ?)`,
+			at.Format("2006-01-02"), i+1, at.Format(time.RFC3339))
+		require.NoError(t, err)
+	}
+	day := base.Format("2006-01-02")
+	for _, u := range []string{"alice", "bob"} {
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state)
+			VALUES ('org1', 'repo1', ?, 'pr', ?, 'http://x', '', '', 'open')`, u, day)
+		require.NoError(t, err)
+	}
+}
+
+func TestSaveCohort_Static(t *testing.T) {
+	store := setupTestDB(t)
+	seedCohortData(t, store)
+
+	c, err := store.SaveCohort(&data.Cohort{Name: " devrel ", Members: []string{"alice", "bob", ""}})
+	require.NoError(t, err)
+	assert.Equal(t, "devrel", c.Name)
+	assert.Equal(t, []string{"alice", "bob"}, c.Members)
+	assert.Empty(t, c.Matched)
+	assert.Nil(t, c.Rules)
+	assert.Equal(t, 2, c.MemberCount)
+
+	// saving replaces static members
+	c, err = store.SaveCohort(&data.Cohort{Name: "devrel", Members: []string{"bob"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, c.Members)
+}
+
+func TestSaveCohort_Rules(t *testing.T) {
+	store := setupTestDB(t)
+	seedCohortData(t, store)
+
+	c, err := store.SaveCohort(&data.Cohort{Name: "core", Rules: &data.CohortRules{MinMergedPRs: 3}})
+	require.NoError(t, err)
+	require.NotNil(t, c.Rules)
+	assert.Equal(t, data.EventAgeMonthsDefault, c.Rules.Months)
+	assert.Equal(t, []string{"carol"}, c.Matched)
+
+	c, err = store.SaveCohort(&data.Cohort{Name: "acme", Members: []string{"carol"}, Rules: &data.CohortRules{Entity: "acme"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol"}, c.Members)
+	assert.Equal(t, []string{"alice", "bob"}, c.Matched)
+	assert.Equal(t, 3, c.MemberCount)
+
+	list, err := store.GetCohorts()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "acme", list[0].Name)
+	assert.Equal(t, 3, list[0].MemberCount)
+
+	// new matching data is picked up on refresh
+	for i := 0; i < 3; i++ {
+		at := time.Now().UTC().AddDate(0, 0, -i-1)
+		_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, merged_at)
+			VALUES ('org1', 'repo1', 'alice', 'pr', ?, 'http://x', '', '', 'merged', ?, ?)`,
+			at.Format("2006-01-02"), i+10, at.Format(time.RFC3339))
+		require.NoError(t, err)
+	}
+	_, err = store.RefreshCohorts()
+	require.NoError(t, err)
+
+	c, err = store.GetCohort("core")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol"}, c.Matched)
+}
+
+func TestSaveCohort_RulesCountPRsOnce(t *testing.T) {
+	store := setupTestDB(t)
+	seedCohortData(t, store)
+
+	// PR 20 is re-synced on two days after it merged; with PR 21 that is
+	// two merged PRs, not three
+	merged := time.Now().UTC().AddDate(0, 0, -5)
+	for i, n := range []int{20, 20, 21} {
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, merged_at)
+			VALUES ('org1', 'repo1', 'bob', 'pr', ?, 'http://x', '', '', 'merged', ?, ?)`,
+			merged.AddDate(0, 0, i).Format("2006-01-02"), n, merged.Format(time.RFC3339))
+		require.NoError(t, err)
+	}
+
+	c, err := store.SaveCohort(&data.Cohort{Name: "core", Rules: &data.CohortRules{MinMergedPRs: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol"}, c.Matched)
+
+	c, err = store.SaveCohort(&data.Cohort{Name: "core", Rules: &data.CohortRules{MinMergedPRs: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob", "carol"}, c.Matched)
+}
+
+func TestSaveCohort_Invalid(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.SaveCohort(nil)
+	assert.Error(t, err)
+	_, err = store.SaveCohort(&data.Cohort{Name: " "})
+	assert.Error(t, err)
+	_, err = store.SaveCohort(&data.Cohort{Name: "empty"})
+	assert.Error(t, err)
+	_, err = store.SaveCohort(&data.Cohort{Name: "neg", Rules: &data.CohortRules{MinMergedPRs: -1}})
+	assert.Error(t, err)
+}
+
+func TestDeleteCohort(t *testing.T) {
+	store := setupTestDB(t)
+	seedCohortData(t, store)
+
+	_, err := store.SaveCohort(&data.Cohort{Name: "devrel", Members: []string{"alice"}})
+	require.NoError(t, err)
+
+	c, err := store.DeleteCohort("devrel")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, []string{"alice"}, c.Members)
+
+	c, err = store.GetCohort("devrel")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	c, err = store.DeleteCohort("devrel")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+}
+
+func TestInsights_CohortFilter(t *testing.T) {
+	store := setupTestDB(t)
+	seedCohortData(t, store)
+
+	_, err := store.SaveCohort(&data.Cohort{Name: "acme", Rules: &data.CohortRules{Entity: "ACME"}})
+	require.NoError(t, err)
+
+	summary, err := store.GetInsightsSummary(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Contributors)
+
+	cohort := "acme"
+	summary, err = store.GetInsightsSummary(&data.InsightsFilter{Months: 6, Cohort: &cohort})
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Contributors)
+
+	res, err := store.SearchEvents(&data.EventSearchCriteria{Cohort: &cohort, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+}
+
+func TestCohorts_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.SaveCohort(&data.Cohort{Name: "x", Members: []string{"a"}})
+	assert.Error(t, err)
+	_, err = s.DeleteCohort("x")
+	assert.Error(t, err)
+	_, err = s.GetCohort("x")
+	assert.Error(t, err)
+	_, err = s.GetCohorts()
+	assert.Error(t, err)
+	_, err = s.RefreshCohorts()
+	assert.Error(t, err)
+}
@@ -34,6 +34,7 @@ const (
 	WHERE cv.org = COALESCE(?, cv.org)
 	  AND cv.repo = COALESCE(?, cv.repo)
 	  AND cv.created_at >= ?
+	  AND cv.created_at < ?
 	GROUP BY month
 	ORDER BY month
 	`
@@ -199,14 +200,20 @@ func (s *Store) ImportAllContainerVersions(ctx context.Context, token string) er
 	return nil
 }
 
-func (s *Store) GetContainerActivity(org, repo *string, months int) (*data.ContainerActivitySeries, error) { //nolint:dupl,nolintlint // different types and SQL than GetReleaseDownloads
+// GetContainerActivity returns the number of container versions pushed by
+// period. Versions are not attributed to developers, so the entity, cohort
+// and bot filters do not apply.
+func (s *Store) GetContainerActivity(f *data.InsightsFilter) (*data.ContainerActivitySeries, error) { //nolint:dupl,nolintlint // different types and SQL than GetReleaseDownloads
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := s.db.Query(selectContainerActivitySQL, org, repo, since)
+	rows, err := s.db.Query(br.query(selectContainerActivitySQL), f.Org, f.Repo, br.since(), br.until())
 	if err != nil {
 		return nil, fmt.Errorf("querying container activity: %w", err)
 	}
@@ -231,6 +238,7 @@ func (s *Store) GetContainerActivity(org, repo *string, months int) (*data.Conta
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
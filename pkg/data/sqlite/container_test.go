@@ -3,19 +3,20 @@ package sqlite
 import (
 	"testing"
 
+	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetContainerActivity_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetContainerActivity(nil, nil, 6)
+	_, err := s.GetContainerActivity(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetContainerActivity_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetContainerActivity(nil, nil, 6)
+	series, err := store.GetContainerActivity(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 	assert.Empty(t, series.Versions)
@@ -31,7 +32,7 @@ func TestGetContainerActivity_WithData(t *testing.T) {
 		('org1', 'repo1', 'pkg1', 3, 'v2.0.0', '2025-02-10T10:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContainerActivity(nil, nil, 24)
+	series, err := store.GetContainerActivity(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 2)
 	assert.Equal(t, "2025-01", series.Months[0])
@@ -50,8 +51,29 @@ func TestGetContainerActivity_FilterByOrg(t *testing.T) {
 	require.NoError(t, err)
 
 	org := "org1"
-	series, err := store.GetContainerActivity(&org, nil, 24)
+	series, err := store.GetContainerActivity(&data.InsightsFilter{Org: &org, Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 1, series.Versions[0])
 }
+
+func TestGetContainerActivity_Range(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO container_version (org, repo, package, version_id, tag, created_at)
+		VALUES
+		('org1', 'repo1', 'pkg1', 1, 'v1.0.0', '2025-01-15T10:00:00Z'),
+		('org1', 'repo1', 'pkg1', 2, 'v1.1.0', '2025-01-20T10:00:00Z'),
+		('org1', 'repo1', 'pkg1', 3, 'v2.0.0', '2025-02-10T10:00:00Z')`)
+	require.NoError(t, err)
+
+	f := &data.InsightsFilter{Granularity: data.GranularityWeek, From: strPtr("2025-01-13"), To: strPtr("2025-01-26")}
+	series, err := store.GetContainerActivity(f)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-W03", "2025-W04"}, series.Months)
+	assert.Equal(t, []int{1, 1}, series.Versions)
+
+	f.From = strPtr("2025-02-01")
+	_, err = store.GetContainerActivity(f)
+	assert.Error(t, err)
+}
@@ -10,6 +10,16 @@ const (
 	deleteReleaseAssetsSQL = `DELETE FROM release_asset WHERE org = ? AND repo = ?`
 	deleteReleasesSQL      = `DELETE FROM release WHERE org = ? AND repo = ?`
 	deleteEventsSQL        = `DELETE FROM event WHERE org = ? AND repo = ?`
+	deleteEventItemsSQL    = `DELETE FROM event_item WHERE org = ? AND repo = ?`
+	deleteTimelineSQL      = `DELETE FROM timeline_event WHERE org = ? AND repo = ?`
+	deleteReviewsSQL       = `DELETE FROM pr_review WHERE org = ? AND repo = ?`
+	deleteDiscussionsSQL   = `DELETE FROM discussion WHERE org = ? AND repo = ?`
+	deleteDiscCommentsSQL  = `DELETE FROM discussion_comment WHERE org = ? AND repo = ?`
+	deleteLinksSQL         = `DELETE FROM pr_issue_link WHERE org = ? AND repo = ?`
+	deleteBacklogSQL       = `DELETE FROM backlog_snapshot WHERE org = ? AND repo = ?`
+	deleteEngagementSQL    = `DELETE FROM engagement WHERE org = ? AND repo = ?`
+	deleteMilestoneItemSQL = `DELETE FROM milestone_item WHERE org = ? AND repo = ?`
+	deleteMilestonesSQL    = `DELETE FROM milestone WHERE org = ? AND repo = ?`
 	deleteRepoMetaSQL      = `DELETE FROM repo_meta WHERE org = ? AND repo = ?`
 	deleteStateSQL         = `DELETE FROM state WHERE org = ? AND repo = ?`
 )
@@ -37,6 +47,17 @@ func (s *Store) DeleteRepoData(org, repo string) (*data.DeleteResult, error) {
 		{deleteReleaseAssetsSQL, &result.ReleaseAssets},
 		{deleteReleasesSQL, &result.Releases},
 		{deleteEventsSQL, &result.Events},
+		{deleteEventItemsSQL, nil},
+		{deleteTimelineSQL, &result.Timeline},
+		{deleteReviewsSQL, &result.Reviews},
+		{deleteDiscCommentsSQL, nil},
+		{deleteDiscussionsSQL, &result.Discussions},
+		{deleteLinksSQL, &result.Links},
+		{deleteBacklogSQL, &result.Backlog},
+		{deleteEngagementSQL, &result.Engagement},
+		{deleteMilestoneItemSQL, nil},
+		{deleteMilestonesSQL, &result.Milestones},
+		{deleteProjectItemsSQL, &result.ProjectItems},
 		{deleteRepoMetaSQL, &result.RepoMeta},
 		{deleteStateSQL, &result.State},
 	}
@@ -52,7 +73,9 @@ func (s *Store) DeleteRepoData(org, repo string) (*data.DeleteResult, error) {
 			_ = tx.Rollback()
 			return nil, fmt.Errorf("getting rows affected: %w", raErr)
 		}
-		*d.field = n
+		if d.field != nil {
+			*d.field = n
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
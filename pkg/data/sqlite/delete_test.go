@@ -61,6 +61,17 @@ func TestDeleteRepoData_WithData(t *testing.T) {
 		('pr', 'myorg', 'myrepo', 5, 1700000000)`)
 	require.NoError(t, err)
 
+	// Insert milestones and project board items
+	_, err = store.db.Exec(`INSERT INTO milestone (org, repo, number, title, state, url, created_at, updated_at) VALUES
+		('myorg', 'myrepo', 1, 'v1', 'open', 'http://example.com/m/1', '2025-01-01', '2025-01-01')`)
+	require.NoError(t, err)
+	_, err = store.db.Exec(`INSERT INTO milestone_item (org, repo, milestone, number, item_type, created_at) VALUES
+		('myorg', 'myrepo', 1, 2, 'issue', '2025-01-02')`)
+	require.NoError(t, err)
+	_, err = store.db.Exec(`INSERT INTO project_item (org, repo, project, project_title, item_id, number, item_type, status, updated_at) VALUES
+		('myorg', 'myrepo', 1, 'Roadmap', 'PVTI_1', 2, 'issue', 'Todo', '2025-01-02')`)
+	require.NoError(t, err)
+
 	// Delete myorg/myrepo
 	result, err := store.DeleteRepoData("myorg", "myrepo")
 	require.NoError(t, err)
@@ -70,6 +81,8 @@ func TestDeleteRepoData_WithData(t *testing.T) {
 	assert.Equal(t, int64(1), result.Releases)
 	assert.Equal(t, int64(2), result.ReleaseAssets)
 	assert.Equal(t, int64(1), result.State)
+	assert.Equal(t, int64(1), result.Milestones)
+	assert.Equal(t, int64(1), result.ProjectItems)
 
 	// Verify otherrepo data is untouched
 	var count int
@@ -19,15 +19,20 @@ const (
 			email,
 			avatar,
 			url,
-			entity
+			entity,
+			is_bot
 		)
-		VALUES (?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, COALESCE((SELECT b.is_bot FROM bot_override b WHERE b.username = ?), ?))
 		ON CONFLICT(username) DO UPDATE SET
 			full_name = ?,
 			email = ?,
 			avatar = ?,
 			url = ?,
-			entity = CASE WHEN ? = '' THEN COALESCE(developer.entity, '') ELSE ? END
+			entity = CASE WHEN ? = '' THEN COALESCE(developer.entity, '') ELSE ? END,
+			is_bot = CASE
+				WHEN EXISTS (SELECT 1 FROM bot_override b WHERE b.username = excluded.username) THEN excluded.is_bot
+				ELSE MAX(developer.is_bot, excluded.is_bot)
+			END
 	`
 
 	selectDeveloperSQL = `SELECT
@@ -36,7 +41,8 @@ const (
 			email,
 			avatar,
 			url,
-			entity
+			entity,
+			is_bot
 		FROM developer
 		WHERE username = ?
 	`
@@ -128,9 +134,7 @@ func (s *Store) SaveDevelopers(devs []*data.Developer) error {
 
 	txStmt := tx.Stmt(userStmt)
 	for i, u := range devs {
-		if _, err = txStmt.Exec(u.Username,
-			u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity,
-			u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity, u.Entity); err != nil {
+		if _, err = txStmt.Exec(developerUpsertArgs(u)...); err != nil {
 			slog.Error("failed to insert developer",
 				"index", i,
 				"error", err,
@@ -153,6 +157,15 @@ func (s *Store) SaveDevelopers(devs []*data.Developer) error {
 	return nil
 }
 
+// developerUpsertArgs returns the insertDeveloperSQL arguments for u.
+func developerUpsertArgs(u *data.Developer) []any {
+	isBot := u.IsBot || isBotUsername(u.Username)
+	return []any{
+		u.Username, u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity, u.Username, isBot,
+		u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity, u.Entity,
+	}
+}
+
 func (s *Store) MergeDeveloper(ctx context.Context, client *http.Client, username string, cDev *data.CNCFDeveloper) (*data.Developer, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
@@ -193,7 +206,7 @@ func (s *Store) MergeDeveloper(ctx context.Context, client *http.Client, usernam
 		dbDev.AvatarURL = ghDev.AvatarURL
 	}
 
-	ghEntity := cleanEntityName(ghDev.Entity)
+	ghEntity := s.entityNormalizer().clean(ghDev.Entity)
 	if ghEntity != "" {
 		dbDev.Entity = ghEntity
 	} else if ca := cDev.GetLatestAffiliation(); ca != "" {
@@ -201,7 +214,7 @@ func (s *Store) MergeDeveloper(ctx context.Context, client *http.Client, usernam
 	}
 
 	if len(dbDev.Email) == 0 {
-		dbDev.Email = cleanEntityName(cDev.GetBestIdentity())
+		dbDev.Email = s.entityNormalizer().clean(cDev.GetBestIdentity())
 	}
 
 	return dbDev, nil
@@ -233,7 +246,7 @@ func (s *Store) GetDeveloper(username string) (*data.Developer, error) {
 	row := stmt.QueryRow(username)
 
 	u := &data.Developer{}
-	if err = row.Scan(&u.Username, &u.FullName, &u.Email, &u.AvatarURL, &u.ProfileURL, &u.Entity); err != nil {
+	if err = row.Scan(&u.Username, &u.FullName, &u.Email, &u.AvatarURL, &u.ProfileURL, &u.Entity, &u.IsBot); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -0,0 +1,463 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/mchmarny/devpulse/pkg/data/ghutil"
+)
+
+const (
+	discussionPageSize     = 25
+	discussionCommentLimit = 50
+	discussionLabelLimit   = 20
+	topAnswerersDefault    = 10
+
+	// selectDiscussionsGraphQL lists discussions by most recent update with
+	// their first comments and the accepted answer, which may be a reply or
+	// beyond the first page of comments.
+	selectDiscussionsGraphQL = `query($owner: String!, $name: String!, $first: Int!, $comments: Int!, $labels: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    discussions(first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number title url createdAt updatedAt closed closedAt isAnswered authorAssociation
+        category { isAnswerable }
+        author { __typename login avatarUrl url }
+        labels(first: $labels) { nodes { name } }
+        answer { url createdAt updatedAt authorAssociation author { __typename login avatarUrl url } }
+        comments(first: $comments) {
+          nodes { url createdAt updatedAt isAnswer authorAssociation author { __typename login avatarUrl url } }
+        }
+      }
+    }
+  }
+}`
+
+	upsertDiscussionSQL = `INSERT INTO discussion (
+			url, org, repo, number, username, state, created_at, updated_at, answer_url
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			state = excluded.state,
+			updated_at = excluded.updated_at,
+			answer_url = excluded.answer_url
+	`
+
+	upsertDiscussionCommentSQL = `INSERT INTO discussion_comment (
+			url, org, repo, number, username, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			updated_at = excluded.updated_at
+	`
+
+	// selectDiscussionSeriesSQL lists each discussion created in the window
+	// with its state, the first reply by someone other than the author and
+	// the creation of its accepted answer.
+	selectDiscussionSeriesSQL = `SELECT
+		substr(t.created_at, 1, 7) AS month,
+		t.state,
+		t.created_at,
+		IFNULL((
+			SELECT MIN(c.created_at)
+			FROM discussion_comment c
+			JOIN developer cd ON c.username = cd.username
+			WHERE c.org = t.org AND c.repo = t.repo AND c.number = t.number
+			  AND c.username != t.username
+			  AND IFNULL(cd.is_bot, 0) = 0
+		), '') AS first_reply_at,
+		IFNULL((
+			SELECT c.created_at
+			FROM discussion_comment c
+			WHERE c.url = t.answer_url
+		), '') AS answer_at
+	FROM discussion t
+	JOIN developer d ON t.username = d.username
+	WHERE t.org = COALESCE(?, t.org)
+	  AND t.repo = COALESCE(?, t.repo)
+	  AND t.created_at >= ?
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  ` + developerFilterSQL + `
+	ORDER BY month
+	`
+
+	// selectTopAnswerersSQL ranks developers by accepted answers, then by
+	// discussion replies, posted in the window.
+	selectTopAnswerersSQL = `SELECT
+			c.username,
+			IFNULL(d.entity, '') AS entity,
+			COUNT(t.url) AS answers,
+			COUNT(*) AS replies
+		FROM discussion_comment c
+		JOIN developer d ON c.username = d.username
+		LEFT JOIN discussion t ON t.answer_url = c.url
+		WHERE c.org = COALESCE(?, c.org)
+		  AND c.repo = COALESCE(?, c.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND substr(c.created_at, 1, 10) >= ?
+		  AND substr(c.created_at, 1, 10) < ?
+		  ` + developerFilterSQL + `
+		GROUP BY c.username, d.entity
+		HAVING answers > 0
+		ORDER BY answers DESC, replies DESC, c.username
+		LIMIT ?
+	`
+)
+
+type discussionAuthor struct {
+	Typename  string `json:"__typename"`
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatarUrl"`
+	URL       string `json:"url"`
+}
+
+type discussionCommentNode struct {
+	URL               string            `json:"url"`
+	CreatedAt         github.Timestamp  `json:"createdAt"`
+	UpdatedAt         github.Timestamp  `json:"updatedAt"`
+	IsAnswer          bool              `json:"isAnswer"`
+	Author            *discussionAuthor `json:"author"`
+	AuthorAssociation string            `json:"authorAssociation"`
+}
+
+type discussionNode struct {
+	Number            int               `json:"number"`
+	Title             string            `json:"title"`
+	URL               string            `json:"url"`
+	CreatedAt         github.Timestamp  `json:"createdAt"`
+	UpdatedAt         github.Timestamp  `json:"updatedAt"`
+	Closed            bool              `json:"closed"`
+	ClosedAt          *github.Timestamp `json:"closedAt"`
+	IsAnswered        bool              `json:"isAnswered"`
+	AuthorAssociation string            `json:"authorAssociation"`
+	Category          struct {
+		IsAnswerable bool `json:"isAnswerable"`
+	} `json:"category"`
+	Author *discussionAuthor `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Answer   *discussionCommentNode `json:"answer"`
+	Comments struct {
+		Nodes []*discussionCommentNode `json:"nodes"`
+	} `json:"comments"`
+}
+
+type discussionsData struct {
+	Repository *struct {
+		Discussions struct {
+			PageInfo graphQLPageInfo   `json:"pageInfo"`
+			Nodes    []*discussionNode `json:"nodes"`
+		} `json:"discussions"`
+	} `json:"repository"`
+}
+
+// toUser maps a GraphQL discussion author to the REST user shape used for
+// developer upserts. The GraphQL type name of app accounts matches their
+// REST user type. Deleted accounts have no author and map to nil.
+func (a *discussionAuthor) toUser() *github.User {
+	if a == nil || a.Login == "" {
+		return nil
+	}
+	u := &github.User{
+		Login:     github.Ptr(a.Login),
+		AvatarURL: github.Ptr(a.AvatarURL),
+		HTMLURL:   github.Ptr(a.URL),
+	}
+	if a.Typename == ghutil.UserTypeBot {
+		u.Type = github.Ptr(ghutil.UserTypeBot)
+	}
+	return u
+}
+
+// discussionState returns the state stored for a discussion: answered or
+// unanswered in answerable categories, open or closed otherwise.
+func discussionState(n *discussionNode) string {
+	switch {
+	case n.Category.IsAnswerable && n.IsAnswered:
+		return data.DiscussionStateAnswered
+	case n.Category.IsAnswerable:
+		return data.DiscussionStateUnanswered
+	case n.Closed:
+		return data.DiscussionStateClosed
+	default:
+		return data.DiscussionStateOpen
+	}
+}
+
+// importDiscussionEvents imports discussions and their comments using the
+// GraphQL API, which is the only API exposing GitHub Discussions. Results
+// are ordered by last update so the import stops at the resume point.
+func (e *eventImporter) importDiscussionEvents(ctx context.Context) error {
+	since := e.state[data.EventTypeDiscussion].Since
+	slog.Debug("starting discussion event import", "since", since.Format("2006-01-02"))
+
+	vars := map[string]any{
+		"owner":    e.owner,
+		"name":     e.repo,
+		"first":    discussionPageSize,
+		"comments": discussionCommentLimit,
+		"labels":   discussionLabelLimit,
+	}
+
+	for {
+		out, resp, err := queryGraphQL[discussionsData](ctx, e.client, selectDiscussionsGraphQL, vars)
+		if err != nil {
+			return fmt.Errorf("error listing discussions: %w", err)
+		}
+
+		if out.Repository == nil {
+			break
+		}
+
+		page := out.Repository.Discussions
+		slog.Debug("discussion events", "found", len(page.Nodes), "has_next", page.PageInfo.HasNextPage, "rate", ghutil.RateInfo(&resp.Rate))
+
+		done := false
+		for _, n := range page.Nodes {
+			if n.UpdatedAt.Before(since) {
+				done = true
+				break
+			}
+			if err := e.addDiscussion(n); err != nil {
+				return fmt.Errorf("error adding discussion event: %s/%s: %w", e.owner, e.repo, err)
+			}
+		}
+
+		if done || !page.PageInfo.HasNextPage {
+			break
+		}
+
+		vars["after"] = page.PageInfo.EndCursor
+	}
+
+	return nil
+}
+
+// addDiscussion adds a discussion and its comments as events and to the
+// discussion store. The accepted answer is stored as a discussion comment
+// event in the answer state and as the answer URL of the discussion.
+func (e *eventImporter) addDiscussion(n *discussionNode) error {
+	number := n.Number
+
+	var thread *data.Discussion
+	if usr := n.Author.toUser(); usr != nil {
+		labels := make([]string, 0, len(n.Labels.Nodes))
+		for _, l := range n.Labels.Nodes {
+			labels = append(labels, l.Name)
+		}
+		extra := &eventExtra{
+			State:             github.Ptr(discussionState(n)),
+			Number:            &number,
+			CreatedAt:         timestampStr(&n.CreatedAt),
+			ClosedAt:          timestampStr(n.ClosedAt),
+			Title:             n.Title,
+			AuthorAssociation: getStrPtr(n.AuthorAssociation),
+		}
+		if err := e.add(data.EventTypeDiscussion, n.URL, usr, &n.UpdatedAt.Time, nil, labels, extra); err != nil {
+			return err
+		}
+		thread = &data.Discussion{
+			URL:       n.URL,
+			Org:       e.owner,
+			Repo:      e.repo,
+			Number:    number,
+			Username:  usr.GetLogin(),
+			State:     discussionState(n),
+			CreatedAt: n.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt: n.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	comments := n.Comments.Nodes
+	if n.Answer != nil {
+		answer := *n.Answer
+		answer.IsAnswer = true
+		comments = append([]*discussionCommentNode{&answer}, comments...)
+		if thread != nil {
+			thread.AnswerURL = github.Ptr(n.Answer.URL)
+		}
+	}
+
+	replies := make([]*data.DiscussionComment, 0, len(comments))
+	seen := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		if c == nil || seen[c.URL] {
+			continue
+		}
+		seen[c.URL] = true
+
+		usr := c.Author.toUser()
+		if usr == nil {
+			continue
+		}
+		extra := &eventExtra{
+			Number:            &number,
+			CreatedAt:         timestampStr(&c.CreatedAt),
+			AuthorAssociation: getStrPtr(c.AuthorAssociation),
+		}
+		if c.IsAnswer {
+			extra.State = github.Ptr(data.DiscussionStateAnswer)
+		}
+		if err := e.add(data.EventTypeDiscussionComment, c.URL, usr, &c.UpdatedAt.Time, nil, nil, extra); err != nil {
+			return err
+		}
+		replies = append(replies, &data.DiscussionComment{
+			URL:       c.URL,
+			Org:       e.owner,
+			Repo:      e.repo,
+			Number:    number,
+			Username:  usr.GetLogin(),
+			CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt: c.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return e.addDiscussionThread(thread, replies)
+}
+
+// GetDiscussionSeries returns monthly discussion volume, answer rate and
+// the average hours to the first reply and to the accepted answer.
+func (s *Store) GetDiscussionSeries(f *data.InsightsFilter) (*data.DiscussionSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectDiscussionSeriesSQL),
+		f.Org, f.Repo, since, f.Entity, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query discussion series: %w", err)
+	}
+	defer rows.Close()
+
+	type monthAgg struct {
+		opened, answerable, answered int
+		replySum, answerSum          float64
+		replyCnt, answerCnt          int
+	}
+	byMonth := make(map[string]*monthAgg)
+
+	for rows.Next() {
+		var month, state, createdAt, firstReplyAt, answerAt string
+		if err := rows.Scan(&month, &state, &createdAt, &firstReplyAt, &answerAt); err != nil {
+			return nil, fmt.Errorf("failed to scan discussion row: %w", err)
+		}
+
+		agg, ok := byMonth[month]
+		if !ok {
+			agg = &monthAgg{}
+			byMonth[month] = agg
+		}
+		agg.opened++
+
+		switch state {
+		case data.DiscussionStateAnswered:
+			agg.answerable++
+			agg.answered++
+		case data.DiscussionStateUnanswered:
+			agg.answerable++
+		}
+
+		if start, end, ok := parseTimeRange(createdAt, firstReplyAt); ok {
+			agg.replySum += end.Sub(start).Hours()
+			agg.replyCnt++
+		}
+		if start, end, ok := parseTimeRange(createdAt, answerAt); ok {
+			agg.answerSum += end.Sub(start).Hours()
+			agg.answerCnt++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for m := range byMonth {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+
+	res := &data.DiscussionSeries{
+		Months:          months,
+		Opened:          make([]int, len(months)),
+		Answerable:      make([]int, len(months)),
+		Answered:        make([]int, len(months)),
+		AnswerRate:      make([]float64, len(months)),
+		FirstReplyHours: make([]float64, len(months)),
+		AnswerHours:     make([]float64, len(months)),
+	}
+
+	for i, m := range months {
+		agg := byMonth[m]
+		res.Opened[i] = agg.opened
+		res.Answerable[i] = agg.answerable
+		res.Answered[i] = agg.answered
+		if agg.answerable > 0 {
+			res.AnswerRate[i] = float64(agg.answered) / float64(agg.answerable) * 100
+		}
+		if agg.replyCnt > 0 {
+			res.FirstReplyHours[i] = agg.replySum / float64(agg.replyCnt)
+		}
+		if agg.answerCnt > 0 {
+			res.AnswerHours[i] = agg.answerSum / float64(agg.answerCnt)
+		}
+	}
+
+	br.align(res)
+	return res, nil
+}
+
+// GetTopAnswerers returns the developers with the most accepted discussion
+// answers in the window.
+func (s *Store) GetTopAnswerers(f *data.InsightsFilter, limit int) ([]*data.Answerer, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	if limit <= 0 {
+		limit = topAnswerersDefault
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectTopAnswerersSQL,
+		f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top answerers: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.Answerer, 0)
+	for rows.Next() {
+		a := &data.Answerer{}
+		if err := rows.Scan(&a.Username, &a.Entity, &a.Answers, &a.Replies); err != nil {
+			return nil, fmt.Errorf("failed to scan top answerer row: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,311 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDiscussionJSON = `{
+	"number": 12,
+	"title": "How do I configure the cache?",
+	"url": "https://github.com/org1/repo1/discussions/12",
+	"createdAt": "2025-01-10T10:00:00Z",
+	"updatedAt": "2025-01-12T09:00:00Z",
+	"closed": false,
+	"closedAt": null,
+	"isAnswered": true,
+	"category": {"isAnswerable": true},
+	"author": {"__typename": "User", "login": "alice", "avatarUrl": "https://a", "url": "https://github.com/alice"},
+	"labels": {"nodes": [{"name": "question"}]},
+	"answer": {
+		"url": "https://github.com/org1/repo1/discussions/12#discussioncomment-2",
+		"createdAt": "2025-01-11T10:00:00Z",
+		"updatedAt": "2025-01-11T10:00:00Z",
+		"author": {"__typename": "User", "login": "carol", "avatarUrl": "https://c", "url": "https://github.com/carol"}
+	},
+	"comments": {"nodes": [
+		{
+			"url": "https://github.com/org1/repo1/discussions/12#discussioncomment-1",
+			"createdAt": "2025-01-10T12:00:00Z",
+			"updatedAt": "2025-01-10T12:00:00Z",
+			"isAnswer": false,
+			"author": {"__typename": "Bot", "login": "helper-bot", "avatarUrl": "", "url": ""}
+		},
+		{
+			"url": "https://github.com/org1/repo1/discussions/12#discussioncomment-2",
+			"createdAt": "2025-01-11T10:00:00Z",
+			"updatedAt": "2025-01-11T10:00:00Z",
+			"isAnswer": true,
+			"author": {"__typename": "User", "login": "carol", "avatarUrl": "https://c", "url": "https://github.com/carol"}
+		},
+		{
+			"url": "https://github.com/org1/repo1/discussions/12#discussioncomment-3",
+			"createdAt": "2025-01-12T09:00:00Z",
+			"updatedAt": "2025-01-12T09:00:00Z",
+			"isAnswer": false,
+			"author": null
+		}
+	]}
+}`
+
+func TestDiscussionState(t *testing.T) {
+	n := &discussionNode{IsAnswered: true}
+	n.Category.IsAnswerable = true
+	assert.Equal(t, data.DiscussionStateAnswered, discussionState(n))
+
+	n.IsAnswered = false
+	assert.Equal(t, data.DiscussionStateUnanswered, discussionState(n))
+
+	n.Category.IsAnswerable = false
+	assert.Equal(t, data.DiscussionStateOpen, discussionState(n))
+
+	n.Closed = true
+	assert.Equal(t, data.DiscussionStateClosed, discussionState(n))
+}
+
+func TestAddDiscussion(t *testing.T) {
+	var n discussionNode
+	require.NoError(t, json.Unmarshal([]byte(testDiscussionJSON), &n))
+
+	imp := &eventImporter{
+		owner:  "org1",
+		repo:   "repo1",
+		list:   make([]*data.Event, 0),
+		counts: make(map[string]int),
+		users:  make(map[string]*github.User),
+	}
+	require.NoError(t, imp.addDiscussion(&n))
+
+	// the discussion, the bot reply and the answer once; the deleted
+	// author's comment is skipped
+	require.Len(t, imp.list, 3)
+
+	d := imp.list[0]
+	assert.Equal(t, data.EventTypeDiscussion, d.Type)
+	assert.Equal(t, "alice", d.Username)
+	assert.Equal(t, "2025-01-12", d.Date)
+	assert.Equal(t, data.DiscussionStateAnswered, *d.State)
+	assert.Equal(t, 12, *d.Number)
+	assert.Equal(t, "2025-01-10T10:00:00Z", *d.CreatedAt)
+	assert.Nil(t, d.ClosedAt)
+	assert.Equal(t, "question", d.Labels)
+
+	a := imp.list[1]
+	assert.Equal(t, data.EventTypeDiscussionComment, a.Type)
+	assert.Equal(t, "carol", a.Username)
+	assert.Equal(t, data.DiscussionStateAnswer, *a.State)
+
+	b := imp.list[2]
+	assert.Equal(t, "helper-bot", b.Username)
+	assert.Nil(t, b.State)
+	assert.Equal(t, "Bot", imp.users["helper-bot"].GetType())
+	assert.Equal(t, "https://github.com/carol", imp.users["carol"].GetHTMLURL())
+
+	// the discussion store keys the discussion and comments by URL
+	require.Len(t, imp.discussions, 1)
+	dd := imp.discussions[0]
+	assert.Equal(t, "https://github.com/org1/repo1/discussions/12", dd.URL)
+	assert.Equal(t, "alice", dd.Username)
+	assert.Equal(t, data.DiscussionStateAnswered, dd.State)
+	assert.Equal(t, "2025-01-10T10:00:00Z", dd.CreatedAt)
+	assert.Equal(t, "2025-01-12T09:00:00Z", dd.UpdatedAt)
+	require.NotNil(t, dd.AnswerURL)
+	assert.Equal(t, "https://github.com/org1/repo1/discussions/12#discussioncomment-2", *dd.AnswerURL)
+
+	require.Len(t, imp.discComments, 2)
+	assert.Equal(t, "carol", imp.discComments[0].Username)
+	assert.Equal(t, "2025-01-11T10:00:00Z", imp.discComments[0].CreatedAt)
+	assert.Equal(t, "helper-bot", imp.discComments[1].Username)
+}
+
+func insertDiscussionTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity, is_bot) VALUES
+		('alice', 'Alice', 'ACME', 0), ('bob', 'Bob', 'ACME', 0),
+		('carol', 'Carol', 'INITECH', 0), ('helper-bot', 'Bot', '', 1)`)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	ago := func(h int) string { return now.Add(-time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	day := func(h int) string { return now.Add(-time.Duration(h) * time.Hour).Format("2006-01-02") }
+
+	// discussion 1: Q&A by alice, bot reply after 1h, carol replies after
+	//   4h and her reply is accepted
+	// discussion 2: Q&A by bob, alice replies after 2h, unanswered
+	// discussion 3: announcement by alice, no replies
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at) VALUES
+		('org1', 'repo1', 'alice', 'discussion', ?, 'http://d/1', '', '', 'answered', 1, ?),
+		('org1', 'repo1', 'helper-bot', 'discussion_comment', ?, 'http://d/1#c1', '', '', NULL, 1, ?),
+		('org1', 'repo1', 'carol', 'discussion_comment', ?, 'http://d/1#c2', '', '', 'answer', 1, ?),
+		('org1', 'repo1', 'bob', 'discussion', ?, 'http://d/2', '', '', 'unanswered', 2, ?),
+		('org1', 'repo1', 'alice', 'discussion_comment', ?, 'http://d/2#c3', '', '', NULL, 2, ?),
+		('org1', 'repo1', 'alice', 'discussion', ?, 'http://d/3', '', '', 'open', 3, ?)`,
+		day(100), ago(100),
+		day(99), ago(99),
+		day(96), ago(96),
+		day(60), ago(60),
+		day(58), ago(58),
+		day(30), ago(30))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO discussion (url, org, repo, number, username, state, created_at, updated_at, answer_url) VALUES
+		('http://d/1', 'org1', 'repo1', 1, 'alice', 'answered', ?, ?, 'http://d/1#c2'),
+		('http://d/2', 'org1', 'repo1', 2, 'bob', 'unanswered', ?, ?, NULL),
+		('http://d/3', 'org1', 'repo1', 3, 'alice', 'open', ?, ?, NULL)`,
+		ago(100), ago(96), ago(60), ago(58), ago(30), ago(30))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO discussion_comment (url, org, repo, number, username, created_at, updated_at) VALUES
+		('http://d/1#c1', 'org1', 'repo1', 1, 'helper-bot', ?, ?),
+		('http://d/1#c2', 'org1', 'repo1', 1, 'carol', ?, ?),
+		('http://d/2#c3', 'org1', 'repo1', 2, 'alice', ?, ?)`,
+		ago(99), ago(99), ago(96), ago(96), ago(58), ago(58))
+	require.NoError(t, err)
+}
+
+func TestGetDiscussionSeries_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetDiscussionSeries(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetDiscussionSeries_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetDiscussionSeries(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Empty(t, res.Months)
+}
+
+func TestGetDiscussionSeries_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertDiscussionTestData(t, store)
+
+	res, err := store.GetDiscussionSeries(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+
+	var opened, answerable, answered int
+	for i := range res.Months {
+		opened += res.Opened[i]
+		answerable += res.Answerable[i]
+		answered += res.Answered[i]
+	}
+	assert.Equal(t, 3, opened)
+	assert.Equal(t, 2, answerable)
+	assert.Equal(t, 1, answered)
+
+	// the discussions may span months, check the month of the answered one
+	created := time.Now().UTC().Truncate(time.Hour).Add(-100 * time.Hour).Format("2006-01")
+	for i, m := range res.Months {
+		if m != created {
+			continue
+		}
+		assert.Positive(t, res.AnswerRate[i])
+		assert.InDelta(t, 4.0, res.AnswerHours[i], 0.01)
+	}
+
+	entity := "INITECH"
+	res, err = store.GetDiscussionSeries(&data.InsightsFilter{Months: 6, Entity: &entity})
+	require.NoError(t, err)
+	assert.Empty(t, res.Months)
+}
+
+func TestGetDiscussionSeries_FirstReplySkipsBots(t *testing.T) {
+	store := setupTestDB(t)
+	insertDiscussionTestData(t, store)
+
+	_, err := store.db.Exec(`DELETE FROM discussion WHERE number IN (2, 3)`)
+	require.NoError(t, err)
+
+	res, err := store.GetDiscussionSeries(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, res.Months, 1)
+	assert.InDelta(t, 100.0, res.AnswerRate[0], 0.01)
+	assert.InDelta(t, 4.0, res.FirstReplyHours[0], 0.01)
+}
+
+func TestGetTopAnswerers_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetTopAnswerers(&data.InsightsFilter{Months: 6}, 0)
+	assert.Error(t, err)
+}
+
+func TestGetTopAnswerers_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertDiscussionTestData(t, store)
+
+	list, err := store.GetTopAnswerers(&data.InsightsFilter{Months: 6}, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "carol", list[0].Username)
+	assert.Equal(t, "INITECH", list[0].Entity)
+	assert.Equal(t, 1, list[0].Answers)
+	assert.Equal(t, 1, list[0].Replies)
+
+	// a reply on another discussion the same day and an edit on a later day
+	// are counted once each
+	now := time.Now().UTC().Truncate(time.Hour)
+	_, err = store.db.Exec(`INSERT INTO discussion_comment (url, org, repo, number, username, created_at, updated_at)
+		VALUES ('http://d/2#c4', 'org1', 'repo1', 2, 'carol', ?, ?)`,
+		now.Add(-96*time.Hour).Add(time.Minute).Format(time.RFC3339), now.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	list, err = store.GetTopAnswerers(&data.InsightsFilter{Months: 6}, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 1, list[0].Answers)
+	assert.Equal(t, 2, list[0].Replies)
+}
+
+func TestGetContributorMetrics_IncludeDiscussions(t *testing.T) {
+	store := setupTestDB(t)
+	insertDiscussionTestData(t, store)
+
+	// carol only takes part in discussions
+	f := &data.InsightsFilter{Months: 6}
+	retention, err := store.GetContributorRetention(f)
+	require.NoError(t, err)
+	assert.Empty(t, retention.Months)
+
+	momentum, err := store.GetContributorMomentum(f)
+	require.NoError(t, err)
+	for _, v := range momentum.Active {
+		assert.Equal(t, 0, v)
+	}
+
+	funnel, err := store.GetContributorFunnel(f)
+	require.NoError(t, err)
+	assert.Empty(t, funnel.Months)
+
+	f.IncludeDiscussions = true
+	retention, err = store.GetContributorRetention(f)
+	require.NoError(t, err)
+	var newContributors int
+	for _, v := range retention.New {
+		newContributors += v
+	}
+	assert.Equal(t, 3, newContributors)
+
+	momentum, err = store.GetContributorMomentum(f)
+	require.NoError(t, err)
+	var peak int
+	for _, v := range momentum.Active {
+		peak = max(peak, v)
+	}
+	assert.Positive(t, peak)
+
+	funnel, err = store.GetContributorFunnel(f)
+	require.NoError(t, err)
+	var firstComments int
+	for _, v := range funnel.FirstComment {
+		firstComments += v
+	}
+	assert.Equal(t, 3, firstComments)
+}
@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	diversityKindCode           = "code"
+	diversityKindReviews        = "reviews"
+	diversityKindMaintainership = "maintainership"
+
+	// selectEntityDiversitySQL counts contributions per month and entity:
+	// merged PRs by author (deduplicated by number), submitted reviews
+	// (one per review ID), and maintainer actions, which are approvals and
+	// PR merges by the merging user. Developers without an entity are grouped as one entity unless
+	// the bound exclude argument is true.
+	selectEntityDiversitySQL = `WITH merged AS (
+		SELECT e.username, MAX(e.merged_at) AS merged_at
+		FROM event e
+		WHERE e.type = 'pr'
+		  AND e.number IS NOT NULL
+		  AND e.merged_at IS NOT NULL
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		GROUP BY e.org, e.repo, e.number, e.username
+	),
+	actions AS (
+		SELECT 'code' AS kind, m.username, m.merged_at AS at
+		FROM merged m
+		UNION ALL
+		SELECT 'reviews', r.username, r.submitted_at
+		FROM pr_review r
+		WHERE r.org = COALESCE(?, r.org)
+		  AND r.repo = COALESCE(?, r.repo)
+		UNION ALL
+		SELECT 'maintainership', r.username, r.submitted_at
+		FROM pr_review r
+		WHERE r.state = 'APPROVED'
+		  AND r.org = COALESCE(?, r.org)
+		  AND r.repo = COALESCE(?, r.repo)
+		UNION ALL
+		SELECT 'maintainership', t.actor, t.created_at
+		FROM timeline_event t
+		WHERE t.kind = 'merged'
+		  AND t.actor != ''
+		  AND t.org = COALESCE(?, t.org)
+		  AND t.repo = COALESCE(?, t.repo)
+	)
+	SELECT a.kind, substr(a.at, 1, 7) AS month, IFNULL(d.entity, '') AS entity, COUNT(*) AS items
+	FROM actions a
+	JOIN developer d ON a.username = d.username
+	WHERE a.at >= ?
+	  AND a.at < ?
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  AND (? = 0 OR IFNULL(d.entity, '') != '')
+	  ` + developerFilterSQL + `
+	GROUP BY a.kind, month, entity
+	ORDER BY month
+	`
+)
+
+func newDiversityIndex(months int) *data.DiversityIndex {
+	return &data.DiversityIndex{
+		Actions:  make([]int, months),
+		Entities: make([]int, months),
+		HHI:      make([]float64, months),
+		Entropy:  make([]float64, months),
+	}
+}
+
+// diversity returns the Herfindahl-Hirschman index (0-10000) and the
+// Shannon entropy in bits of the counts.
+func diversity(counts []int) (hhi, entropy float64) {
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0, 0
+	}
+
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(total)
+		hhi += (p * 100) * (p * 100)
+		entropy -= p * math.Log2(p)
+	}
+	return hhi, entropy
+}
+
+// GetEntityDiversity returns the monthly HHI and Shannon entropy of merged
+// PRs, reviews and maintainer actions by entity. Developers without an
+// entity count as one entity, or are left out when excludeUnaffiliated.
+func (s *Store) GetEntityDiversity(f *data.InsightsFilter, excludeUnaffiliated bool) (*data.EntityDiversitySeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(br.query(selectEntityDiversitySQL),
+		f.Org, f.Repo,
+		f.Org, f.Repo,
+		f.Org, f.Repo,
+		f.Org, f.Repo,
+		br.since(), br.until(), f.Entity, excludeUnaffiliated, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity diversity: %w", err)
+	}
+	defer rows.Close()
+
+	// kind -> month -> entity counts
+	counts := make(map[string]map[string][]int)
+	monthSet := make(map[string]bool)
+	for rows.Next() {
+		var kind, month, entity string
+		var items int
+		if err := rows.Scan(&kind, &month, &entity, &items); err != nil {
+			return nil, fmt.Errorf("failed to scan entity diversity row: %w", err)
+		}
+		if counts[kind] == nil {
+			counts[kind] = make(map[string][]int)
+		}
+		counts[kind][month] = append(counts[kind][month], items)
+		monthSet[month] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	months := make([]string, 0, len(monthSet))
+	for m := range monthSet {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+
+	res := &data.EntityDiversitySeries{
+		Months:         months,
+		Code:           newDiversityIndex(len(months)),
+		Reviews:        newDiversityIndex(len(months)),
+		Maintainership: newDiversityIndex(len(months)),
+	}
+	kinds := map[string]*data.DiversityIndex{
+		diversityKindCode:           res.Code,
+		diversityKindReviews:        res.Reviews,
+		diversityKindMaintainership: res.Maintainership,
+	}
+
+	for kind, idx := range kinds {
+		for i, m := range months {
+			c := counts[kind][m]
+			for _, n := range c {
+				idx.Actions[i] += n
+			}
+			idx.Entities[i] = len(c)
+			idx.HHI[i], idx.Entropy[i] = diversity(c)
+		}
+	}
+
+	br.align(res)
+	return res, nil
+}
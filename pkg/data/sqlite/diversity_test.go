@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiversity(t *testing.T) {
+	tests := []struct {
+		name    string
+		counts  []int
+		hhi     float64
+		entropy float64
+	}{
+		{"empty", nil, 0, 0},
+		{"single", []int{7}, 10000, 0},
+		{"even pair", []int{3, 3}, 5000, 1},
+		{"even four", []int{1, 1, 1, 1}, 2500, 2},
+		{"skewed", []int{3, 1}, 6250, 0.8113},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hhi, entropy := diversity(tt.counts)
+			assert.InDelta(t, tt.hhi, hhi, 0.01)
+			assert.InDelta(t, tt.entropy, entropy, 0.0001)
+		})
+	}
+}
+
+func TestGetEntityDiversity_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetEntityDiversity(&data.InsightsFilter{Months: 6}, false)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetEntityDiversity_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES
+		('alice', 'Alice', 'ACME'), ('bob', 'Bob', 'BETA'), ('carol', 'Carol', NULL)`)
+	require.NoError(t, err)
+
+	// PR 1 is stored on two days and counts once
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, merged_at)
+		VALUES
+		('org1', 'repo1', 'alice', 'pr', '2025-01-04', 'http://p/1', '', '', 'open', 1, '2025-01-03T10:00:00Z', NULL),
+		('org1', 'repo1', 'alice', 'pr', '2025-01-05', 'http://p/1', '', '', 'merged', 1, '2025-01-03T10:00:00Z', '2025-01-05T12:00:00Z'),
+		('org1', 'repo1', 'carol', 'pr', '2025-01-06', 'http://p/2', '', '', 'merged', 2, '2025-01-05T10:00:00Z', '2025-01-06T12:00:00Z')`)
+	require.NoError(t, err)
+
+	// bob approves twice on the same day; both reviews count
+	_, err = store.db.Exec(`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+		VALUES
+		(1, 'org1', 'repo1', 1, 'bob', 'APPROVED', '2025-01-05T09:00:00Z'),
+		(2, 'org1', 'repo1', 2, 'bob', 'APPROVED', '2025-01-05T15:00:00Z'),
+		(3, 'org1', 'repo1', 2, 'alice', 'COMMENTED', '2025-01-06T10:00:00Z')`)
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at)
+		VALUES
+		('org1', 'repo1', 1, 'pr', 'merged', 'bob', '', '2025-01-05T12:00:00Z'),
+		('org1', 'repo1', 2, 'pr', 'merged', 'bob', '', '2025-01-06T12:00:00Z')`)
+	require.NoError(t, err)
+
+	f := &data.InsightsFilter{Granularity: data.GranularityMonth, From: strPtr("2025-01-01"), To: strPtr("2025-02-28")}
+	res, err := store.GetEntityDiversity(f, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-01", "2025-02"}, res.Months)
+
+	assert.Equal(t, []int{2, 0}, res.Code.Actions)
+	assert.Equal(t, []int{2, 0}, res.Code.Entities)
+	assert.InDelta(t, 5000, res.Code.HHI[0], 0.01)
+	assert.InDelta(t, 1, res.Code.Entropy[0], 0.0001)
+	assert.Zero(t, res.Code.HHI[1])
+
+	assert.Equal(t, 3, res.Reviews.Actions[0])
+	assert.Equal(t, 2, res.Reviews.Entities[0])
+	assert.InDelta(t, 5555.56, res.Reviews.HHI[0], 0.01)
+
+	assert.Equal(t, 4, res.Maintainership.Actions[0])
+	assert.Equal(t, 1, res.Maintainership.Entities[0])
+	assert.InDelta(t, 10000, res.Maintainership.HHI[0], 0.01)
+	assert.Zero(t, res.Maintainership.Entropy[0])
+
+	res, err = store.GetEntityDiversity(f, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Code.Actions[0])
+	assert.InDelta(t, 10000, res.Code.HHI[0], 0.01)
+	assert.Zero(t, res.Code.Entropy[0])
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"math"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	doraKeyDeploymentFrequency = "deployment_frequency"
+	doraKeyLeadTime            = "lead_time"
+	doraKeyChangeFailureRate   = "change_failure_rate"
+	doraKeyTimeToRestore       = "time_to_restore"
+
+	// doraSteadyChange is the relative change versus the previous period
+	// below which a key is steady.
+	doraSteadyChange = 0.05
+)
+
+// doraKey describes one of the four keys. Thresholds are the elite, high
+// and medium limits of the published DORA performance levels (2023 State
+// of DevOps report); values past the medium limit are low.
+type doraKey struct {
+	key            string
+	name           string
+	unit           string
+	higherIsBetter bool
+	thresholds     [3]float64
+}
+
+var doraKeys = []*doraKey{
+	// on demand (daily or more), daily to weekly, weekly to monthly
+	{doraKeyDeploymentFrequency, "Deployment frequency", "per week", true, [3]float64{7, 1, 7.0 / 30}},
+	// less than a day, a week, a month
+	{doraKeyLeadTime, "Lead time for changes", "hours", false, [3]float64{24, 24 * 7, 24 * 30}},
+	{doraKeyChangeFailureRate, "Change failure rate", "%", false, [3]float64{5, 10, 15}},
+	// less than an hour, a day, a week
+	{doraKeyTimeToRestore, "Time to restore", "hours", false, [3]float64{1, 24, 24 * 7}},
+}
+
+// doraTiers orders the tiers from best to worst.
+var doraTiers = []string{data.DORATierElite, data.DORATierHigh, data.DORATierMedium, data.DORATierLow}
+
+// tier classifies a value of the key.
+func (k *doraKey) tier(v float64) string {
+	for i, t := range k.thresholds {
+		if (k.higherIsBetter && v >= t) || (!k.higherIsBetter && v <= t) {
+			return doraTiers[i]
+		}
+	}
+	return data.DORATierLow
+}
+
+// trend compares a value of the key with the previous period.
+func (k *doraKey) trend(v, prev float64) string {
+	diff := v - prev
+	if math.Abs(diff) <= doraSteadyChange*math.Abs(prev) {
+		return data.DORATrendSteady
+	}
+	if (diff > 0) == k.higherIsBetter {
+		return data.DORATrendImproving
+	}
+	return data.DORATrendDeclining
+}
+
+// doraValue is the value of a key in a period and the number of samples
+// it is computed from.
+type doraValue struct {
+	value   float64
+	samples int
+}
+
+// GetDORASummary returns the four DORA keys of the filter's range, or of
+// its last months, classified into performance tiers, with the trend versus
+// the previous period of the same length. Deployments are releases, or
+// merged PRs in repos without releases; lead time is the median time to
+// merge and time to restore the median time to close bugs reported after a
+// release.
+func (s *Store) GetDORASummary(f *data.InsightsFilter) (*data.DORASummary, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := br.start, br.end
+	prevTo := from.AddDate(0, 0, -1)
+	prevFrom := from.AddDate(0, -f.Months, 0)
+	if f.From != nil || f.To != nil {
+		prevFrom = prevTo.Add(-to.Sub(from))
+	}
+
+	cur, err := s.doraPeriod(f, from, to)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := s.doraPeriod(f, prevFrom, prevTo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &data.DORASummary{
+		From:         from.Format(dateLayout),
+		To:           to.Format(dateLayout),
+		PreviousFrom: prevFrom.Format(dateLayout),
+		PreviousTo:   prevTo.Format(dateLayout),
+		Metrics:      make([]*data.DORAMetric, 0, len(doraKeys)),
+	}
+
+	worst := -1
+	for _, k := range doraKeys {
+		c, p := cur[k.key], prev[k.key]
+		m := &data.DORAMetric{
+			Key:         k.key,
+			Name:        k.name,
+			Unit:        k.unit,
+			Value:       c.value,
+			Samples:     c.samples,
+			Previous:    p.value,
+			PrevSamples: p.samples,
+		}
+		if c.samples > 0 {
+			m.Tier = k.tier(c.value)
+			for i, t := range doraTiers {
+				if t == m.Tier && i > worst {
+					worst = i
+				}
+			}
+		}
+		if p.samples > 0 {
+			m.PreviousTier = k.tier(p.value)
+		}
+		if c.samples > 0 && p.samples > 0 {
+			m.Trend = k.trend(c.value, p.value)
+		}
+		res.Metrics = append(res.Metrics, m)
+	}
+	if worst >= 0 {
+		res.Tier = doraTiers[worst]
+	}
+
+	return res, nil
+}
+
+// doraPeriod computes the four keys for the days from through to using the
+// release cadence, change failure rate and velocity queries with the rest
+// of the filter.
+func (s *Store) doraPeriod(filter *data.InsightsFilter, from, to time.Time) (map[string]doraValue, error) {
+	since, until := from.Format(dateLayout), to.Format(dateLayout)
+	f := *filter
+	f.Granularity, f.From, f.To = "", &since, &until
+	br, err := newBucketRange(&f)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]doraValue, len(doraKeys))
+
+	cadence, err := s.GetReleaseCadence(&f)
+	if err != nil {
+		return nil, err
+	}
+	var deployments int
+	for _, n := range cadence.Deployments {
+		deployments += n
+	}
+	weeks := (to.Sub(from).Hours()/24 + 1) / 7
+	res[doraKeyDeploymentFrequency] = doraValue{float64(deployments) / weeks, deployments}
+
+	cfr, err := s.GetChangeFailureRate(&f)
+	if err != nil {
+		return nil, err
+	}
+	var failures, released int
+	for i := range cfr.Months {
+		failures += cfr.Failures[i]
+		released += cfr.Deployments[i]
+	}
+	if released > 0 {
+		res[doraKeyChangeFailureRate] = doraValue{float64(failures) / float64(released) * 100, released}
+	}
+
+	leadTime, err := s.queryVelocitySamples(selectTimeToMergeSQL, &f, br)
+	if err != nil {
+		return nil, err
+	}
+	restore, err := s.queryRestoreSamples(&f, br)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, samples := range map[string]*durationSamples{
+		doraKeyLeadTime:      leadTime,
+		doraKeyTimeToRestore: restore,
+	} {
+		hours := make([]float64, 0)
+		for i, m := range samples.months {
+			if m == bucketOverflow {
+				continue
+			}
+			for _, days := range samples.values[i] {
+				hours = append(hours, days*24)
+			}
+		}
+		st := summarizeDurations(hours)
+		res[key] = doraValue{st.p50, st.count}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDORAKey_Tier(t *testing.T) {
+	keys := make(map[string]*doraKey, len(doraKeys))
+	for _, k := range doraKeys {
+		keys[k.key] = k
+	}
+
+	tests := []struct {
+		key   string
+		value float64
+		tier  string
+	}{
+		{doraKeyDeploymentFrequency, 10, data.DORATierElite},
+		{doraKeyDeploymentFrequency, 2, data.DORATierHigh},
+		{doraKeyDeploymentFrequency, 0.5, data.DORATierMedium},
+		{doraKeyDeploymentFrequency, 0.1, data.DORATierLow},
+		{doraKeyLeadTime, 12, data.DORATierElite},
+		{doraKeyLeadTime, 72, data.DORATierHigh},
+		{doraKeyLeadTime, 300, data.DORATierMedium},
+		{doraKeyLeadTime, 1000, data.DORATierLow},
+		{doraKeyChangeFailureRate, 5, data.DORATierElite},
+		{doraKeyChangeFailureRate, 8, data.DORATierHigh},
+		{doraKeyChangeFailureRate, 15, data.DORATierMedium},
+		{doraKeyChangeFailureRate, 40, data.DORATierLow},
+		{doraKeyTimeToRestore, 0.5, data.DORATierElite},
+		{doraKeyTimeToRestore, 2, data.DORATierHigh},
+		{doraKeyTimeToRestore, 48, data.DORATierMedium},
+		{doraKeyTimeToRestore, 200, data.DORATierLow},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.tier, keys[tt.key].tier(tt.value), "%s %v", tt.key, tt.value)
+	}
+}
+
+func TestDORAKey_Trend(t *testing.T) {
+	freq, lead := doraKeys[0], doraKeys[1]
+	assert.Equal(t, data.DORATrendImproving, freq.trend(2, 1))
+	assert.Equal(t, data.DORATrendDeclining, freq.trend(1, 2))
+	assert.Equal(t, data.DORATrendImproving, lead.trend(10, 20))
+	assert.Equal(t, data.DORATrendDeclining, lead.trend(20, 10))
+	assert.Equal(t, data.DORATrendSteady, lead.trend(10.2, 10))
+	assert.Equal(t, data.DORATrendSteady, lead.trend(0, 0))
+}
+
+func TestGetDORASummary_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetDORASummary(&data.InsightsFilter{Months: 3})
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetDORASummary_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	ts := func(days, hours int) string {
+		return today.AddDate(0, 0, -days).Add(time.Duration(hours) * time.Hour).Format(time.RFC3339)
+	}
+	day := func(days int) string {
+		return today.AddDate(0, 0, -days).Format(dateLayout)
+	}
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice')`)
+	require.NoError(t, err)
+
+	// three releases in the period, one in the previous period
+	_, err = store.db.Exec(`INSERT INTO release (org, repo, tag, name, published_at, prerelease) VALUES
+		('org1', 'repo1', 'v1.3', 'v1.3', ?, 0),
+		('org1', 'repo1', 'v1.2', 'v1.2', ?, 0),
+		('org1', 'repo1', 'v1.1', 'v1.1', ?, 0),
+		('org1', 'repo1', 'v1.0', 'v1.0', ?, 0)`,
+		ts(5, 0), ts(10, 0), ts(15, 0), ts(40, 0))
+	require.NoError(t, err)
+
+	// a PR merged in 12 hours now and in 10 days before; a bug reported a
+	// day after a release and closed in 2 hours
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, merged_at, closed_at, title)
+		VALUES
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/1', '', '', 'merged', 1, ?, ?, NULL, 'Add cache'),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/2', '', '', 'merged', 2, ?, ?, NULL, 'Add api'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/3', '', 'bug', 'closed', 3, ?, NULL, ?, 'Crash')`,
+		day(12), ts(12, 0), ts(12, 12),
+		day(45), ts(45, 0), ts(35, 0),
+		day(4), ts(4, 0), ts(4, 2))
+	require.NoError(t, err)
+
+	org, repo := "org1", "repo1"
+	res, err := store.GetDORASummary(&data.InsightsFilter{Org: &org, Repo: &repo, Months: 1})
+	require.NoError(t, err)
+	assert.Equal(t, day(0), res.To)
+	require.Len(t, res.Metrics, 4)
+
+	freq := res.Metrics[0]
+	assert.Equal(t, doraKeyDeploymentFrequency, freq.Key)
+	assert.Equal(t, 3, freq.Samples)
+	assert.Equal(t, data.DORATierMedium, freq.Tier)
+	assert.Equal(t, 1, freq.PrevSamples)
+	assert.Equal(t, data.DORATrendImproving, freq.Trend)
+
+	lead := res.Metrics[1]
+	assert.InDelta(t, 12, lead.Value, 0.01)
+	assert.Equal(t, data.DORATierElite, lead.Tier)
+	assert.InDelta(t, 240, lead.Previous, 0.01)
+	assert.Equal(t, data.DORATierMedium, lead.PreviousTier)
+	assert.Equal(t, data.DORATrendImproving, lead.Trend)
+
+	cfr := res.Metrics[2]
+	assert.InDelta(t, 100.0/3, cfr.Value, 0.01)
+	assert.Equal(t, data.DORATierLow, cfr.Tier)
+	assert.Equal(t, data.DORATierElite, cfr.PreviousTier)
+	assert.Equal(t, data.DORATrendDeclining, cfr.Trend)
+
+	restore := res.Metrics[3]
+	assert.InDelta(t, 2, restore.Value, 0.01)
+	assert.Equal(t, data.DORATierHigh, restore.Tier)
+	assert.Equal(t, 0, restore.PrevSamples)
+	assert.Empty(t, restore.PreviousTier)
+	assert.Empty(t, restore.Trend)
+
+	assert.Equal(t, data.DORATierLow, res.Tier)
+
+	// an explicit range is compared with the same number of days before it
+	from, to := day(20), day(0)
+	res, err = store.GetDORASummary(&data.InsightsFilter{Org: &org, Repo: &repo, From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, day(41), res.PreviousFrom)
+	assert.Equal(t, day(21), res.PreviousTo)
+	assert.Equal(t, 3, res.Metrics[0].Samples)
+	assert.Equal(t, 1, res.Metrics[0].PrevSamples)
+	assert.Equal(t, 1, res.Metrics[1].Samples)
+
+	// the entity filter applies to PRs and bugs, not to releases
+	entity := "INITECH"
+	res, err = store.GetDORASummary(&data.InsightsFilter{Org: &org, Repo: &repo, Entity: &entity, Months: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Metrics[0].Samples)
+	assert.Zero(t, res.Metrics[1].Samples)
+	assert.Zero(t, res.Metrics[3].Samples)
+}
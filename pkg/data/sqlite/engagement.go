@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	mostWantedLimitDefault = 10
+
+	upsertEngagementSQL = `INSERT INTO engagement (
+			org, repo, number, item_type, comments, reactions,
+			plus_one, minus_one, laugh, hooray, confused, heart, rocket, eyes, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(org, repo, number) DO UPDATE SET
+			item_type = excluded.item_type,
+			comments = excluded.comments,
+			reactions = excluded.reactions,
+			plus_one = excluded.plus_one,
+			minus_one = excluded.minus_one,
+			laugh = excluded.laugh,
+			hooray = excluded.hooray,
+			confused = excluded.confused,
+			heart = excluded.heart,
+			rocket = excluded.rocket,
+			eyes = excluded.eyes,
+			updated_at = excluded.updated_at
+	`
+
+	// selectMostWantedSQL ranks currently open issues by thumbs-up reactions
+	// and the number of distinct non-bot commenters in the window. The state
+	// of an issue is taken from its most recent row.
+	selectMostWantedSQL = `WITH latest AS (
+		SELECT e.org, e.repo, e.number, e.username, e.state, e.title, e.url, e.created_at,
+			ROW_NUMBER() OVER (PARTITION BY e.org, e.repo, e.number ORDER BY e.date DESC) AS rn
+		FROM event e
+		WHERE e.type = 'issue'
+		  AND e.number IS NOT NULL
+		  AND e.url NOT LIKE '%/pull/%'
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+	),
+	ranked AS (
+		SELECT
+			l.org, l.repo, l.number,
+			IFNULL(l.title, '') AS title,
+			l.url,
+			IFNULL(l.created_at, '') AS created_at,
+			IFNULL(g.plus_one, 0) AS plus_one,
+			IFNULL(g.reactions, 0) AS reactions,
+			IFNULL(g.comments, 0) AS comments,
+			(
+				SELECT COUNT(DISTINCT c.username)
+				FROM event c
+				JOIN developer cd ON c.username = cd.username
+				WHERE c.org = l.org AND c.repo = l.repo AND c.number = l.number
+				  AND c.type = 'issue_comment'
+				  AND c.date >= ?
+				  AND c.date < ?
+				  AND IFNULL(cd.is_bot, 0) = 0
+			) AS commenters
+		FROM latest l
+		JOIN developer d ON l.username = d.username
+		LEFT JOIN engagement g ON g.org = l.org AND g.repo = l.repo AND g.number = l.number
+		WHERE l.rn = 1
+		  AND l.state = 'open'
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  ` + developerFilterSQL + `
+	)
+	SELECT org, repo, number, title, url, created_at, plus_one, reactions, comments, commenters
+	FROM ranked
+	WHERE plus_one > 0 OR commenters > 0
+	ORDER BY plus_one DESC, commenters DESC, reactions DESC, number
+	LIMIT ?
+	`
+)
+
+// mapEngagement captures the comment count and reaction totals of an issue
+// or PR from the issues list.
+func mapEngagement(org, repo, itemType string, item *github.Issue) *data.Engagement {
+	g := &data.Engagement{
+		Org:      org,
+		Repo:     repo,
+		Number:   item.GetNumber(),
+		ItemType: itemType,
+		Comments: item.GetComments(),
+	}
+	if r := item.Reactions; r != nil {
+		g.Reactions = r.GetTotalCount()
+		g.PlusOne = r.GetPlusOne()
+		g.MinusOne = r.GetMinusOne()
+		g.Laugh = r.GetLaugh()
+		g.Hooray = r.GetHooray()
+		g.Confused = r.GetConfused()
+		g.Heart = r.GetHeart()
+		g.Rocket = r.GetRocket()
+		g.Eyes = r.GetEyes()
+	}
+	return g
+}
+
+// GetMostWanted returns open issues ranked by thumbs-up reactions and the
+// number of distinct commenters in the window.
+func (s *Store) GetMostWanted(f *data.InsightsFilter, limit int) ([]*data.WantedIssue, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	if limit <= 0 {
+		limit = mostWantedLimitDefault
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectMostWantedSQL,
+		f.Org, f.Repo, since, br.until(), f.Entity, f.IncludeBots, f.Cohort, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query most wanted issues: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.WantedIssue, 0)
+	for rows.Next() {
+		w := &data.WantedIssue{}
+		if err := rows.Scan(&w.Org, &w.Repo, &w.Number, &w.Title, &w.URL, &w.CreatedAt,
+			&w.PlusOne, &w.Reactions, &w.Comments, &w.Commenters); err != nil {
+			return nil, fmt.Errorf("failed to scan most wanted row: %w", err)
+		}
+		list = append(list, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
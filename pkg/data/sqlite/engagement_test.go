@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertEngagementTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, is_bot) VALUES
+		('alice', 'Alice', 0), ('bob', 'Bob', 0), ('carol', 'Carol', 0), ('ci-bot', 'CI', 1)`)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	day := func(d int) string { return now.AddDate(0, 0, -d).Format("2006-01-02") }
+
+	// issue 1: open, 5 thumbs-up, commented by bob and the bot
+	// issue 2: open, 1 thumbs-up, commented by bob and carol
+	// issue 3: closed on its latest row, 9 thumbs-up
+	// issue 4: open, no engagement
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, title) VALUES
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/1', '', '', 'open', 1, 'Dark mode'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/2', '', '', 'open', 2, 'Export'),
+		('org1', 'repo1', 'carol', 'issue', ?, 'http://i/issues/3', '', '', 'open', 3, 'Old'),
+		('org1', 'repo1', 'carol', 'issue', ?, 'http://i/issues/3', '', '', 'closed', 3, 'Old'),
+		('org1', 'repo1', 'carol', 'issue', ?, 'http://i/issues/4', '', '', 'open', 4, 'Quiet'),
+		('org1', 'repo1', 'bob', 'issue_comment', ?, 'http://i/issues/1#c1', '', '', NULL, 1, ''),
+		('org1', 'repo1', 'ci-bot', 'issue_comment', ?, 'http://i/issues/1#c2', '', '', NULL, 1, ''),
+		('org1', 'repo1', 'bob', 'issue_comment', ?, 'http://i/issues/2#c3', '', '', NULL, 2, ''),
+		('org1', 'repo1', 'carol', 'issue_comment', ?, 'http://i/issues/2#c4', '', '', NULL, 2, '')`,
+		day(20), day(19), day(30), day(5), day(18), day(10), day(10), day(9), day(8))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO engagement (org, repo, number, item_type, comments, reactions, plus_one, heart, updated_at) VALUES
+		('org1', 'repo1', 1, 'issue', 2, 6, 5, 1, '2025-01-01T00:00:00Z'),
+		('org1', 'repo1', 2, 'issue', 2, 1, 1, 0, '2025-01-01T00:00:00Z'),
+		('org1', 'repo1', 3, 'issue', 0, 9, 9, 0, '2025-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+}
+
+func TestMapEngagement(t *testing.T) {
+	item := &github.Issue{
+		Number:   github.Ptr(7),
+		Comments: github.Ptr(3),
+		Reactions: &github.Reactions{
+			TotalCount: github.Ptr(4),
+			PlusOne:    github.Ptr(2),
+			Heart:      github.Ptr(1),
+			Eyes:       github.Ptr(1),
+		},
+	}
+
+	g := mapEngagement("org1", "repo1", data.EventTypeIssue, item)
+	assert.Equal(t, 7, g.Number)
+	assert.Equal(t, 3, g.Comments)
+	assert.Equal(t, 4, g.Reactions)
+	assert.Equal(t, 2, g.PlusOne)
+	assert.Equal(t, 1, g.Heart)
+	assert.Equal(t, 1, g.Eyes)
+
+	g = mapEngagement("org1", "repo1", data.EventTypePR, &github.Issue{Number: github.Ptr(8)})
+	assert.Equal(t, data.EventTypePR, g.ItemType)
+	assert.Equal(t, 0, g.Reactions)
+}
+
+func TestGetMostWanted_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetMostWanted(&data.InsightsFilter{Months: 6}, 0)
+	assert.Error(t, err)
+}
+
+func TestGetMostWanted_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	list, err := store.GetMostWanted(&data.InsightsFilter{Months: 6}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestGetMostWanted_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertEngagementTestData(t, store)
+
+	list, err := store.GetMostWanted(&data.InsightsFilter{Months: 6}, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	assert.Equal(t, 1, list[0].Number)
+	assert.Equal(t, "Dark mode", list[0].Title)
+	assert.Equal(t, 5, list[0].PlusOne)
+	assert.Equal(t, 6, list[0].Reactions)
+	assert.Equal(t, 1, list[0].Commenters)
+
+	assert.Equal(t, 2, list[1].Number)
+	assert.Equal(t, 2, list[1].Commenters)
+
+	list, err = store.GetMostWanted(&data.InsightsFilter{Months: 6}, 1)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestSearchEvents_MinReactions(t *testing.T) {
+	store := setupTestDB(t)
+	insertEngagementTestData(t, store)
+
+	// event search matches entity with = and scans profile fields as strings
+	_, err := store.db.Exec(`UPDATE developer SET entity = 'ACME', email = '', avatar = '', url = ''`)
+	require.NoError(t, err)
+
+	issueType := data.EventTypeIssue
+	minReactions := 5
+	q := &data.EventSearchCriteria{Type: &issueType, MinReactions: &minReactions, IncludeBots: true, PageSize: 10, Page: 1}
+	results, err := store.SearchEvents(q)
+	require.NoError(t, err)
+	// issue 1 and both rows of issue 3
+	assert.Len(t, results, 3)
+
+	q.MinReactions = nil
+	results, err = store.SearchEvents(q)
+	require.NoError(t, err)
+	assert.Len(t, results, 5)
+}
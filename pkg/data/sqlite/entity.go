@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/mchmarny/devpulse/pkg/data"
 )
@@ -39,7 +42,7 @@ const (
 		LIMIT ?
 	`
 
-	selectEntityNamesSQL = `SELECT DISTINCT entity FROM developer WHERE entity IS NOT NULL and entity != ''`
+	selectEntityNamesSQL = `SELECT entity, COUNT(*) FROM developer WHERE entity IS NOT NULL and entity != '' GROUP BY entity`
 
 	updateEntityNamesSQL = `UPDATE developer SET entity = ? WHERE entity = ?`
 )
@@ -161,66 +164,171 @@ func (s *Store) QueryEntities(val string, limit int) ([]*data.CountedItem, error
 }
 
 func (s *Store) CleanEntities() error {
+	_, err := s.NormalizeEntities(false)
+	return err
+}
+
+// NormalizeEntities applies the entity normalization rules to all developer
+// affiliations and returns the resulting changes. When dryRun is true the
+// changes are only computed, not applied.
+func (s *Store) NormalizeEntities(dryRun bool) ([]*data.EntityChange, error) {
 	if s.db == nil {
-		return data.ErrDBNotInitialized
+		return nil, data.ErrDBNotInitialized
 	}
 
 	stmt, err := s.db.Prepare(selectEntityNamesSQL)
 	if err != nil {
-		return fmt.Errorf("failed to prepare developer query statement: %w", err)
+		return nil, fmt.Errorf("failed to prepare developer query statement: %w", err)
 	}
 	defer stmt.Close()
 
-	m := make(map[string]string)
 	rows, err := stmt.Query()
 	if err != nil {
-		return fmt.Errorf("failed to execute select statement: %w", err)
+		return nil, fmt.Errorf("failed to execute select statement: %w", err)
 	}
 	defer rows.Close()
 
+	n := s.entityNormalizer()
+	list := make([]*data.EntityChange, 0)
 	for rows.Next() {
-		var name string
-		if err = rows.Scan(&name); err != nil {
-			return fmt.Errorf("failed to scan row: %w", err)
+		c := &data.EntityChange{}
+		if err = rows.Scan(&c.From, &c.Developers); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		c.To = n.clean(c.From)
+		if c.To != c.From {
+			list = append(list, c)
 		}
-		m[name] = cleanEntityName(name)
 	}
 
 	if err = rows.Err(); err != nil {
-		return fmt.Errorf("error iterating rows: %w", err)
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].To != list[j].To {
+			return list[i].To < list[j].To
+		}
+		return list[i].From < list[j].From
+	})
+
+	if dryRun || len(list) == 0 {
+		return list, nil
 	}
 
 	updateStmt, err := s.db.Prepare(updateEntityNamesSQL)
 	if err != nil {
-		return fmt.Errorf("failed to prepare entity update statement: %w", err)
+		return nil, fmt.Errorf("failed to prepare entity update statement: %w", err)
 	}
 	defer updateStmt.Close()
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	txStmt := tx.Stmt(updateStmt)
-	for old, new := range m {
-		if _, err = txStmt.Exec(new, old); err != nil {
+	for _, c := range list {
+		if _, err = txStmt.Exec(c.To, c.From); err != nil {
 			rollbackTransaction(tx)
-			return fmt.Errorf("error updating entity %s to %s: %w", old, new, err)
+			return nil, fmt.Errorf("error updating entity %s to %s: %w", c.From, c.To, err)
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return list, nil
+}
+
+// entityNormalizer applies compiled data.EntityRules to entity names.
+type entityNormalizer struct {
+	rewrites   []*entityRewrite
+	noise      map[string]bool
+	aliases    map[string]string
+	exclusions map[string]bool
+}
+
+type entityRewrite struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+var defaultEntityNormalizer = sync.OnceValue(func() *entityNormalizer {
+	n, err := newEntityNormalizer(data.DefaultEntityRules())
+	if err != nil {
+		panic(fmt.Sprintf("invalid default entity rules: %v", err))
+	}
+	return n
+})
+
+func newEntityNormalizer(r *data.EntityRules) (*entityNormalizer, error) {
+	if r == nil {
+		return nil, errors.New("entity rules are required")
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	n := &entityNormalizer{
+		rewrites:   make([]*entityRewrite, 0, len(r.Rewrites)),
+		noise:      make(map[string]bool, len(r.Noise)),
+		aliases:    make(map[string]string),
+		exclusions: make(map[string]bool, len(r.Exclusions)),
+	}
+
+	for _, rw := range r.Rewrites {
+		re, err := regexp.Compile(rw.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entity rewrite pattern %q: %w", rw.Pattern, err)
+		}
+		n.rewrites = append(n.rewrites, &entityRewrite{re: re, replace: rw.Replace})
+	}
+
+	// names lose their punctuation before noise is matched, so tokens do too
+	for _, v := range r.Noise {
+		if t := entityRegEx.ReplaceAllString(strings.ToUpper(strings.TrimSpace(v)), ""); t != "" {
+			n.noise[t] = true
+		}
+	}
+
+	for name, aliases := range r.Aliases {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		for _, a := range aliases {
+			n.aliases[strings.ToUpper(strings.TrimSpace(a))] = name
+		}
+	}
+
+	for _, v := range r.Exclusions {
+		n.exclusions[strings.ToUpper(strings.TrimSpace(v))] = true
+	}
+
+	return n, nil
 }
 
-func cleanEntityName(val string) string {
+// entityNormalizer returns the store's normalizer or the built-in default.
+func (s *Store) entityNormalizer() *entityNormalizer {
+	if s.normalizer != nil {
+		return s.normalizer
+	}
+	return defaultEntityNormalizer()
+}
+
+// clean normalizes val. Excluded names return an empty (unaffiliated) entity.
+func (n *entityNormalizer) clean(val string) string {
 	original := val
 	val = strings.ToUpper(strings.TrimSpace(val))
 
-	if name, ok := entitySubstitutions[val]; ok {
+	if n.exclusions[val] {
+		return ""
+	}
+
+	for _, rw := range n.rewrites {
+		val = rw.re.ReplaceAllString(val, rw.replace)
+	}
+
+	if name, ok := n.aliases[val]; ok {
 		val = name
 	}
 
@@ -231,17 +339,24 @@ func cleanEntityName(val string) string {
 		if len(strings.ToUpper(strings.TrimSpace(part))) == 0 {
 			continue
 		}
-		if _, ok := entityNoise[part]; !ok {
-			parts = append(parts, part)
-		}
+		parts = append(parts, part)
+	}
+
+	// noise tokens are legal-form suffixes, so only trailing ones are removed
+	for len(parts) > 1 && n.noise[parts[len(parts)-1]] {
+		parts = parts[:len(parts)-1]
 	}
 
 	val = strings.Join(parts, " ")
 
-	if name, ok := entitySubstitutions[val]; ok {
+	if name, ok := n.aliases[val]; ok {
 		val = name
 	}
 
+	if n.exclusions[val] {
+		return ""
+	}
+
 	if len(val) > 0 {
 		slog.Debug("cleaned entity name", "original", original, "cleaned", val)
 	}
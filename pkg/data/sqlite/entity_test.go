@@ -1,6 +1,8 @@
 package sqlite
 
 import (
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -34,10 +36,10 @@ func seedTestData(t *testing.T, store *Store) {
 			e.Org, e.Repo, e.Username, e.Type, e.Date,
 			e.URL, e.Mentions, e.Labels,
 			e.State, e.Number, e.CreatedAt, e.ClosedAt, e.MergedAt, e.Additions, e.Deletions,
-			e.ChangedFiles, e.Commits, e.Title,
+			e.ChangedFiles, e.Commits, e.Title, e.StateReason, e.Draft, e.EventAt, e.AuthorAssociation,
 			e.URL, e.Mentions, e.Labels,
 			e.State, e.Number, e.CreatedAt, e.ClosedAt, e.MergedAt, e.Additions, e.Deletions,
-			e.ChangedFiles, e.Commits, e.Title,
+			e.ChangedFiles, e.Commits, e.Title, e.StateReason, e.Draft, e.EventAt, e.AuthorAssociation,
 		)
 		require.NoError(t, err)
 	}
@@ -138,11 +140,104 @@ func TestCleanEntity(t *testing.T) {
 		"Mercari Inc":          "MERCARI",
 		"Some Company Corp.":   "SOME",
 		"Big Cars LLC.":        "BIG CARS",
+		"Inc Labs Inc":         "INC LABS",
+		"ACME B.V.":            "ACME",
+		"Acme S.A.":            "ACME",
+		"Group One Co Ltd":     "GROUP ONE",
 		"International Business Machines Corporation": "IBM",
 	}
 
 	for input, expected := range tests {
-		val := cleanEntityName(input)
+		val := defaultEntityNormalizer().clean(input)
 		assert.Equal(t, expected, val)
 	}
 }
+
+func TestCleanEntity_Exclusions(t *testing.T) {
+	n := defaultEntityNormalizer()
+	for _, v := range []string{"N/A", "n/a", "Freelance", "Self-Employed", "(Unknown)", "NotFound"} {
+		assert.Empty(t, n.clean(v), v)
+	}
+	assert.Equal(t, "GOOGLE", n.clean("gcp"))
+}
+
+func TestNewEntityNormalizer_Rules(t *testing.T) {
+	n, err := newEntityNormalizer(&data.EntityRules{
+		Version:    1,
+		Rewrites:   []*data.EntityRewrite{{Pattern: `^ACME\b.*`, Replace: "ACME"}},
+		Noise:      []string{"inc"},
+		Aliases:    map[string][]string{"Initech": {"initech labs"}},
+		Exclusions: []string{"stealth"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ACME", n.clean("Acme Robotics EMEA"))
+	assert.Equal(t, "INITECH", n.clean("Initech Labs Inc"))
+	assert.Equal(t, "GLOBEX", n.clean("Globex Inc"))
+	assert.Empty(t, n.clean("Stealth"))
+
+	_, err = newEntityNormalizer(nil)
+	assert.Error(t, err)
+	_, err = newEntityNormalizer(&data.EntityRules{Version: 0})
+	assert.Error(t, err)
+}
+
+func TestNormalizeEntities_DryRun(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "dev1", Entity: "Google LLC"},
+		{Username: "dev2", Entity: "GCP"},
+		{Username: "dev3", Entity: "GOOGLE"},
+		{Username: "dev4", Entity: "N/A"},
+	}))
+
+	list, err := store.NormalizeEntities(true)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, &data.EntityChange{From: "N/A", To: "", Developers: 1}, list[0])
+	assert.Equal(t, "GOOGLE", list[1].To)
+	assert.Equal(t, "GOOGLE", list[2].To)
+
+	dev, err := store.GetDeveloper("dev2")
+	require.NoError(t, err)
+	assert.Equal(t, "GCP", dev.Entity)
+
+	list, err = store.NormalizeEntities(false)
+	require.NoError(t, err)
+	assert.Len(t, list, 3)
+
+	dev, err = store.GetDeveloper("dev2")
+	require.NoError(t, err)
+	assert.Equal(t, "GOOGLE", dev.Entity)
+
+	dev, err = store.GetDeveloper("dev4")
+	require.NoError(t, err)
+	assert.Empty(t, dev.Entity)
+
+	list, err = store.NormalizeEntities(true)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestNew_EntityRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, data.EntityRulesFileName),
+		[]byte("version: 1\naliases:\n  INITECH: [INITECH LABS]\n"), 0600))
+
+	store, err := New(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	assert.Equal(t, "INITECH", store.entityNormalizer().clean("Initech Labs"))
+	assert.Equal(t, "GCP", store.entityNormalizer().clean("GCP"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, data.EntityRulesFileName), []byte("version: 7\n"), 0600))
+	_, err = New(filepath.Join(dir, "other.db"))
+	assert.Error(t, err)
+}
+
+func TestNormalizeEntities_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.NormalizeEntities(true)
+	assert.Error(t, err)
+}
@@ -48,9 +48,9 @@ const (
 	insertEventSQL = `INSERT INTO event (
 			org, repo, username, type, date, url, mentions, labels,
 			state, number, created_at, closed_at, merged_at, additions, deletions,
-			changed_files, commits, title
+			changed_files, commits, title, state_reason, draft, event_at, author_association
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(org, repo, username, type, date) DO UPDATE SET
 			url = ?, mentions = ?, labels = ?,
 			state = COALESCE(?, event.state),
@@ -62,7 +62,19 @@ const (
 			deletions = COALESCE(?, event.deletions),
 			changed_files = COALESCE(?, event.changed_files),
 			commits = COALESCE(?, event.commits),
-			title = ?
+			title = ?,
+			state_reason = COALESCE(?, event.state_reason),
+			draft = COALESCE(?, event.draft),
+			event_at = COALESCE(?, event.event_at),
+			author_association = COALESCE(?, event.author_association)
+	`
+
+	// upsertEventItemSQL records the time of each imported item: its
+	// creation time, or its last update when it has none.
+	upsertEventItemSQL = `INSERT INTO event_item (type, url, org, repo, username, at)
+		VALUES (?, ?, ?, ?, ?, COALESCE(?, ?))
+		ON CONFLICT(type, url) DO UPDATE SET
+			at = excluded.at
 	`
 )
 
@@ -71,7 +83,9 @@ var EventTypes = []string{
 	data.EventTypeIssue,
 	data.EventTypeIssueComment,
 	data.EventTypePRReview,
+	data.EventTypePRReviewComment,
 	data.EventTypeFork,
+	data.EventTypeDiscussion,
 }
 
 type importerFunc func(ctx context.Context) error
@@ -139,6 +153,7 @@ func (s *Store) ImportEvents(ctx context.Context, token, owner, repo string, mon
 		owner:        owner,
 		repo:         repo,
 		list:         make([]*data.Event, 0),
+		timeline:     make([]*data.TimelineEvent, 0),
 		counts:       make(map[string]int),
 		users:        make(map[string]*github.User),
 		state:        make(map[string]*data.State),
@@ -147,10 +162,11 @@ func (s *Store) ImportEvents(ctx context.Context, token, owner, repo string, mon
 
 	importers := []importerFunc{
 		imp.importPREvents,
-		imp.importPRReviewEvents,
+		imp.importPRReviewCommentEvents,
 		imp.importIssueEvents,
 		imp.importIssueCommentEvents,
 		imp.importForkEvents,
+		imp.importDiscussionEvents,
 	}
 
 	if err := imp.loadState(); err != nil {
@@ -193,6 +209,14 @@ func (s *Store) ImportEvents(ctx context.Context, token, owner, repo string, mon
 		slog.Warn("error backfilling PR size data", "repo", owner+"/"+repo, "error", err)
 	}
 
+	if err := imp.linkConnectedIssues(ctx); err != nil {
+		slog.Warn("error linking connected issues", "repo", owner+"/"+repo, "error", err)
+	}
+
+	if err := imp.updateReadyForReview(ctx); err != nil {
+		slog.Warn("error updating PR ready for review times", "repo", owner+"/"+repo, "error", err)
+	}
+
 	total := 0
 	for _, v := range imp.counts {
 		total += v
@@ -220,6 +244,12 @@ type eventImporter struct {
 	owner        string
 	repo         string
 	list         []*data.Event
+	timeline     []*data.TimelineEvent
+	reviews      []*data.PRReview
+	discussions  []*data.Discussion
+	discComments []*data.DiscussionComment
+	links        []*data.PRIssueLink
+	engagement   []*data.Engagement
 	counts       map[string]int
 	users        map[string]*github.User
 	state        map[string]*data.State
@@ -232,25 +262,30 @@ func (e *eventImporter) qualifyTypeKey(t string) string {
 }
 
 type eventExtra struct {
-	State        *string
-	Number       *int
-	CreatedAt    *string
-	ClosedAt     *string
-	MergedAt     *string
-	Additions    *int
-	Deletions    *int
-	ChangedFiles *int
-	Commits      *int
-	Title        string
+	State             *string
+	Number            *int
+	CreatedAt         *string
+	ClosedAt          *string
+	MergedAt          *string
+	Additions         *int
+	Deletions         *int
+	ChangedFiles      *int
+	Commits           *int
+	Title             string
+	StateReason       *string
+	Draft             *bool
+	AuthorAssociation *string
 }
 
 func (e *eventImporter) add(eType, url string, usr *github.User, updated *time.Time, mentions []string, labels []string, extra *eventExtra) error {
+	eventAt := ghutil.ParseTime(updated)
 	item := &data.Event{
 		Org:      e.owner,
 		Repo:     e.repo,
 		Username: usr.GetLogin(),
 		Type:     eType,
 		Date:     ghutil.ParseDate(updated),
+		EventAt:  &eventAt,
 		URL:      url,
 		Mentions: strings.Join(unique(mentions), ","),
 		Labels:   strings.Join(unique(labels), ","),
@@ -267,6 +302,9 @@ func (e *eventImporter) add(eType, url string, usr *github.User, updated *time.T
 		item.ChangedFiles = extra.ChangedFiles
 		item.Commits = extra.Commits
 		item.Title = extra.Title
+		item.StateReason = extra.StateReason
+		item.Draft = extra.Draft
+		item.AuthorAssociation = extra.AuthorAssociation
 	}
 
 	e.mu.Lock()
@@ -284,6 +322,66 @@ func (e *eventImporter) add(eType, url string, usr *github.User, updated *time.T
 	return nil
 }
 
+func (e *eventImporter) addTimeline(item *data.TimelineEvent) error {
+	e.mu.Lock()
+	e.timeline = append(e.timeline, item)
+	shouldFlush := len(e.timeline) >= importBatchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		if err := e.flush(); err != nil {
+			return fmt.Errorf("error flushing timeline events: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *eventImporter) addReview(item *data.PRReview) error {
+	e.mu.Lock()
+	e.reviews = append(e.reviews, item)
+	shouldFlush := len(e.reviews) >= importBatchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		if err := e.flush(); err != nil {
+			return fmt.Errorf("error flushing PR reviews: %w", err)
+		}
+	}
+	return nil
+}
+
+// addDiscussionThread adds a discussion and its comments.
+func (e *eventImporter) addDiscussionThread(item *data.Discussion, comments []*data.DiscussionComment) error {
+	e.mu.Lock()
+	if item != nil {
+		e.discussions = append(e.discussions, item)
+	}
+	e.discComments = append(e.discComments, comments...)
+	shouldFlush := len(e.discussions)+len(e.discComments) >= importBatchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		if err := e.flush(); err != nil {
+			return fmt.Errorf("error flushing discussions: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *eventImporter) addLink(item *data.PRIssueLink) error {
+	e.mu.Lock()
+	e.links = append(e.links, item)
+	shouldFlush := len(e.links) >= importBatchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		if err := e.flush(); err != nil {
+			return fmt.Errorf("error flushing issue links: %w", err)
+		}
+	}
+	return nil
+}
+
 func (e *eventImporter) loadState() error {
 	for _, t := range EventTypes {
 		state, err := e.store.GetState(t, e.owner, e.repo, e.minEventTime)
@@ -296,20 +394,57 @@ func (e *eventImporter) loadState() error {
 	return nil
 }
 
+func (e *eventImporter) addEngagement(item *data.Engagement) error {
+	e.mu.Lock()
+	e.engagement = append(e.engagement, item)
+	shouldFlush := len(e.engagement) >= importBatchSize
+	e.mu.Unlock()
+
+	if shouldFlush {
+		if err := e.flush(); err != nil {
+			return fmt.Errorf("error flushing engagement: %w", err)
+		}
+	}
+	return nil
+}
+
 func (e *eventImporter) flush() error {
-	if len(e.list) == 0 {
+	e.mu.Lock()
+	empty := len(e.list) == 0 && len(e.timeline) == 0 && len(e.reviews) == 0 &&
+		len(e.discussions) == 0 && len(e.discComments) == 0 &&
+		len(e.links) == 0 && len(e.engagement) == 0
+	e.mu.Unlock()
+	if empty {
 		return nil
 	}
 
 	start := time.Now()
 
 	var events []*data.Event
+	var timeline []*data.TimelineEvent
+	var reviews []*data.PRReview
+	var discussions []*data.Discussion
+	var discComments []*data.DiscussionComment
+	var links []*data.PRIssueLink
+	var engagement []*data.Engagement
 	var users map[string]*github.User
 	var state map[string]*data.State
 
 	e.mu.Lock()
 	events = e.list
 	e.list = make([]*data.Event, 0)
+	timeline = e.timeline
+	e.timeline = make([]*data.TimelineEvent, 0)
+	reviews = e.reviews
+	e.reviews = make([]*data.PRReview, 0)
+	discussions = e.discussions
+	e.discussions = make([]*data.Discussion, 0)
+	discComments = e.discComments
+	e.discComments = make([]*data.DiscussionComment, 0)
+	links = e.links
+	e.links = make([]*data.PRIssueLink, 0)
+	engagement = e.engagement
+	e.engagement = make([]*data.Engagement, 0)
 
 	users = make(map[string]*github.User, len(e.users))
 	for k, v := range e.users {
@@ -350,6 +485,48 @@ func (e *eventImporter) flush() error {
 	}
 	defer stateStmt.Close()
 
+	timelineStmt, err := db.Prepare(insertTimelineEventSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare timeline insert statement: %w", err)
+	}
+	defer timelineStmt.Close()
+
+	reviewStmt, err := db.Prepare(upsertPRReviewSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare PR review insert statement: %w", err)
+	}
+	defer reviewStmt.Close()
+
+	discussionStmt, err := db.Prepare(upsertDiscussionSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare discussion insert statement: %w", err)
+	}
+	defer discussionStmt.Close()
+
+	discCommentStmt, err := db.Prepare(upsertDiscussionCommentSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare discussion comment insert statement: %w", err)
+	}
+	defer discCommentStmt.Close()
+
+	linkStmt, err := db.Prepare(insertPRIssueLinkSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare issue link insert statement: %w", err)
+	}
+	defer linkStmt.Close()
+
+	engagementStmt, err := db.Prepare(upsertEngagementSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare engagement insert statement: %w", err)
+	}
+	defer engagementStmt.Close()
+
+	itemStmt, err := db.Prepare(upsertEventItemSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare event item insert statement: %w", err)
+	}
+	defer itemStmt.Close()
+
 	tx, err := db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -357,29 +534,87 @@ func (e *eventImporter) flush() error {
 
 	txDevStmt := tx.Stmt(devStmt)
 	for i, u := range devs {
-		if _, err = txDevStmt.Exec(u.Username,
-			u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity,
-			u.FullName, u.Email, u.AvatarURL, u.ProfileURL, u.Entity, u.Entity); err != nil {
+		if _, err = txDevStmt.Exec(developerUpsertArgs(u)...); err != nil {
 			rollbackTransaction(tx)
 			return fmt.Errorf("error inserting developer[%d]: %s: %w", i, u.Username, err)
 		}
 	}
 
 	txEventStmt := tx.Stmt(eventStmt)
+	txItemStmt := tx.Stmt(itemStmt)
 	for i, ev := range events {
 		_, err = txEventStmt.Exec(
 			ev.Org, ev.Repo, ev.Username, ev.Type, ev.Date,
 			ev.URL, ev.Mentions, ev.Labels,
 			ev.State, ev.Number, ev.CreatedAt, ev.ClosedAt, ev.MergedAt, ev.Additions, ev.Deletions,
-			ev.ChangedFiles, ev.Commits, ev.Title,
+			ev.ChangedFiles, ev.Commits, ev.Title, ev.StateReason, ev.Draft, ev.EventAt, ev.AuthorAssociation,
 			ev.URL, ev.Mentions, ev.Labels,
 			ev.State, ev.Number, ev.CreatedAt, ev.ClosedAt, ev.MergedAt, ev.Additions, ev.Deletions,
-			ev.ChangedFiles, ev.Commits, ev.Title,
+			ev.ChangedFiles, ev.Commits, ev.Title, ev.StateReason, ev.Draft, ev.EventAt, ev.AuthorAssociation,
 		)
 		if err != nil {
 			rollbackTransaction(tx)
 			return fmt.Errorf("error inserting event[%d]: %s/%s: %w", i, ev.Org, ev.Repo, err)
 		}
+		if ev.URL == "" {
+			continue
+		}
+		if _, err = txItemStmt.Exec(ev.Type, ev.URL, ev.Org, ev.Repo, ev.Username, ev.CreatedAt, ev.EventAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting event item[%d]: %s: %w", i, ev.URL, err)
+		}
+	}
+
+	txTimelineStmt := tx.Stmt(timelineStmt)
+	for i, t := range timeline {
+		if _, err = txTimelineStmt.Exec(t.Org, t.Repo, t.Number, t.ItemType, t.Kind, t.Actor, t.Subject, t.CreatedAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting timeline event[%d]: %s/%s#%d: %w", i, t.Org, t.Repo, t.Number, err)
+		}
+	}
+
+	txReviewStmt := tx.Stmt(reviewStmt)
+	for i, r := range reviews {
+		if _, err = txReviewStmt.Exec(r.ID, r.Org, r.Repo, r.Number, r.Username, r.State, r.SubmittedAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting PR review[%d]: %s/%s#%d: %w", i, r.Org, r.Repo, r.Number, err)
+		}
+	}
+
+	txDiscussionStmt := tx.Stmt(discussionStmt)
+	for i, d := range discussions {
+		if _, err = txDiscussionStmt.Exec(d.URL, d.Org, d.Repo, d.Number, d.Username, d.State,
+			d.CreatedAt, d.UpdatedAt, d.AnswerURL); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting discussion[%d]: %s: %w", i, d.URL, err)
+		}
+	}
+
+	txDiscCommentStmt := tx.Stmt(discCommentStmt)
+	for i, c := range discComments {
+		if _, err = txDiscCommentStmt.Exec(c.URL, c.Org, c.Repo, c.Number, c.Username,
+			c.CreatedAt, c.UpdatedAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting discussion comment[%d]: %s: %w", i, c.URL, err)
+		}
+	}
+
+	txLinkStmt := tx.Stmt(linkStmt)
+	for i, l := range links {
+		if _, err = txLinkStmt.Exec(l.Org, l.Repo, l.PRNumber, l.Issue.Org, l.Issue.Repo, l.Issue.Number, l.Source); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting issue link[%d]: %s/%s#%d: %w", i, l.Org, l.Repo, l.PRNumber, err)
+		}
+	}
+
+	updatedAt := time.Now().UTC().Format(time.RFC3339)
+	txEngagementStmt := tx.Stmt(engagementStmt)
+	for i, g := range engagement {
+		if _, err = txEngagementStmt.Exec(g.Org, g.Repo, g.Number, g.ItemType, g.Comments, g.Reactions,
+			g.PlusOne, g.MinusOne, g.Laugh, g.Hooray, g.Confused, g.Heart, g.Rocket, g.Eyes, updatedAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting engagement[%d]: %s/%s#%d: %w", i, g.Org, g.Repo, g.Number, err)
+		}
 	}
 
 	txStateStmt := tx.Stmt(stateStmt)
@@ -405,6 +640,12 @@ func (e *eventImporter) flush() error {
 	slog.Debug("flushed events",
 		"repo", e.owner+"/"+e.repo,
 		"batch", len(events),
+		"timeline", len(timeline),
+		"reviews", len(reviews),
+		"discussions", len(discussions),
+		"discussion_comments", len(discComments),
+		"links", len(links),
+		"engagement", len(engagement),
 		"total", total,
 		"developers", len(users),
 		"duration_sec", time.Since(start).Seconds())
@@ -514,21 +755,40 @@ func (e *eventImporter) importPREvents(ctx context.Context) error {
 			mentions = append(mentions, ghutil.GetUsernames(items[i].Assignee)...)
 			mentions = append(mentions, ghutil.GetUsernames(items[i].Assignees...)...)
 			mentions = append(mentions, ghutil.GetUsernames(items[i].RequestedReviewers...)...)
+			state := items[i].State
+			if items[i].MergedAt != nil {
+				state = github.Ptr(data.PRStateMerged)
+			}
 			extra := &eventExtra{
-				State:     items[i].State,
-				Number:    items[i].Number,
-				CreatedAt: timestampStr(items[i].CreatedAt),
-				ClosedAt:  timestampStr(items[i].ClosedAt),
-				MergedAt:  timestampStr(items[i].MergedAt),
-				Additions: intPtr(items[i].GetAdditions()),
-				Deletions: intPtr(items[i].GetDeletions()),
-				Title:     items[i].GetTitle(),
+				State:             state,
+				Number:            items[i].Number,
+				CreatedAt:         timestampStr(items[i].CreatedAt),
+				ClosedAt:          timestampStr(items[i].ClosedAt),
+				MergedAt:          timestampStr(items[i].MergedAt),
+				Additions:         intPtr(items[i].GetAdditions()),
+				Deletions:         intPtr(items[i].GetDeletions()),
+				Title:             items[i].GetTitle(),
+				Draft:             items[i].Draft,
+				AuthorAssociation: items[i].AuthorAssociation,
 			}
 			if err := e.add(data.EventTypePR, *items[i].HTMLURL, items[i].User, timestampToTime(items[i].UpdatedAt), mentions,
 				ghutil.GetLabels(items[i].Labels), extra); err != nil {
 				return fmt.Errorf("error adding pr event: %s/%s: %w", e.owner, e.repo, err)
 			}
 
+			for _, ref := range ghutil.ParseClosingRefs(items[i].Body, e.owner, e.repo) {
+				link := &data.PRIssueLink{
+					Org:      e.owner,
+					Repo:     e.repo,
+					PRNumber: items[i].GetNumber(),
+					Issue:    ref,
+					Source:   data.LinkSourceKeyword,
+				}
+				if err := e.addLink(link); err != nil {
+					return fmt.Errorf("error adding issue link: %s/%s: %w", e.owner, e.repo, err)
+				}
+			}
+
 			if err := e.importPRReviews(ctx, items[i].GetNumber()); err != nil {
 				slog.Warn("error importing PR reviews", "pr", items[i].GetNumber(), "error", err)
 			}
@@ -643,18 +903,32 @@ func (e *eventImporter) importPRReviews(ctx context.Context, prNumber int) error
 		}
 
 		for i := range reviews {
-			if reviews[i].User == nil || reviews[i].HTMLURL == nil {
+			// pending reviews have not been submitted yet
+			if reviews[i].User == nil || reviews[i].HTMLURL == nil || reviews[i].SubmittedAt == nil {
 				continue
 			}
 			n := prNumber
 			extra := &eventExtra{
-				Number:    &n,
-				CreatedAt: timestampStr(reviews[i].SubmittedAt),
+				State:             reviews[i].State,
+				Number:            &n,
+				CreatedAt:         timestampStr(reviews[i].SubmittedAt),
+				AuthorAssociation: reviews[i].AuthorAssociation,
 			}
 			if err := e.add(data.EventTypePRReview, *reviews[i].HTMLURL, reviews[i].User,
 				timestampToTime(reviews[i].SubmittedAt), nil, nil, extra); err != nil {
 				return fmt.Errorf("error adding PR review event: %w", err)
 			}
+			if err := e.addReview(&data.PRReview{
+				ID:          reviews[i].GetID(),
+				Org:         e.owner,
+				Repo:        e.repo,
+				Number:      prNumber,
+				Username:    reviews[i].User.GetLogin(),
+				State:       reviews[i].GetState(),
+				SubmittedAt: *timestampStr(reviews[i].SubmittedAt),
+			}); err != nil {
+				return fmt.Errorf("error adding PR review: %w", err)
+			}
 		}
 
 		if resp.NextPage == 0 {
@@ -703,16 +977,33 @@ func (e *eventImporter) importIssueEvents(ctx context.Context) error {
 			mentions = append(mentions, ghutil.GetUsernames(items[i].Assignee)...)
 			mentions = append(mentions, ghutil.GetUsernames(items[i].Assignees...)...)
 			extra := &eventExtra{
-				State:     items[i].State,
-				Number:    items[i].Number,
-				CreatedAt: timestampStr(items[i].CreatedAt),
-				ClosedAt:  timestampStr(items[i].ClosedAt),
-				Title:     items[i].GetTitle(),
+				State:             items[i].State,
+				Number:            items[i].Number,
+				CreatedAt:         timestampStr(items[i].CreatedAt),
+				ClosedAt:          timestampStr(items[i].ClosedAt),
+				Title:             items[i].GetTitle(),
+				StateReason:       items[i].StateReason,
+				AuthorAssociation: items[i].AuthorAssociation,
 			}
 			if err := e.add(data.EventTypeIssue, *items[i].HTMLURL, items[i].User,
 				timestampToTime(items[i].UpdatedAt), mentions, ghutil.GetLabels(items[i].Labels), extra); err != nil {
 				return fmt.Errorf("error adding issue event: %s/%s: %w", e.owner, e.repo, err)
 			}
+
+			itemType := data.EventTypeIssue
+			if items[i].IsPullRequest() {
+				itemType = data.EventTypePR
+			}
+
+			// the issues list also returns PRs and, unlike the PR list,
+			// carries their reaction and comment counts
+			if err := e.addEngagement(mapEngagement(e.owner, e.repo, itemType, items[i])); err != nil {
+				return fmt.Errorf("error adding engagement: %s/%s: %w", e.owner, e.repo, err)
+			}
+
+			if err := e.importTimeline(ctx, items[i].GetNumber(), itemType); err != nil {
+				slog.Warn("error importing timeline", "number", items[i].GetNumber(), "error", err)
+			}
 		}
 
 		e.state[data.EventTypeIssue].Page = opt.ListOptions.Page
@@ -734,7 +1025,7 @@ func getStrPtr(s string) *string {
 	return &s
 }
 
-//nolint:dupl // pagination boilerplate shared with importPRReviewEvents; different API + item processing
+//nolint:dupl // pagination boilerplate shared with importPRReviewCommentEvents; different API + item processing
 func (e *eventImporter) importIssueCommentEvents(ctx context.Context) error {
 	slog.Debug("starting issue comment event import", "page", e.state[data.EventTypeIssueComment].Page, "since", e.state[data.EventTypeIssueComment].Since.Format("2006-01-02"))
 
@@ -768,7 +1059,8 @@ func (e *eventImporter) importIssueCommentEvents(ctx context.Context) error {
 
 		for i := range items {
 			extra := &eventExtra{
-				CreatedAt: timestampStr(items[i].CreatedAt),
+				CreatedAt:         timestampStr(items[i].CreatedAt),
+				AuthorAssociation: items[i].AuthorAssociation,
 			}
 			if items[i].HTMLURL != nil {
 				if n := parseIssueNumberFromURL(*items[i].HTMLURL); n > 0 {
@@ -792,17 +1084,20 @@ func (e *eventImporter) importIssueCommentEvents(ctx context.Context) error {
 	return nil
 }
 
+// importPRReviewCommentEvents imports inline diff comments. Submitted reviews
+// and their verdicts are imported per PR by importPRReviews.
+//
 //nolint:dupl // pagination boilerplate shared with importIssueCommentEvents; different API + item processing
-func (e *eventImporter) importPRReviewEvents(ctx context.Context) error {
-	slog.Debug("starting pr review event import", "page", e.state[data.EventTypePRReview].Page, "since", e.state[data.EventTypePRReview].Since.Format("2006-01-02"))
+func (e *eventImporter) importPRReviewCommentEvents(ctx context.Context) error {
+	slog.Debug("starting pr review comment event import", "page", e.state[data.EventTypePRReviewComment].Page, "since", e.state[data.EventTypePRReviewComment].Since.Format("2006-01-02"))
 
 	opt := &github.PullRequestListCommentsOptions{
 		Sort:      sortField,
 		Direction: sortCommentField,
-		Since:     e.state[data.EventTypePRReview].Since,
+		Since:     e.state[data.EventTypePRReviewComment].Since,
 		ListOptions: github.ListOptions{
 			PerPage: pageSizeDefault,
-			Page:    e.state[data.EventTypePRReview].Page,
+			Page:    e.state[data.EventTypePRReviewComment].Page,
 		},
 	}
 
@@ -818,7 +1113,7 @@ func (e *eventImporter) importPRReviewEvents(ctx context.Context) error {
 		if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
 			return err
 		}
-		slog.Debug("pr review events", "found", len(items), "next_page", resp.NextPage, "last_page", resp.LastPage, "rate", ghutil.RateInfo(&resp.Rate))
+		slog.Debug("pr review comment events", "found", len(items), "next_page", resp.NextPage, "last_page", resp.LastPage, "rate", ghutil.RateInfo(&resp.Rate))
 
 		if len(items) == 0 {
 			break
@@ -826,19 +1121,20 @@ func (e *eventImporter) importPRReviewEvents(ctx context.Context) error {
 
 		for i := range items {
 			extra := &eventExtra{
-				CreatedAt: timestampStr(items[i].CreatedAt),
+				CreatedAt:         timestampStr(items[i].CreatedAt),
+				AuthorAssociation: items[i].AuthorAssociation,
 			}
 			if items[i].PullRequestURL != nil {
 				if n := parsePRNumberFromURL(*items[i].PullRequestURL); n > 0 {
 					extra.Number = &n
 				}
 			}
-			if err := e.add(data.EventTypePRReview, *items[i].HTMLURL, items[i].User, timestampToTime(items[i].UpdatedAt), ghutil.ParseUsers(items[i].Body), nil, extra); err != nil {
+			if err := e.add(data.EventTypePRReviewComment, *items[i].HTMLURL, items[i].User, timestampToTime(items[i].UpdatedAt), ghutil.ParseUsers(items[i].Body), nil, extra); err != nil {
 				return fmt.Errorf("error adding PR comment event: %s/%s: %w", e.owner, e.repo, err)
 			}
 		}
 
-		e.state[data.EventTypePRReview].Page = opt.ListOptions.Page
+		e.state[data.EventTypePRReviewComment].Page = opt.ListOptions.Page
 
 		if resp.NextPage == 0 {
 			break
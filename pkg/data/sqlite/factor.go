@@ -0,0 +1,186 @@
+package sqlite
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// factorShare is the share of activity the bus and pony factor members
+	// account for.
+	factorShare = 0.5
+
+	// selectFactorActivitySQL counts the events of each developer per month
+	// with the same filters as the bus and pony factor of the summary.
+	selectFactorActivitySQL = `SELECT
+			substr(e.date, 1, 7) AS month,
+			e.username,
+			IFNULL(d.entity, '') AS entity,
+			COUNT(*) AS cnt
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND e.date >= ?
+		  AND e.date < ?
+		  ` + developerFilterSQL + `
+		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
+		GROUP BY month, e.username, d.entity
+		ORDER BY month
+	`
+)
+
+// factorMembers returns the names with the most events that together
+// account for half of all events, largest first. Ties are ordered by name.
+func factorMembers(counts map[string]int) []*data.FactorMember {
+	var total int
+	names := make([]string, 0, len(counts))
+	for n, c := range counts {
+		names = append(names, n)
+		total += c
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	list := make([]*data.FactorMember, 0)
+	var sum int
+	for _, n := range names {
+		if float64(sum) >= float64(total)*factorShare {
+			break
+		}
+		c := counts[n]
+		sum += c
+		list = append(list, &data.FactorMember{
+			Name:   n,
+			Events: c,
+			Share:  float64(c) / float64(total) * 100,
+		})
+	}
+	return list
+}
+
+// factorChanges returns the names in cur but not in prev, and in prev but
+// not in cur.
+func factorChanges(prev, cur []*data.FactorMember) (joined, left []string) {
+	in := func(list []*data.FactorMember, name string) bool {
+		for _, m := range list {
+			if m.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	joined, left = make([]string, 0), make([]string, 0)
+	for _, m := range cur {
+		if !in(prev, m.Name) {
+			joined = append(joined, m.Name)
+		}
+	}
+	for _, m := range prev {
+		if !in(cur, m.Name) {
+			left = append(left, m.Name)
+		}
+	}
+	return joined, left
+}
+
+// GetFactorTrend returns the bus and pony factor of each bucket over a
+// trailing window of buckets, with the developers and entities making up
+// half of the activity and how those sets changed from the previous bucket.
+func (s *Store) GetFactorTrend(f *data.InsightsFilter, window int) (*data.FactorSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+	if window < 1 {
+		return nil, fmt.Errorf("invalid factor window: %d", window)
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// the window of the first bucket, and the one before it, reach back
+	// before the range
+	lookback := br.lookback(window)
+
+	rows, err := s.db.Query(br.query(selectFactorActivitySQL),
+		f.Org, f.Repo, f.Entity, lookback.Format(dateLayout), br.until(), f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query factor activity: %w", err)
+	}
+	defer rows.Close()
+
+	devs := make(map[string]map[string]int)
+	ents := make(map[string]map[string]int)
+	for rows.Next() {
+		var month, username, entity string
+		var cnt int
+		if err := rows.Scan(&month, &username, &entity, &cnt); err != nil {
+			return nil, fmt.Errorf("failed to scan factor activity row: %w", err)
+		}
+		if devs[month] == nil {
+			devs[month] = make(map[string]int)
+			ents[month] = make(map[string]int)
+		}
+		devs[month][username] += cnt
+		if entity != "" {
+			ents[month][entity] += cnt
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	sr := &data.FactorSeries{
+		Months:     make([]string, 0),
+		Window:     window,
+		BusFactor:  make([]int, 0),
+		PonyFactor: make([]int, 0),
+		Periods:    make([]*data.FactorPeriod, 0),
+	}
+
+	first := br.key(br.start)
+	keys := br.keysFrom(lookback)
+	var prevDevs, prevEnts []*data.FactorMember
+	for i, k := range keys {
+		windowDevs, windowEnts := make(map[string]int), make(map[string]int)
+		for j := max(0, i-window+1); j <= i; j++ {
+			for n, c := range devs[keys[j]] {
+				windowDevs[n] += c
+			}
+			for n, c := range ents[keys[j]] {
+				windowEnts[n] += c
+			}
+		}
+
+		p := &data.FactorPeriod{
+			Developers: factorMembers(windowDevs),
+			Entities:   factorMembers(windowEnts),
+		}
+		p.DevelopersJoined, p.DevelopersLeft = factorChanges(prevDevs, p.Developers)
+		p.EntitiesJoined, p.EntitiesLeft = factorChanges(prevEnts, p.Entities)
+		prevDevs, prevEnts = p.Developers, p.Entities
+
+		if k < first || (!br.aligned && len(devs[k]) == 0) {
+			continue
+		}
+
+		sr.Months = append(sr.Months, k)
+		sr.BusFactor = append(sr.BusFactor, len(p.Developers))
+		sr.PonyFactor = append(sr.PonyFactor, len(p.Entities))
+		sr.Periods = append(sr.Periods, p)
+	}
+
+	return sr, nil
+}
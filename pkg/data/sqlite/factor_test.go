@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactorMembers(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		want   []string
+	}{
+		{"empty", map[string]int{}, []string{}},
+		{"single", map[string]int{"a": 3}, []string{"a"}},
+		{"majority", map[string]int{"a": 6, "b": 2, "c": 1}, []string{"a"}},
+		{"spread", map[string]int{"a": 6, "b": 8, "c": 4}, []string{"b", "a"}},
+		{"ties by name", map[string]int{"b": 2, "a": 2, "c": 2, "d": 2}, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make([]string, 0)
+			for _, m := range factorMembers(tt.counts) {
+				names = append(names, m.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestGetFactorTrend_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetFactorTrend(&data.InsightsFilter{Months: 6}, 3)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetFactorTrend_InvalidWindow(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.GetFactorTrend(&data.InsightsFilter{Months: 6}, 0)
+	assert.Error(t, err)
+}
+
+func TestGetFactorTrend_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES
+		('alice', 'Alice', 'ACME'), ('bob', 'Bob', 'BETA'), ('carol', 'Carol', 'BETA')`)
+	require.NoError(t, err)
+
+	activity := []struct {
+		username string
+		month    string
+		events   int
+	}{
+		{"alice", "2025-01", 6},
+		{"bob", "2025-01", 2},
+		{"bob", "2025-02", 6},
+		{"carol", "2025-02", 4},
+		{"carol", "2025-03", 5},
+	}
+	for _, a := range activity {
+		for i := 1; i <= a.events; i++ {
+			day := fmt.Sprintf("%s-%02d", a.month, i)
+			_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+				VALUES ('org1', 'repo1', ?, 'issue_comment', ?, ?, '', '')`,
+				a.username, day, "http://c/"+a.username+day)
+			require.NoError(t, err)
+		}
+	}
+
+	f := &data.InsightsFilter{
+		Granularity: data.GranularityMonth,
+		From:        strPtr("2025-02-01"),
+		To:          strPtr("2025-03-31"),
+	}
+	sr, err := store.GetFactorTrend(f, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, sr.Window)
+	assert.Equal(t, []string{"2025-02", "2025-03"}, sr.Months)
+	assert.Equal(t, []int{2, 1}, sr.BusFactor)
+	assert.Equal(t, []int{1, 1}, sr.PonyFactor)
+	require.Len(t, sr.Periods, 2)
+
+	feb := sr.Periods[0]
+	require.Len(t, feb.Developers, 2)
+	assert.Equal(t, "bob", feb.Developers[0].Name)
+	assert.Equal(t, 8, feb.Developers[0].Events)
+	assert.InDelta(t, 8.0/18*100, feb.Developers[0].Share, 0.01)
+	assert.Equal(t, []string{"bob"}, feb.DevelopersJoined)
+	assert.Empty(t, feb.DevelopersLeft)
+	assert.Equal(t, []string{"BETA"}, feb.EntitiesJoined)
+	assert.Equal(t, []string{"ACME"}, feb.EntitiesLeft)
+
+	mar := sr.Periods[1]
+	assert.Equal(t, []string{"carol"}, mar.DevelopersJoined)
+	assert.Equal(t, []string{"bob", "alice"}, mar.DevelopersLeft)
+	assert.Empty(t, mar.EntitiesJoined)
+	assert.Empty(t, mar.EntitiesLeft)
+}
@@ -0,0 +1,325 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// selectFailureCandidatesSQL lists the issues and PRs opened in the
+	// range with their latest title and labels, when they were restored
+	// (issues closed, PRs merged), and the last release of the repo published
+	// before each was opened. Rows are deduplicated by URL since an item
+	// updated on several days is stored once per day.
+	selectFailureCandidatesSQL = `WITH items AS (
+		SELECT e.org, e.repo, IFNULL(e.number, 0) AS number, e.type, e.url, e.username,
+			IFNULL(e.title, '') AS title,
+			IFNULL(e.labels, '') AS labels,
+			MIN(e.created_at) OVER (PARTITION BY e.org, e.repo, e.type, e.url) AS created_at,
+			CASE WHEN e.type = 'pr' THEN e.merged_at
+				WHEN e.state = 'closed' THEN e.closed_at END AS restored_at,
+			ROW_NUMBER() OVER (PARTITION BY e.org, e.repo, e.type, e.url ORDER BY e.date DESC) AS rn
+		FROM event e
+		WHERE e.type IN ('issue', 'pr')
+		  AND e.created_at IS NOT NULL
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND e.created_at >= ?
+		  AND e.created_at < ?
+	),
+	candidates AS (
+		SELECT i.*,
+			(SELECT r.tag || '|' || r.published_at FROM release r
+				WHERE r.org = i.org AND r.repo = i.repo AND r.published_at <= i.created_at
+				ORDER BY r.published_at DESC LIMIT 1) AS release
+		FROM items i
+		WHERE i.rn = 1
+	)
+	SELECT c.org, c.repo, c.number, c.type, c.url, c.title, c.labels, c.created_at,
+		IFNULL(c.restored_at, ''), IFNULL(c.release, '')
+	FROM candidates c
+	JOIN developer d ON c.username = d.username
+	WHERE IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  ` + developerFilterSQL + `
+	ORDER BY c.created_at, c.url
+	`
+)
+
+// failureClassifier applies compiled data.FailureRules to issues and PRs.
+type failureClassifier struct {
+	rules    *data.FailureRules
+	patterns map[*data.FailureRule][]*regexp.Regexp
+}
+
+var defaultFailureClassifier = sync.OnceValue(func() *failureClassifier {
+	c, err := newFailureClassifier(data.DefaultFailureRules())
+	if err != nil {
+		panic(fmt.Sprintf("invalid default failure rules: %v", err))
+	}
+	return c
+})
+
+func newFailureClassifier(r *data.FailureRules) (*failureClassifier, error) {
+	if r == nil {
+		return nil, errors.New("failure rules are required")
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &failureClassifier{
+		rules:    r,
+		patterns: make(map[*data.FailureRule][]*regexp.Regexp, len(r.Rules)),
+	}
+
+	for _, fr := range r.Rules {
+		for _, p := range fr.TitlePatterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid failure title pattern %q: %w", p, err)
+			}
+			c.patterns[fr] = append(c.patterns[fr], re)
+		}
+	}
+
+	return c, nil
+}
+
+// failureClassifier returns the store's classifier or the built-in default.
+func (s *Store) failureClassifier() *failureClassifier {
+	if s.failures != nil {
+		return s.failures
+	}
+	return defaultFailureClassifier()
+}
+
+// failureCandidate is an issue or PR that may be a failed change.
+type failureCandidate struct {
+	org, repo, itemType, url, title, labels, createdAt string
+	number                                             int
+	// restoredAt is when the issue was closed or the PR merged, or empty.
+	restoredAt string
+	// release is the tag and publish time of the last prior release,
+	// separated by |, or empty when there is none.
+	release string
+}
+
+// classify returns the rule applied to the candidate and the reason it is a
+// failed change; the reason is empty when it is not.
+func (c *failureClassifier) classify(fc *failureCandidate) (*data.FailureRule, string) {
+	fr := c.rules.Rule(fc.org, fc.repo)
+	if fr == nil {
+		return nil, ""
+	}
+
+	switch fc.itemType {
+	case data.EventTypePR:
+		for _, re := range c.patterns[fr] {
+			if re.MatchString(fc.title) {
+				return fr, "title matches " + re.String()
+			}
+		}
+	case data.EventTypeIssue:
+		return fr, classifyFailureIssue(fr, fc)
+	}
+
+	return fr, ""
+}
+
+func classifyFailureIssue(fr *data.FailureRule, fc *failureCandidate) string {
+	labels := strings.Split(fc.labels, ",")
+
+	var label string
+	for _, l := range labels {
+		if data.ContainsFold(fr.Labels, strings.TrimSpace(l)) {
+			label = strings.TrimSpace(l)
+			break
+		}
+	}
+	if label == "" {
+		return ""
+	}
+
+	reason := "label " + label
+	if fr.MinSeverity != "" {
+		severity, ok := failureSeverity(fr, labels)
+		if !ok {
+			return ""
+		}
+		reason += " (" + severity + ")"
+	}
+
+	if fr.WindowDays == 0 {
+		return reason
+	}
+
+	tag, published, ok := strings.Cut(fc.release, "|")
+	if !ok {
+		return ""
+	}
+	days := failureDays(published, fc.createdAt)
+	if days < 0 || days > float64(fr.WindowDays) {
+		return ""
+	}
+	return fmt.Sprintf("%s, %.1f days after release %s", reason, days, tag)
+}
+
+// failureSeverity returns the most severe of the labels when it is at or
+// above the minimum severity of the rule.
+func failureSeverity(fr *data.FailureRule, labels []string) (string, bool) {
+	for _, sev := range fr.SeverityLabels {
+		for _, l := range labels {
+			if strings.EqualFold(strings.TrimSpace(l), sev) {
+				return sev, true
+			}
+		}
+		if strings.EqualFold(sev, fr.MinSeverity) {
+			break
+		}
+	}
+	return "", false
+}
+
+// failureDays returns the days from start to end, or -1 when either
+// timestamp is invalid.
+func failureDays(start, end string) float64 {
+	s, e, ok := parseTimeRange(start, end)
+	if !ok {
+		return -1
+	}
+	return e.Sub(s).Hours() / 24
+}
+
+// failedChange is a candidate classified as a failed change by a rule.
+type failedChange struct {
+	*failureCandidate
+	rule   *data.FailureRule
+	reason string
+	day    time.Time
+}
+
+// queryFailedChanges returns the issues and PRs opened in the range that
+// the store's failure rules classify as failed changes, in order of their
+// creation.
+func (s *Store) queryFailedChanges(f *data.InsightsFilter, br *bucketRange) ([]*failedChange, error) {
+	rows, err := s.db.Query(selectFailureCandidatesSQL,
+		f.Org, f.Repo, br.since(), br.until(), f.Entity, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query change failures: %w", err)
+	}
+	defer rows.Close()
+
+	c := s.failureClassifier()
+	list := make([]*failedChange, 0)
+	for rows.Next() {
+		fc := &failureCandidate{}
+		if err := rows.Scan(&fc.org, &fc.repo, &fc.number, &fc.itemType, &fc.url, &fc.title,
+			&fc.labels, &fc.createdAt, &fc.restoredAt, &fc.release); err != nil {
+			return nil, fmt.Errorf("failed to scan change failure row: %w", err)
+		}
+
+		fr, reason := c.classify(fc)
+		if reason == "" || len(fc.createdAt) < len(dateLayout) {
+			continue
+		}
+		day, err := time.Parse(dateLayout, fc.createdAt[:len(dateLayout)])
+		if err != nil {
+			continue
+		}
+
+		list = append(list, &failedChange{failureCandidate: fc, rule: fr, reason: reason, day: day})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// queryChangeFailures returns the issues and PRs in the range classified as
+// failed changes, keyed by the bucket of their creation.
+func (s *Store) queryChangeFailures(f *data.InsightsFilter, br *bucketRange) ([]*data.ChangeFailure, error) {
+	failed, err := s.queryFailedChanges(f, br)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*data.ChangeFailure, 0, len(failed))
+	for _, fc := range failed {
+		list = append(list, &data.ChangeFailure{
+			Month:     br.key(fc.day),
+			Org:       fc.org,
+			Repo:      fc.repo,
+			Number:    fc.number,
+			Type:      fc.itemType,
+			Title:     fc.title,
+			URL:       fc.url,
+			CreatedAt: fc.createdAt,
+			Rule:      fc.rule.Scope(),
+			Reason:    fc.reason,
+		})
+	}
+
+	return list, nil
+}
+
+// queryRestoreSamples returns the days from opening to closing each failed
+// change issue, or to merging each failed change PR, by bucket of its
+// creation. Failed changes not yet restored are left out.
+func (s *Store) queryRestoreSamples(f *data.InsightsFilter, br *bucketRange) (*durationSamples, error) {
+	failed, err := s.queryFailedChanges(f, br)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := newDurationSamples()
+	for _, fc := range failed {
+		if fc.restoredAt == "" {
+			continue
+		}
+		days := failureDays(fc.createdAt, fc.restoredAt)
+		if days < 0 {
+			continue
+		}
+		samples.add(br.key(fc.day), days)
+	}
+
+	return samples, nil
+}
+
+// GetChangeFailures returns the issues and PRs counted as failed changes in
+// a bucket of the change failure rate, or in the whole range when bucket is
+// empty, with the reason each was counted.
+func (s *Store) GetChangeFailures(f *data.InsightsFilter, bucket string) ([]*data.ChangeFailure, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.queryChangeFailures(f, br)
+	if err != nil {
+		return nil, err
+	}
+	if bucket == "" {
+		return list, nil
+	}
+
+	res := make([]*data.ChangeFailure, 0)
+	for _, cf := range list {
+		if cf.Month == bucket {
+			res = append(res, cf)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFailureClassifier(t *testing.T, rules string) *failureClassifier {
+	t.Helper()
+	r, err := data.ParseFailureRules([]byte(rules))
+	require.NoError(t, err)
+	c, err := newFailureClassifier(r)
+	require.NoError(t, err)
+	return c
+}
+
+func TestFailureClassifier_Classify(t *testing.T) {
+	c := testFailureClassifier(t, `version: 1
+rules:
+  - labels: [bug]
+    titlePatterns: ['(?i)\brevert\b']
+    windowDays: 7
+  - org: acme
+    labels: [incident, regression]
+    severityLabels: [sev1, sev2, sev3]
+    minSeverity: sev2
+`)
+	release := "v1.0|2025-01-10T00:00:00Z"
+
+	tests := []struct {
+		name   string
+		fc     *failureCandidate
+		reason string
+	}{
+		{"bug near release", &failureCandidate{org: "o", itemType: "issue", labels: "area/ui,Bug", createdAt: "2025-01-13T00:00:00Z", release: release},
+			"label Bug, 3.0 days after release v1.0"},
+		{"bug after window", &failureCandidate{org: "o", itemType: "issue", labels: "bug", createdAt: "2025-01-20T00:00:00Z", release: release}, ""},
+		{"bug without release", &failureCandidate{org: "o", itemType: "issue", labels: "bug", createdAt: "2025-01-13T00:00:00Z"}, ""},
+		{"docs bug label", &failureCandidate{org: "o", itemType: "issue", labels: "docs-bug", createdAt: "2025-01-13T00:00:00Z", release: release}, ""},
+		{"revert PR", &failureCandidate{org: "o", itemType: "pr", title: `Revert "add cache"`}, `title matches (?i)\brevert\b`},
+		{"reverted word", &failureCandidate{org: "o", itemType: "pr", title: "Fix reverted cache"}, ""},
+		{"org rule replaces default", &failureCandidate{org: "acme", itemType: "pr", title: "Revert x"}, ""},
+		{"severe incident", &failureCandidate{org: "acme", itemType: "issue", labels: "incident,sev1"}, "label incident (sev1)"},
+		{"minimum severity", &failureCandidate{org: "acme", itemType: "issue", labels: "sev2,regression"}, "label regression (sev2)"},
+		{"below severity", &failureCandidate{org: "acme", itemType: "issue", labels: "incident,sev3"}, ""},
+		{"no severity", &failureCandidate{org: "acme", itemType: "issue", labels: "incident"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reason := c.classify(tt.fc)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestGetChangeFailures_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetChangeFailures(&data.InsightsFilter{Months: 6}, "")
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetChangeFailures_Rules(t *testing.T) {
+	store := setupTestDB(t)
+	store.failures = testFailureClassifier(t, `version: 1
+rules:
+  - labels: [bug]
+    titlePatterns: ['(?i)\brevert\b']
+    windowDays: 7
+  - org: org1
+    repo: repo2
+    labels: [incident]
+`)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice')`)
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO release (org, repo, tag, name, published_at, prerelease)
+		VALUES ('org1', 'repo1', 'v1.0', 'v1.0', '2025-01-15T00:00:00Z', 0)`)
+	require.NoError(t, err)
+
+	// the bug issue is stored on two days and counts once; repo2 only counts incidents
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, title)
+		VALUES
+		('org1', 'repo1', 'alice', 'issue', '2025-01-17', 'http://i/1', '', '', 'open', 1, '2025-01-17T10:00:00Z', 'Crash'),
+		('org1', 'repo1', 'alice', 'issue', '2025-01-18', 'http://i/1', '', 'bug', 'open', 1, '2025-01-17T10:00:00Z', 'Crash'),
+		('org1', 'repo1', 'alice', 'pr', '2025-02-03', 'http://p/2', '', '', 'merged', 2, '2025-02-03T10:00:00Z', 'Revert "Add cache"'),
+		('org1', 'repo2', 'alice', 'issue', '2025-02-05', 'http://i/3', '', 'bug', 'open', 3, '2025-02-05T10:00:00Z', 'Typo'),
+		('org1', 'repo2', 'alice', 'issue', '2025-02-06', 'http://i/4', '', 'incident', 'open', 4, '2025-02-06T10:00:00Z', 'Outage')`)
+	require.NoError(t, err)
+
+	f := &data.InsightsFilter{From: strPtr("2025-01-01"), To: strPtr("2025-02-28")}
+	list, err := store.GetChangeFailures(f, "")
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, "2025-01", list[0].Month)
+	assert.Equal(t, 1, list[0].Number)
+	assert.Equal(t, "*", list[0].Rule)
+	assert.Contains(t, list[0].Reason, "after release v1.0")
+	assert.Equal(t, 2, list[1].Number)
+	assert.Equal(t, 4, list[2].Number)
+	assert.Equal(t, "org1/repo2", list[2].Rule)
+
+	list, err = store.GetChangeFailures(f, "2025-02")
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	sr, err := store.GetChangeFailureRate(f)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-01", "2025-02"}, sr.Months)
+	assert.Equal(t, []int{1, 2}, sr.Failures)
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data/ghutil"
+	"github.com/mchmarny/devpulse/pkg/net"
+)
+
+const graphQLPath = "graphql"
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse[T any] struct {
+	Data   T              `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// graphQLPageInfo is the cursor state of a paginated GraphQL connection.
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// queryGraphQL runs a query against the GitHub GraphQL API, which reports
+// query errors in the body of successful responses.
+func queryGraphQL[T any](ctx context.Context, client *github.Client, query string, vars map[string]any) (*T, *github.Response, error) {
+	req, err := client.NewRequest(http.MethodPost, graphQLPath, &graphQLRequest{
+		Query:     query,
+		Variables: vars,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating graphql request: %w", err)
+	}
+
+	var out graphQLResponse[T]
+	resp, err := client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, resp, fmt.Errorf("error running graphql query: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		net.PrintHTTPResponse(resp.Response)
+		return nil, resp, fmt.Errorf("error running graphql query, rate: %s, status: %d", ghutil.RateInfo(&resp.Rate), resp.StatusCode)
+	}
+	if len(out.Errors) > 0 {
+		return nil, resp, errors.New(out.Errors[0].Message)
+	}
+	if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
+		return nil, resp, err
+	}
+
+	return &out.Data, resp, nil
+}
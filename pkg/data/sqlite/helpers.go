@@ -10,71 +10,52 @@ import (
 const (
 	nonAlphaNumRegex string = "[^a-zA-Z0-9 ]+"
 
-	// botExcludeSQL filters out bot accounts using the "e" table alias.
-	botExcludeSQL = `AND e.username NOT LIKE '%[bot]'
-		AND LOWER(e.username) NOT IN ('copilot','github-copilot','claude','anthropic-claude')`
+	// botExcludeSQL filters out developers flagged as bots using the "d"
+	// developer table alias.
+	botExcludeSQL = `AND d.is_bot = 0`
 
-	// botExcludeDSQL filters out bot accounts using the "d" table alias.
-	botExcludeDSQL = `AND d.username NOT LIKE '%[bot]'
-		AND LOWER(d.username) NOT IN ('copilot','github-copilot','claude','anthropic-claude')`
-
-	// botExcludePrSQL filters out bot accounts using the "pr" table alias.
-	botExcludePrSQL = `AND pr.username NOT LIKE '%[bot]'
-		AND LOWER(pr.username) NOT IN ('copilot','github-copilot','claude','anthropic-claude')`
+	// developerFilterSQL filters out developers flagged as bots unless the bound
+	// include-bots argument is true, and limits developers to the bound cohort
+	// name (NULL matches everyone). Uses the "d" developer table alias.
+	developerFilterSQL = `AND (IFNULL(d.is_bot, 0) = 0 OR ? = 1)
+		AND EXISTS (SELECT 1 FROM (SELECT ? AS c) p WHERE p.c IS NULL
+			OR EXISTS (SELECT 1 FROM cohort_member m WHERE m.cohort = p.c AND m.username = d.username))`
 
 	// forkExcludeSQL excludes fork events from the join so only code/comment
 	// activity (PR, PR review, issue, issue comment) counts toward reputation.
 	// Uses "e" as the event table alias.
 	forkExcludeSQL = `AND e.type != 'fork'`
-)
 
-var (
-	entityRegEx = regexp.MustCompile(nonAlphaNumRegex)
+	// discussionExcludeSQL excludes GitHub Discussions activity, which only
+	// counts toward contributor metrics when opted in. Uses "e" as the event
+	// table alias.
+	discussionExcludeSQL = `AND e.type NOT IN ('discussion', 'discussion_comment')`
 
-	entityNoise = map[string]bool{
-		"B.V.":        true,
-		"CDL":         true,
-		"CO":          true,
-		"COMPANY":     true,
-		"CORP":        true,
-		"CORPORATION": true,
-		"GMBH":        true,
-		"GROUP":       true,
-		"INC":         true,
-		"LLC":         true,
-		"LC":          true,
-		"P.C.":        true,
-		"P.A.":        true,
-		"S.C.":        true,
-		"LTD.":        true,
-		"CHTD.":       true,
-		"PC":          true,
-		"LTD":         true,
-		"PVT":         true,
-		"SE":          true,
-		"S.A.":        true,
-	}
+	// discussionFilterSQL excludes GitHub Discussions activity unless the
+	// bound include-discussions argument is true. Uses "e" as the event
+	// table alias.
+	discussionFilterSQL = `AND (e.type NOT IN ('discussion', 'discussion_comment') OR ? = 1)`
 
-	entitySubstitutions = map[string]string{
-		"CHAINGUARDDEV":       "CHAINGUARD",
-		"GCP":                 "GOOGLE",
-		"GOOGLECLOUD":         "GOOGLE",
-		"GOOGLECLOUDPLATFORM": "GOOGLE",
-		"HUAWEICLOUD":         "HUAWEI",
-		"IBM CODAITY":         "IBM",
-		"IBM RESEARCH":        "IBM",
-		"INTERNATIONAL BUSINESS MACHINES CORPORATION":                 "IBM",
-		"INTERNATIONAL BUSINESS MACHINES":                             "IBM",
-		"INTERNATIONAL INSTITUTE OF INFORMATION TECHNOLOGY BANGALORE": "IIIT BANGALORE",
-		"LINE PLUS":       "LINE",
-		"MICROSOFT CHINA": "MICROSOFT",
-		"REDHATOFFICIAL":  "REDHAT",
-		"S&P GLOBAL INC":  "S&P",
-		"S&P GLOBAL":      "S&P",
-		"VERVERICA ORIGINAL CREATORS OF APACHE FLINK": "VERVERICA",
-	}
+	// maintainerSQL lists the maintainers of each repo as (org, repo,
+	// username): members and outside collaborators of the org, authors GitHub
+	// associated with the repo as owner, member or collaborator, and anyone
+	// who approved a PR in it. Use as a row value membership test, e.g.
+	// "(p.org, p.repo, p.username) IN (" + maintainerSQL + ")".
+	maintainerSQL = `SELECT r.org, r.repo, m.username
+		FROM org_member m
+		JOIN (SELECT DISTINCT org, repo FROM event) r ON r.org = m.org
+		UNION
+		SELECT org, repo, username
+		FROM event
+		WHERE author_association IN ('OWNER', 'MEMBER', 'COLLABORATOR')
+		UNION
+		SELECT org, repo, username
+		FROM pr_review
+		WHERE state = 'APPROVED'`
 )
 
+var entityRegEx = regexp.MustCompile(nonAlphaNumRegex)
+
 func sinceDate(months int) string {
 	return time.Now().UTC().AddDate(0, -months, 0).Format("2006-01-02")
 }
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	offHoursEntityLimit = 20
+
+	// selectActivityHeatmapSQL counts imported items by UTC day of week and
+	// hour of their time.
+	selectActivityHeatmapSQL = `SELECT
+			CAST(strftime('%w', i.at) AS INTEGER) AS dow,
+			CAST(strftime('%H', i.at) AS INTEGER) AS hour,
+			COUNT(*) AS cnt
+		FROM event_item i
+		JOIN developer d ON i.username = d.username
+		WHERE i.org = COALESCE(?, i.org)
+		  AND i.repo = COALESCE(?, i.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND i.at >= ?
+		  AND i.at < ?
+		  ` + developerFilterSQL + `
+		GROUP BY dow, hour
+	`
+
+	// selectOffHoursSQL counts each entity's items on weekends and on
+	// weekdays outside business hours (args: start and end hour).
+	selectOffHoursSQL = `SELECT
+			d.entity,
+			COUNT(*) AS events,
+			SUM(CASE WHEN strftime('%w', i.at) IN ('0', '6') THEN 1 ELSE 0 END) AS weekend,
+			SUM(CASE WHEN strftime('%w', i.at) NOT IN ('0', '6')
+				AND (CAST(strftime('%H', i.at) AS INTEGER) < ?
+					OR CAST(strftime('%H', i.at) AS INTEGER) >= ?) THEN 1 ELSE 0 END) AS after_hours
+		FROM event_item i
+		JOIN developer d ON i.username = d.username
+		WHERE d.entity IS NOT NULL AND d.entity != ''
+		  AND i.org = COALESCE(?, i.org)
+		  AND i.repo = COALESCE(?, i.repo)
+		  AND d.entity = COALESCE(?, d.entity)
+		  AND i.at >= ?
+		  AND i.at < ?
+		  ` + developerFilterSQL + `
+		GROUP BY d.entity
+		ORDER BY events DESC, d.entity
+		LIMIT ?
+	`
+)
+
+var weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// parseTimeRange parses the start and end timestamps of a duration.
+func parseTimeRange(startAt, endAt string) (time.Time, time.Time, bool) {
+	start, err := time.Parse(time.RFC3339, startAt)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse(time.RFC3339, endAt)
+	if err != nil || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// businessHours returns the hours between start and end that fall on
+// weekdays within data.BusinessHourStart and data.BusinessHourEnd (UTC).
+func businessHours(start, end time.Time) float64 {
+	start, end = start.UTC(), end.UTC()
+	if !end.After(start) {
+		return 0
+	}
+
+	var total time.Duration
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			continue
+		}
+		from := day.Add(time.Duration(data.BusinessHourStart) * time.Hour)
+		to := day.Add(time.Duration(data.BusinessHourEnd) * time.Hour)
+		if start.After(from) {
+			from = start
+		}
+		if end.Before(to) {
+			to = end
+		}
+		if to.After(from) {
+			total += to.Sub(from)
+		}
+	}
+
+	return total.Hours()
+}
+
+// GetActivityHeatmap returns the number of issues, PRs, comments, reviews,
+// forks and discussions by UTC day of week and hour they were created.
+func (s *Store) GetActivityHeatmap(f *data.InsightsFilter) (*data.ActivityHeatmap, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectActivityHeatmapSQL, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	res := &data.ActivityHeatmap{
+		Days:   weekdayNames,
+		Counts: make([][]int, len(weekdayNames)),
+	}
+	for i := range res.Counts {
+		res.Counts[i] = make([]int, 24)
+	}
+
+	for rows.Next() {
+		var dow, hour, cnt int
+		if err := rows.Scan(&dow, &hour, &cnt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity heatmap row: %w", err)
+		}
+		if dow < 0 || dow > 6 || hour < 0 || hour > 23 {
+			continue
+		}
+		res.Counts[dow][hour] = cnt
+		res.Total += cnt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetOffHoursShare returns the share of each entity's items created on
+// weekends and on weekdays outside business hours, for the most active
+// entities.
+func (s *Store) GetOffHoursShare(f *data.InsightsFilter) ([]*data.EntityOffHours, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectOffHoursSQL,
+		data.BusinessHourStart, data.BusinessHourEnd,
+		f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort, offHoursEntityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query off-hours share: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.EntityOffHours, 0)
+	for rows.Next() {
+		r := &data.EntityOffHours{}
+		if err := rows.Scan(&r.Entity, &r.Events, &r.Weekend, &r.AfterHours); err != nil {
+			return nil, fmt.Errorf("failed to scan off-hours row: %w", err)
+		}
+		if r.Events > 0 {
+			r.WeekendPct = float64(r.Weekend) / float64(r.Events) * 100
+			r.AfterHoursPct = float64(r.AfterHours) / float64(r.Events) * 100
+		}
+		list = append(list, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
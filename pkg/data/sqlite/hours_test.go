@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessHours(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+
+	// 2025-01-10 is a Friday
+	assert.InDelta(t, 6.0, businessHours(at("2025-01-10T10:00:00Z"), at("2025-01-10T16:00:00Z")), 0.01)
+	assert.InDelta(t, 0.0, businessHours(at("2025-01-10T18:00:00Z"), at("2025-01-10T23:00:00Z")), 0.01)
+	// Friday 16:00 to Monday 10:00 spans the weekend: 1h + 1h
+	assert.InDelta(t, 2.0, businessHours(at("2025-01-10T16:00:00Z"), at("2025-01-13T10:00:00Z")), 0.01)
+	// a full work week
+	assert.InDelta(t, 40.0, businessHours(at("2025-01-13T00:00:00Z"), at("2025-01-18T00:00:00Z")), 0.01)
+	assert.InDelta(t, 0.0, businessHours(at("2025-01-13T10:00:00Z"), at("2025-01-13T09:00:00Z")), 0.01)
+}
+
+func TestParseTimeRange(t *testing.T) {
+	start, end, ok := parseTimeRange("2025-01-10T10:00:00Z", "2025-01-10T12:00:00Z")
+	require.True(t, ok)
+	assert.InDelta(t, 2.0, end.Sub(start).Hours(), 0.01)
+
+	_, _, ok = parseTimeRange("2025-01-10", "2025-01-10T12:00:00Z")
+	assert.False(t, ok)
+	_, _, ok = parseTimeRange("2025-01-10T12:00:00Z", "2025-01-10T10:00:00Z")
+	assert.False(t, ok)
+}
+
+func TestGetReviewLatency_BusinessHours(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice'), ('bob', 'Bob')`)
+	require.NoError(t, err)
+
+	// PR opened Friday 16:00, first review (no created_at) Monday 10:00
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, event_at) VALUES
+		('org1', 'repo1', 'alice', 'pr', '2025-01-10', 'http://a', '', '', 'open', 42, '2025-01-10T16:00:00Z', '2025-01-10T16:00:00Z'),
+		('org1', 'repo1', 'bob', 'pr_review', '2025-01-13', 'http://b', '', '', 'APPROVED', 42, NULL, '2025-01-13T10:00:00Z')`)
+	require.NoError(t, err)
+
+	series, err := store.GetReviewLatency(&data.InsightsFilter{Months: 1200})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, 1, series.Count[0])
+	assert.InDelta(t, 66.0, series.AvgHours[0], 0.01)
+	assert.InDelta(t, 2.0, series.BusinessAvgHours[0], 0.01)
+}
+
+func TestGetTimeToFirstResponse_BusinessHours(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice'), ('bob', 'Bob')`)
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, event_at) VALUES
+		('org1', 'repo1', 'alice', 'issue', '2025-01-10', 'http://i/1', '', '', 'open', 1, '2025-01-10T20:00:00Z', '2025-01-10T20:00:00Z'),
+		('org1', 'repo1', 'bob', 'issue_comment', '2025-01-13', 'http://i/1#c1', '', '', NULL, 1, '2025-01-13T11:00:00Z', '2025-01-13T11:00:00Z')`)
+	require.NoError(t, err)
+
+	series, err := store.GetTimeToFirstResponse(&data.InsightsFilter{Months: 1200})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.InDelta(t, 63.0, series.IssueAvg[0], 0.01)
+	assert.InDelta(t, 2.0, series.IssueBusinessAvg[0], 0.01)
+	assert.InDelta(t, 0.0, series.PRBusinessAvg[0], 0.01)
+}
+
+func insertActivityTimeTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES
+		('alice', 'Alice', 'ACME'), ('bob', 'Bob', 'INITECH'), ('carol', 'Carol', '')`)
+	require.NoError(t, err)
+
+	// find the most recent Saturday so the weekday offsets are stable
+	sat := time.Now().UTC().Truncate(24 * time.Hour)
+	for sat.Weekday() != time.Saturday {
+		sat = sat.AddDate(0, 0, -1)
+	}
+	at := func(d, h int) string {
+		return sat.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour).Format(time.RFC3339)
+	}
+
+	// alice: Saturday 10:00, Monday 10:00, Monday 20:00 (after hours)
+	// bob: Tuesday 11:00
+	// carol: unaffiliated, Tuesday 03:00
+	_, err = store.db.Exec(`INSERT INTO event_item (type, url, org, repo, username, at) VALUES
+		('pr', 'http://p/1', 'org1', 'repo1', 'alice', ?),
+		('issue', 'http://i/2', 'org1', 'repo1', 'alice', ?),
+		('issue_comment', 'http://i/2#c', 'org1', 'repo1', 'alice', ?),
+		('pr', 'http://p/3', 'org1', 'repo1', 'bob', ?),
+		('pr', 'http://p/4', 'org1', 'repo1', 'carol', ?)`,
+		at(-7, 10), at(-5, 10), at(-5, 20), at(-4, 11), at(-4, 3))
+	require.NoError(t, err)
+}
+
+func TestGetActivityHeatmap_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetActivityHeatmap(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetActivityHeatmap_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetActivityHeatmap(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	require.Len(t, res.Counts, 7)
+	assert.Len(t, res.Counts[0], 24)
+}
+
+func TestGetActivityHeatmap_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertActivityTimeTestData(t, store)
+
+	res, err := store.GetActivityHeatmap(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, "Sat", res.Days[6])
+	assert.Equal(t, 1, res.Counts[6][10])
+	assert.Equal(t, 1, res.Counts[1][10])
+	assert.Equal(t, 1, res.Counts[1][20])
+	assert.Equal(t, 1, res.Counts[2][11])
+	assert.Equal(t, 1, res.Counts[2][3])
+}
+
+func TestGetActivityHeatmap_SameDayItems(t *testing.T) {
+	store := setupTestDB(t)
+
+	// two PRs by the same author on the same day share one event row but
+	// each counts at its own hour
+	d := time.Now().UTC().AddDate(0, 0, -3).Truncate(24 * time.Hour)
+	imp := &eventImporter{
+		store:  store,
+		owner:  "org1",
+		repo:   "repo1",
+		list:   make([]*data.Event, 0),
+		counts: make(map[string]int),
+		users:  make(map[string]*github.User),
+	}
+	usr := &github.User{Login: github.Ptr("alice")}
+	for i, h := range []int{9, 22} {
+		created := d.Add(time.Duration(h) * time.Hour)
+		updated := created.Add(30 * time.Minute)
+		extra := &eventExtra{CreatedAt: timestampStr(&github.Timestamp{Time: created})}
+		require.NoError(t, imp.add(data.EventTypePR, fmt.Sprintf("http://p/%d", i), usr, &updated, nil, nil, extra))
+	}
+	require.NoError(t, imp.flush())
+
+	var events int
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM event WHERE username = 'alice'`).Scan(&events))
+	assert.Equal(t, 1, events)
+
+	res, err := store.GetActivityHeatmap(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	dow := int(d.Weekday())
+	assert.Equal(t, 1, res.Counts[dow][9])
+	assert.Equal(t, 1, res.Counts[dow][22])
+}
+
+func TestGetOffHoursShare_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetOffHoursShare(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetOffHoursShare_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertActivityTimeTestData(t, store)
+
+	list, err := store.GetOffHoursShare(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	assert.Equal(t, "ACME", list[0].Entity)
+	assert.Equal(t, 3, list[0].Events)
+	assert.Equal(t, 1, list[0].Weekend)
+	assert.Equal(t, 1, list[0].AfterHours)
+	assert.InDelta(t, 100.0/3.0, list[0].WeekendPct, 0.01)
+
+	assert.Equal(t, "INITECH", list[1].Entity)
+	assert.Equal(t, 0, list[1].Weekend)
+	assert.Equal(t, 0, list[1].AfterHours)
+}
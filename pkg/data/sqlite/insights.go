@@ -9,6 +9,10 @@ import (
 )
 
 const (
+	// momentumWindow is the number of buckets in the contributor momentum
+	// rolling window.
+	momentumWindow = 3
+
 	selectBusFactorSQL = `WITH dev_counts AS (
 			SELECT e.username, COUNT(*) AS cnt
 			FROM event e
@@ -17,8 +21,10 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.date >= ?
-			  ` + botExcludeSQL + `
+			  AND e.date < ?
+			  ` + developerFilterSQL + `
 			  ` + forkExcludeSQL + `
+			  ` + discussionExcludeSQL + `
 			GROUP BY e.username
 			ORDER BY cnt DESC
 		),
@@ -39,8 +45,11 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.date >= ?
+			  AND e.date < ?
 			  AND d.entity IS NOT NULL AND d.entity != ''
+			  ` + developerFilterSQL + `
 			  ` + forkExcludeSQL + `
+			  ` + discussionExcludeSQL + `
 			GROUP BY d.entity
 			ORDER BY cnt DESC
 		),
@@ -61,8 +70,9 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.date >= ?
-			  ` + botExcludeSQL + `
+			  ` + developerFilterSQL + `
 			  ` + forkExcludeSQL + `
+			  ` + discussionFilterSQL + `
 			GROUP BY e.username
 		),
 		monthly AS (
@@ -73,8 +83,9 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.date >= ?
-			  ` + botExcludeSQL + `
+			  ` + developerFilterSQL + `
 			  ` + forkExcludeSQL + `
+			  ` + discussionFilterSQL + `
 		)
 		SELECT m.month,
 			SUM(CASE WHEN f.first_month = m.month THEN 1 ELSE 0 END) AS new_contributors,
@@ -87,8 +98,7 @@ const (
 
 	selectTimeToMergeSQL = `SELECT
 			substr(e.created_at, 1, 7) AS month,
-			COUNT(*) AS cnt,
-			AVG(julianday(e.merged_at) - julianday(e.created_at)) AS avg_days
+			julianday(e.merged_at) - julianday(e.created_at) AS days
 		FROM event e
 		JOIN developer d ON e.username = d.username
 		WHERE e.type = 'pr'
@@ -98,40 +108,13 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.created_at >= ?
-		  ` + botExcludeSQL + `
-		GROUP BY month
-		ORDER BY month
-	`
-
-	selectTimeToRestoreBugsSQL = `SELECT
-			substr(e.created_at, 1, 7) AS month,
-			COUNT(*) AS cnt,
-			AVG(julianday(e.closed_at) - julianday(e.created_at)) AS avg_days
-		FROM event e
-		JOIN developer d ON e.username = d.username
-		WHERE e.type = 'issue'
-		  AND e.closed_at IS NOT NULL
-		  AND e.created_at IS NOT NULL
-		  AND e.state = 'closed'
-		  AND LOWER(e.labels) LIKE '%bug%'
-		  AND EXISTS (
-		      SELECT 1 FROM release r
-		      WHERE r.org = e.org AND r.repo = e.repo
-		        AND julianday(e.created_at) - julianday(r.published_at) BETWEEN 0 AND 7
-		  )
-		  AND e.org = COALESCE(?, e.org)
-		  AND e.repo = COALESCE(?, e.repo)
-		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
-		  AND e.created_at >= ?
-		  ` + botExcludeSQL + `
-		GROUP BY month
+		  ` + developerFilterSQL + `
 		ORDER BY month
 	`
 
 	selectTimeToCloseSQL = `SELECT
 			substr(e.created_at, 1, 7) AS month,
-			COUNT(*) AS cnt,
-			AVG(julianday(e.closed_at) - julianday(e.created_at)) AS avg_days
+			julianday(e.closed_at) - julianday(e.created_at) AS days
 		FROM event e
 		JOIN developer d ON e.username = d.username
 		WHERE e.type = 'issue'
@@ -142,8 +125,7 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.created_at >= ?
-		  ` + botExcludeSQL + `
-		GROUP BY month
+		  ` + developerFilterSQL + `
 		ORDER BY month
 	`
 
@@ -157,7 +139,7 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY month
 		ORDER BY month
 	`
@@ -173,35 +155,11 @@ const (
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
 		  AND e.type IN (?, ?)
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY month
 		ORDER BY month
 	`
 
-	selectChangeFailuresSQL = `SELECT
-		substr(e.created_at, 1, 7) AS month,
-		COUNT(*) AS failures
-	FROM event e
-	JOIN developer d ON e.username = d.username
-	WHERE (
-	    (e.type = 'issue' AND LOWER(e.labels) LIKE '%bug%'
-	     AND EXISTS (
-	        SELECT 1 FROM release r
-	        WHERE r.org = e.org AND r.repo = e.repo
-	          AND julianday(e.created_at) - julianday(r.published_at) BETWEEN 0 AND 7
-	     ))
-	    OR
-	    (e.type = 'pr' AND LOWER(e.title) LIKE '%revert%')
-	)
-	  AND e.org = COALESCE(?, e.org)
-	  AND e.repo = COALESCE(?, e.repo)
-	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
-	  AND e.created_at >= ?
-	  ` + botExcludeSQL + `
-	GROUP BY month
-	ORDER BY month
-	`
-
 	selectDeploymentCountSQL = `SELECT
 		substr(published_at, 1, 7) AS month,
 		COUNT(*) AS cnt
@@ -213,6 +171,8 @@ const (
 	ORDER BY month
 	`
 
+	// selectReviewLatencySQL lists the creation and first review time of each
+	// PR by month of creation; months without reviewed PRs have NULL times.
 	selectReviewLatencySQL = `WITH months AS (
 		SELECT DISTINCT substr(date, 1, 7) AS month
 		FROM event
@@ -221,7 +181,8 @@ const (
 	latency AS (
 		SELECT
 			substr(pr.created_at, 1, 7) AS month,
-			(julianday(MIN(rev.created_at)) - julianday(pr.created_at)) * 24 AS hours
+			MIN(pr.created_at) AS start_at,
+			MIN(COALESCE(rev.created_at, rev.event_at)) AS end_at
 		FROM event pr
 		JOIN event rev ON pr.org = rev.org AND pr.repo = rev.repo AND pr.number = rev.number
 			AND rev.type = 'pr_review'
@@ -229,21 +190,17 @@ const (
 		WHERE pr.type = 'pr'
 		  AND pr.number IS NOT NULL
 		  AND pr.created_at IS NOT NULL
-		  AND rev.created_at IS NOT NULL
+		  AND COALESCE(rev.created_at, rev.event_at) IS NOT NULL
 		  AND pr.org = COALESCE(?, pr.org)
 		  AND pr.repo = COALESCE(?, pr.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND pr.created_at >= ?
-		  ` + botExcludePrSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY pr.org, pr.repo, pr.number, month
 	)
-	SELECT
-		m.month,
-		COALESCE(COUNT(l.hours), 0) AS cnt,
-		COALESCE(AVG(l.hours), 0) AS avg_hours
+	SELECT m.month, l.start_at, l.end_at
 	FROM months m
 	LEFT JOIN latency l ON m.month = l.month
-	GROUP BY m.month
 	ORDER BY m.month
 	`
 
@@ -261,36 +218,31 @@ const (
 	  AND e.repo = COALESCE(?, e.repo)
 	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 	  AND e.created_at >= ?
-	  ` + botExcludeSQL + `
+	  ` + developerFilterSQL + `
 	GROUP BY month
 	ORDER BY month
 	`
 
-	selectContributorMomentumSQL = `WITH months AS (
-		SELECT DISTINCT substr(date, 1, 7) AS month
-		FROM event
-		WHERE date >= ?
-	)
-	SELECT
-		m.month,
-		COUNT(DISTINCT e.username) AS active
-	FROM months m
-	JOIN event e ON substr(e.date, 1, 7) >= substr(date(m.month || '-01', '-2 months'), 1, 7)
-		AND substr(e.date, 1, 7) <= m.month
+	// selectContributorMomentumSQL lists the contributors active in each
+	// month; the rolling window is counted in Go.
+	selectContributorMomentumSQL = `SELECT DISTINCT substr(e.date, 1, 7) AS month, e.username
+	FROM event e
 	JOIN developer d ON e.username = d.username
 	WHERE e.org = COALESCE(?, e.org)
 	  AND e.repo = COALESCE(?, e.repo)
 	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
-	  ` + botExcludeSQL + `
+	  AND e.date >= ?
+	  ` + developerFilterSQL + `
 	  ` + forkExcludeSQL + `
-	GROUP BY m.month
-	ORDER BY m.month
+	  ` + discussionFilterSQL + `
+	ORDER BY month
 	`
 
 	selectContributorFunnelSQL = `WITH firsts AS (
 		SELECT
 			e.username,
-			MIN(CASE WHEN e.type = 'issue_comment' THEN e.date END) AS first_comment,
+			MIN(CASE WHEN e.type = 'issue_comment'
+				OR (? = 1 AND e.type IN ('discussion', 'discussion_comment')) THEN e.date END) AS first_comment,
 			MIN(CASE WHEN e.type = 'pr' THEN e.date END) AS first_pr,
 			MIN(CASE WHEN e.type = 'pr' AND e.state = 'merged' THEN e.date END) AS first_merge
 		FROM event e
@@ -298,7 +250,7 @@ const (
 		WHERE e.org = COALESCE(?, e.org)
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY e.username
 	),
 	months AS (
@@ -335,6 +287,7 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
+		  AND e.date < ?
 	),
 	avg_counts AS (
 		SELECT
@@ -365,7 +318,8 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.date >= ?
-			  ` + botExcludeSQL + `
+			  AND e.date < ?
+			  ` + developerFilterSQL + `
 			GROUP BY e.username
 		)
 	)
@@ -387,12 +341,15 @@ const (
 		COALESCE((SELECT MAX(rm.last_import_at) FROM repo_meta rm
 			WHERE rm.org = COALESCE(?, rm.org) AND rm.repo = COALESCE(?, rm.repo)), '')
 	FROM event e
+	LEFT JOIN developer d ON e.username = d.username
 	WHERE e.org = COALESCE(?, e.org)
 	  AND e.repo = COALESCE(?, e.repo)
-	  AND IFNULL((SELECT d.entity FROM developer d WHERE d.username = e.username), '') = COALESCE(?, IFNULL((SELECT d.entity FROM developer d WHERE d.username = e.username), ''))
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 	  AND e.date >= ?
-	  ` + botExcludeSQL + `
+	  AND e.date < ?
+	  ` + developerFilterSQL + `
 	  ` + forkExcludeSQL + `
+	  ` + discussionExcludeSQL + `
 	`
 
 	selectIssueOpenCloseRatioSQL = `SELECT month, SUM(opened) AS opened, SUM(closed) AS closed
@@ -406,7 +363,7 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.created_at >= ?
-			  ` + botExcludeSQL + `
+			  ` + developerFilterSQL + `
 			UNION ALL
 			SELECT substr(e.closed_at, 1, 7) AS month, 0 AS opened, 1 AS closed
 			FROM event e
@@ -417,22 +374,23 @@ const (
 			  AND e.repo = COALESCE(?, e.repo)
 			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 			  AND e.closed_at >= ?
-			  ` + botExcludeSQL + `
+			  ` + developerFilterSQL + `
 		) sub
 		GROUP BY month
 		ORDER BY month
 	`
 
+	// selectTimeToFirstResponseSQL lists the creation and first response time
+	// (comment on issues, review on PRs) of each item by month of creation.
 	selectTimeToFirstResponseSQL = `WITH issue_first AS (
 		SELECT
-			e.org, e.repo, e.number,
+			'issue' AS kind,
 			substr(e.created_at, 1, 7) AS month,
-			MIN(
-				(julianday(c.created_at) - julianday(e.created_at)) * 24
-			) AS hours_to_first
+			MIN(e.created_at) AS start_at,
+			MIN(COALESCE(c.created_at, c.event_at)) AS end_at
 		FROM event e
 		JOIN event c ON c.org = e.org AND c.repo = e.repo AND c.number = e.number
-			AND c.type = 'issue_comment' AND c.created_at > e.created_at
+			AND c.type = 'issue_comment' AND COALESCE(c.created_at, c.event_at) > e.created_at
 		JOIN developer d ON e.username = d.username
 		WHERE e.type = 'issue'
 		  AND e.created_at IS NOT NULL
@@ -441,18 +399,17 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.created_at >= ?
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY e.org, e.repo, e.number, month
 	), pr_first AS (
 		SELECT
-			e.org, e.repo, e.number,
+			'pr' AS kind,
 			substr(e.created_at, 1, 7) AS month,
-			MIN(
-				(julianday(c.created_at) - julianday(e.created_at)) * 24
-			) AS hours_to_first
+			MIN(e.created_at) AS start_at,
+			MIN(COALESCE(c.created_at, c.event_at)) AS end_at
 		FROM event e
 		JOIN event c ON c.org = e.org AND c.repo = e.repo AND c.number = e.number
-			AND c.type = 'pr_review' AND c.created_at > e.created_at
+			AND c.type = 'pr_review' AND COALESCE(c.created_at, c.event_at) > e.created_at
 		JOIN developer d ON e.username = d.username
 		WHERE e.type = 'pr'
 		  AND e.created_at IS NOT NULL
@@ -461,19 +418,13 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.created_at >= ?
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY e.org, e.repo, e.number, month
-	), all_months AS (
-		SELECT month FROM issue_first
-		UNION
-		SELECT month FROM pr_first
 	)
-	SELECT
-		m.month,
-		COALESCE((SELECT AVG(hours_to_first) FROM issue_first WHERE month = m.month), 0),
-		COALESCE((SELECT AVG(hours_to_first) FROM pr_first WHERE month = m.month), 0)
-	FROM all_months m
-	ORDER BY m.month
+	SELECT kind, month, start_at, end_at FROM issue_first
+	UNION ALL
+	SELECT kind, month, start_at, end_at FROM pr_first
+	ORDER BY month
 `
 
 	selectDailyActivitySQL = `SELECT e.date, COUNT(*) AS cnt
@@ -483,30 +434,36 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
-		  ` + botExcludeSQL + `
+		  AND e.date < ?
+		  ` + developerFilterSQL + `
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 		GROUP BY e.date
 		ORDER BY e.date
 	`
 )
 
-func (s *Store) GetInsightsSummary(org, repo, entity *string, months int) (*data.InsightsSummary, error) {
+func (s *Store) GetInsightsSummary(f *data.InsightsFilter) (*data.InsightsSummary, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 	summary := &data.InsightsSummary{}
 
-	if err := s.db.QueryRow(selectBusFactorSQL, org, repo, entity, since).Scan(&summary.BusFactor); err != nil {
+	if err := s.db.QueryRow(selectBusFactorSQL, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort).Scan(&summary.BusFactor); err != nil {
 		return nil, fmt.Errorf("failed to query bus factor: %w", err)
 	}
 
-	if err := s.db.QueryRow(selectPonyFactorSQL, org, repo, entity, since).Scan(&summary.PonyFactor); err != nil {
+	if err := s.db.QueryRow(selectPonyFactorSQL, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort).Scan(&summary.PonyFactor); err != nil {
 		return nil, fmt.Errorf("failed to query pony factor: %w", err)
 	}
 
-	if err := s.db.QueryRow(selectBannerStatsSQL, org, repo, org, repo, entity, since).Scan(
+	if err := s.db.QueryRow(selectBannerStatsSQL, f.Org, f.Repo, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort).Scan(
 		&summary.Orgs, &summary.Repos, &summary.Events, &summary.Contributors, &summary.LastImport,
 	); err != nil {
 		return nil, fmt.Errorf("failed to query banner stats: %w", err)
@@ -515,14 +472,18 @@ func (s *Store) GetInsightsSummary(org, repo, entity *string, months int) (*data
 	return summary, nil
 }
 
-func (s *Store) GetDailyActivity(org, repo, entity *string, months int) (*data.DailyActivitySeries, error) {
+func (s *Store) GetDailyActivity(f *data.InsightsFilter) (*data.DailyActivitySeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectDailyActivitySQL, org, repo, entity, since)
+	rows, err := s.db.Query(selectDailyActivitySQL, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query daily activity: %w", err)
 	}
@@ -546,25 +507,44 @@ func (s *Store) GetDailyActivity(org, repo, entity *string, months int) (*data.D
 	return series, nil
 }
 
-func (s *Store) GetContributorRetention(org, repo, entity *string, months int) (*data.RetentionSeries, error) {
-	ms, newC, retC, err := getMonthDualSeries[int](s.db, selectRetentionSQL, org, repo, entity, months)
+func (s *Store) GetContributorRetention(f *data.InsightsFilter) (*data.RetentionSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
 	if err != nil {
 		return nil, err
 	}
-	return &data.RetentionSeries{Months: ms, New: newC, Returning: retC}, nil
+	since := br.since()
+
+	ms, newC, retC, err := queryMonthDualSeries[int](s.db, br.query(selectRetentionSQL),
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort, f.IncludeDiscussions,
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort, f.IncludeDiscussions)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &data.RetentionSeries{Months: ms, New: newC, Returning: retC}
+	br.align(sr)
+	return sr, nil
 }
 
-func (s *Store) GetPRReviewRatio(org, repo, entity *string, months int) (*data.PRReviewRatioSeries, error) {
+func (s *Store) GetPRReviewRatio(f *data.InsightsFilter) (*data.PRReviewRatioSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectPRReviewRatioSQL,
+	rows, err := s.db.Query(br.query(selectPRReviewRatioSQL),
 		data.EventTypePR, data.EventTypePRReview,
-		org, repo, entity, since,
-		data.EventTypePR, data.EventTypePRReview)
+		f.Org, f.Repo, f.Entity, since,
+		data.EventTypePR, data.EventTypePRReview, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query PR review ratio: %w", err)
 	}
@@ -598,40 +578,36 @@ func (s *Store) GetPRReviewRatio(org, repo, entity *string, months int) (*data.P
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetChangeFailureRate(org, repo, entity *string, months int) (*data.ChangeFailureRateSeries, error) {
+// GetChangeFailureRate returns failed changes per release. Issues and PRs
+// are classified as failures by the failure rules of their repo.
+func (s *Store) GetChangeFailureRate(f *data.InsightsFilter) (*data.ChangeFailureRateSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
-
-	failureMap := make(map[string]int)
-
-	rows, err := s.db.Query(selectChangeFailuresSQL, org, repo, entity, since)
+	br, err := newBucketRange(f)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query change failures: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	since := br.since()
 
-	for rows.Next() {
-		var month string
-		var failures int
-		if scanErr := rows.Scan(&month, &failures); scanErr != nil {
-			return nil, fmt.Errorf("failed to scan change failure row: %w", scanErr)
-		}
-		failureMap[month] = failures
+	failures, err := s.queryChangeFailures(f, br)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	failureMap := make(map[string]int)
+	for _, cf := range failures {
+		failureMap[cf.Month]++
 	}
 
 	deployMap := make(map[string]int)
 
-	dRows, err := s.db.Query(selectDeploymentCountSQL, org, repo, since)
+	dRows, err := s.db.Query(br.query(selectDeploymentCountSQL), f.Org, f.Repo, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query deployment count: %w", err)
 	}
@@ -684,105 +660,131 @@ func (s *Store) GetChangeFailureRate(org, repo, entity *string, months int) (*da
 		sr.Rate = append(sr.Rate, rate)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetReviewLatency(org, repo, entity *string, months int) (*data.ReviewLatencySeries, error) {
+func (s *Store) GetReviewLatency(f *data.InsightsFilter) (*data.ReviewLatencySeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectReviewLatencySQL, since, org, repo, entity, since)
+	rows, err := s.db.Query(br.query(selectReviewLatencySQL), since, f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query review latency: %w", err)
 	}
 	defer rows.Close()
 
-	sr := &data.ReviewLatencySeries{
-		Months:   make([]string, 0),
-		Count:    make([]int, 0),
-		AvgHours: make([]float64, 0),
-	}
-
+	hours, business := newDurationSamples(), newDurationSamples()
 	for rows.Next() {
 		var month string
-		var cnt int
-		var avgHours float64
-		if err := rows.Scan(&month, &cnt, &avgHours); err != nil {
+		var startAt, endAt sql.NullString
+		if err := rows.Scan(&month, &startAt, &endAt); err != nil {
 			return nil, fmt.Errorf("failed to scan review latency row: %w", err)
 		}
-		sr.Months = append(sr.Months, month)
-		sr.Count = append(sr.Count, cnt)
-		sr.AvgHours = append(sr.AvgHours, avgHours)
+
+		hours.bucket(month)
+		business.bucket(month)
+
+		start, end, ok := parseTimeRange(startAt.String, endAt.String)
+		if !ok {
+			continue
+		}
+		hours.add(month, end.Sub(start).Hours())
+		business.add(month, businessHours(start, end))
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	sr := reviewLatencySeries(hours, business)
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) getVelocitySeries(query string, org, repo, entity *string, months int) (*data.VelocitySeries, error) {
+// getVelocitySeries summarizes (month, days) rows of the query.
+func (s *Store) getVelocitySeries(query string, f *data.InsightsFilter) (*data.VelocitySeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
-
-	rows, err := s.db.Query(query, org, repo, entity, since)
+	br, err := newBucketRange(f)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query velocity series: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	sr := &data.VelocitySeries{
-		Months:  make([]string, 0),
-		Count:   make([]int, 0),
-		AvgDays: make([]float64, 0),
+	samples, err := s.queryVelocitySamples(query, f, br)
+	if err != nil {
+		return nil, err
 	}
 
-	for rows.Next() {
-		var month string
-		var cnt int
-		var avgDays float64
-		if err := rows.Scan(&month, &cnt, &avgDays); err != nil {
-			return nil, fmt.Errorf("failed to scan velocity row: %w", err)
-		}
-		sr.Months = append(sr.Months, month)
-		sr.Count = append(sr.Count, cnt)
-		sr.AvgDays = append(sr.AvgDays, avgDays)
-	}
+	sr := velocitySeries(samples)
+	br.align(sr)
+	return sr, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+// queryVelocitySamples returns the (month, days) samples of a velocity
+// query by bucket of the range.
+func (s *Store) queryVelocitySamples(query string, f *data.InsightsFilter, br *bucketRange) (*durationSamples, error) {
+	rows, err := s.db.Query(br.query(query), f.Org, f.Repo, f.Entity, br.since(), f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query velocity series: %w", err)
 	}
+	defer rows.Close()
 
-	return sr, nil
+	return scanDurationSamples(rows)
 }
 
-func (s *Store) GetTimeToMerge(org, repo, entity *string, months int) (*data.VelocitySeries, error) {
-	return s.getVelocitySeries(selectTimeToMergeSQL, org, repo, entity, months)
+func (s *Store) GetTimeToMerge(f *data.InsightsFilter) (*data.VelocitySeries, error) {
+	return s.getVelocitySeries(selectTimeToMergeSQL, f)
 }
 
-func (s *Store) GetTimeToClose(org, repo, entity *string, months int) (*data.VelocitySeries, error) {
-	return s.getVelocitySeries(selectTimeToCloseSQL, org, repo, entity, months)
+func (s *Store) GetTimeToClose(f *data.InsightsFilter) (*data.VelocitySeries, error) {
+	return s.getVelocitySeries(selectTimeToCloseSQL, f)
 }
 
-func (s *Store) GetTimeToRestoreBugs(org, repo, entity *string, months int) (*data.VelocitySeries, error) {
-	return s.getVelocitySeries(selectTimeToRestoreBugsSQL, org, repo, entity, months)
+// GetTimeToRestoreBugs returns the days to restore failed changes, as
+// classified by the failure rules of the change failure rate, by bucket of
+// their creation.
+func (s *Store) GetTimeToRestoreBugs(f *data.InsightsFilter) (*data.VelocitySeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := s.queryRestoreSamples(f, br)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := velocitySeries(samples)
+	br.align(sr)
+	return sr, nil
 }
 
-func (s *Store) GetPRSizeDistribution(org, repo, entity *string, months int) (*data.PRSizeSeries, error) {
+func (s *Store) GetPRSizeDistribution(f *data.InsightsFilter) (*data.PRSizeSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectPRSizeDistributionSQL, org, repo, entity, since)
+	rows, err := s.db.Query(br.query(selectPRSizeDistributionSQL), f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query PR size distribution: %w", err)
 	}
@@ -813,17 +815,22 @@ func (s *Store) GetPRSizeDistribution(org, repo, entity *string, months int) (*d
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetForksAndActivity(org, repo, entity *string, months int) (*data.ForksAndActivitySeries, error) {
+func (s *Store) GetForksAndActivity(f *data.InsightsFilter) (*data.ForksAndActivitySeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectForksAndActivitySQL, org, repo, entity, since)
+	rows, err := s.db.Query(br.query(selectForksAndActivitySQL), f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query forks and activity: %w", err)
 	}
@@ -850,17 +857,23 @@ func (s *Store) GetForksAndActivity(org, repo, entity *string, months int) (*dat
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetContributorFunnel(org, repo, entity *string, months int) (*data.ContributorFunnelSeries, error) {
+func (s *Store) GetContributorFunnel(f *data.InsightsFilter) (*data.ContributorFunnelSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectContributorFunnelSQL, org, repo, entity, since)
+	rows, err := s.db.Query(br.query(selectContributorFunnelSQL),
+		f.IncludeDiscussions, f.Org, f.Repo, f.Entity, f.IncludeBots, f.Cohort, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query contributor funnel: %w", err)
 	}
@@ -889,40 +902,67 @@ func (s *Store) GetContributorFunnel(org, repo, entity *string, months int) (*da
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetContributorMomentum(org, repo, entity *string, months int) (*data.MomentumSeries, error) {
+// GetContributorMomentum returns the distinct contributors active in a
+// rolling window of the bucket and the buckets before it, with the change
+// from the previous bucket.
+func (s *Store) GetContributorMomentum(f *data.InsightsFilter) (*data.MomentumSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// the window of the first bucket reaches back before the range
+	lookback := br.lookback(momentumWindow - 1)
 
-	rows, err := s.db.Query(selectContributorMomentumSQL, since, org, repo, entity)
+	rows, err := s.db.Query(br.query(selectContributorMomentumSQL),
+		f.Org, f.Repo, f.Entity, lookback.Format(dateLayout), f.IncludeBots, f.Cohort, f.IncludeDiscussions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query contributor momentum: %w", err)
 	}
 	defer rows.Close()
 
+	active := make(map[string][]string)
+	for rows.Next() {
+		var month, username string
+		if err := rows.Scan(&month, &username); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor momentum row: %w", err)
+		}
+		active[month] = append(active[month], username)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
 	sr := &data.MomentumSeries{
 		Months: make([]string, 0),
 		Active: make([]int, 0),
 		Delta:  make([]int, 0),
 	}
 
-	for rows.Next() {
-		var month string
-		var active int
-		if err := rows.Scan(&month, &active); err != nil {
-			return nil, fmt.Errorf("failed to scan contributor momentum row: %w", err)
+	first := br.key(br.start)
+	keys := br.keysFrom(lookback)
+	for i, k := range keys {
+		if k < first || (!br.aligned && len(active[k]) == 0) {
+			continue
 		}
-		sr.Months = append(sr.Months, month)
-		sr.Active = append(sr.Active, active)
-	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		window := make(map[string]bool)
+		for j := max(0, i-momentumWindow+1); j <= i; j++ {
+			for _, u := range active[keys[j]] {
+				window[u] = true
+			}
+		}
+		sr.Months = append(sr.Months, k)
+		sr.Active = append(sr.Active, len(window))
 	}
 
 	for i := range sr.Active {
@@ -936,7 +976,7 @@ func (s *Store) GetContributorMomentum(org, repo, entity *string, months int) (*
 	return sr, nil
 }
 
-func (s *Store) GetContributorProfile(username string, org, repo, entity *string, months int) (*data.ContributorProfileSeries, error) {
+func (s *Store) GetContributorProfile(username string, f *data.InsightsFilter) (*data.ContributorProfileSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
@@ -945,16 +985,20 @@ func (s *Store) GetContributorProfile(username string, org, repo, entity *string
 		return nil, fmt.Errorf("username is required")
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
 	var prs, prsMerged, reviews, issues, comments int
 	var prSmall, prMedium, prLarge, prXLarge int
 	var avgPrs, avgMerged, avgReviews, avgIssues, avgComments float64
 	var avgSmall, avgMedium, avgLarge, avgXLarge float64
 
-	err := s.db.QueryRow(selectContributorProfileSQL,
-		username, org, repo, entity, since,
-		org, repo, entity, since,
+	err = s.db.QueryRow(selectContributorProfileSQL,
+		username, f.Org, f.Repo, f.Entity, since, br.until(),
+		f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort,
 	).Scan(
 		&prs, &prsMerged, &reviews, &issues, &comments,
 		&prSmall, &prMedium, &prLarge, &prXLarge,
@@ -980,21 +1024,29 @@ func (s *Store) GetContributorProfile(username string, org, repo, entity *string
 	return result, nil
 }
 
-func getMonthDualSeries[T int | float64](db *sql.DB, query string, org, repo, entity *string, months int) ([]string, []T, []T, error) {
+func getMonthDualSeries[T int | float64](db *sql.DB, query string, f *data.InsightsFilter, br *bucketRange) ([]string, []T, []T, error) {
 	if db == nil {
 		return nil, nil, nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	since := br.since()
 
-	rows, err := db.Query(query, org, repo, entity, since, org, repo, entity, since)
+	return queryMonthDualSeries[T](db, br.query(query),
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
+}
+
+// queryMonthDualSeries runs a query returning a month and two values per
+// row with the given arguments.
+func queryMonthDualSeries[T int | float64](db *sql.DB, query string, args ...any) ([]string, []T, []T, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to query month dual series: %w", err)
 	}
 	defer rows.Close()
 
-	var ms []string
-	var a, b []T
+	ms := make([]string, 0)
+	a, b := make([]T, 0), make([]T, 0)
 
 	for rows.Next() {
 		var month string
@@ -1014,18 +1066,95 @@ func getMonthDualSeries[T int | float64](db *sql.DB, query string, org, repo, en
 	return ms, a, b, nil
 }
 
-func (s *Store) GetTimeToFirstResponse(org, repo, entity *string, months int) (*data.FirstResponseSeries, error) {
-	ms, issueAvg, prAvg, err := getMonthDualSeries[float64](s.db, selectTimeToFirstResponseSQL, org, repo, entity, months)
+func (s *Store) GetTimeToFirstResponse(f *data.InsightsFilter) (*data.FirstResponseSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
 	if err != nil {
 		return nil, err
 	}
-	return &data.FirstResponseSeries{Months: ms, IssueAvg: issueAvg, PRAvg: prAvg}, nil
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectTimeToFirstResponseSQL),
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time to first response: %w", err)
+	}
+	defer rows.Close()
+
+	// all four sample sets share the months of the rows
+	issues, issuesBusiness := newDurationSamples(), newDurationSamples()
+	prs, prsBusiness := newDurationSamples(), newDurationSamples()
+	for rows.Next() {
+		var kind, month, startAt, endAt string
+		if err := rows.Scan(&kind, &month, &startAt, &endAt); err != nil {
+			return nil, fmt.Errorf("failed to scan time to first response row: %w", err)
+		}
+
+		for _, d := range []*durationSamples{issues, issuesBusiness, prs, prsBusiness} {
+			d.bucket(month)
+		}
+
+		start, end, ok := parseTimeRange(startAt, endAt)
+		if !ok {
+			continue
+		}
+		if kind == data.EventTypePR {
+			prs.add(month, end.Sub(start).Hours())
+			prsBusiness.add(month, businessHours(start, end))
+		} else {
+			issues.add(month, end.Sub(start).Hours())
+			issuesBusiness.add(month, businessHours(start, end))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	issue := reviewLatencySeries(issues, issuesBusiness)
+	pr := reviewLatencySeries(prs, prsBusiness)
+
+	sr := &data.FirstResponseSeries{
+		Months:           issues.months,
+		IssueCount:       issue.Count,
+		IssueAvg:         issue.AvgHours,
+		IssueP50:         issue.P50Hours,
+		IssueP75:         issue.P75Hours,
+		IssueP90:         issue.P90Hours,
+		IssueMin:         issue.MinHours,
+		IssueMax:         issue.MaxHours,
+		PRCount:          pr.Count,
+		PRAvg:            pr.AvgHours,
+		PRP50:            pr.P50Hours,
+		PRP75:            pr.P75Hours,
+		PRP90:            pr.P90Hours,
+		PRMin:            pr.MinHours,
+		PRMax:            pr.MaxHours,
+		IssueBusinessAvg: issue.BusinessAvgHours,
+		PRBusinessAvg:    pr.BusinessAvgHours,
+		IssueBusinessP50: issue.BusinessP50Hours,
+		PRBusinessP50:    pr.BusinessP50Hours,
+	}
+	br.align(sr)
+	return sr, nil
 }
 
-func (s *Store) GetIssueOpenCloseRatio(org, repo, entity *string, months int) (*data.IssueRatioSeries, error) {
-	ms, opened, closed, err := getMonthDualSeries[int](s.db, selectIssueOpenCloseRatioSQL, org, repo, entity, months)
+func (s *Store) GetIssueOpenCloseRatio(f *data.InsightsFilter) (*data.IssueRatioSeries, error) {
+	br, err := newBucketRange(f)
 	if err != nil {
 		return nil, err
 	}
-	return &data.IssueRatioSeries{Months: ms, Opened: opened, Closed: closed}, nil
+
+	ms, opened, closed, err := getMonthDualSeries[int](s.db, selectIssueOpenCloseRatioSQL, f, br)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &data.IssueRatioSeries{Months: ms, Opened: opened, Closed: closed}
+	br.align(sr)
+	return sr, nil
 }
@@ -12,7 +12,7 @@ import (
 func TestGetInsightsSummary_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
 
-	summary, err := store.GetInsightsSummary(nil, nil, nil, 6)
+	summary, err := store.GetInsightsSummary(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Equal(t, 0, summary.BusFactor)
 	assert.Equal(t, 0, summary.PonyFactor)
@@ -23,7 +23,7 @@ func TestGetInsightsSummary_EmptyDB(t *testing.T) {
 
 func TestGetInsightsSummary_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetInsightsSummary(nil, nil, nil, 6)
+	_, err := s.GetInsightsSummary(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -62,7 +62,7 @@ func TestGetInsightsSummary_WithData(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	summary, err := store.GetInsightsSummary(nil, nil, nil, 24)
+	summary, err := store.GetInsightsSummary(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, summary.BusFactor, 1)
 	assert.GreaterOrEqual(t, summary.PonyFactor, 1)
@@ -76,14 +76,14 @@ func TestGetInsightsSummary_WithData(t *testing.T) {
 func TestGetContributorRetention_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
 
-	series, err := store.GetContributorRetention(nil, nil, nil, 6)
+	series, err := store.GetContributorRetention(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
 
 func TestGetContributorRetention_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetContributorRetention(nil, nil, nil, 6)
+	_, err := s.GetContributorRetention(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -100,7 +100,7 @@ func TestGetContributorRetention_WithData(t *testing.T) {
 		('org1', 'repo1', 'bob', 'pr', '2025-02-15', 'http://b', '', '')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContributorRetention(nil, nil, nil, 24)
+	series, err := store.GetContributorRetention(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 2)
 
@@ -118,14 +118,14 @@ func TestGetContributorRetention_WithData(t *testing.T) {
 func TestGetPRReviewRatio_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
 
-	series, err := store.GetPRReviewRatio(nil, nil, nil, 6)
+	series, err := store.GetPRReviewRatio(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
 
 func TestGetPRReviewRatio_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetPRReviewRatio(nil, nil, nil, 6)
+	_, err := s.GetPRReviewRatio(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -143,7 +143,7 @@ func TestGetPRReviewRatio_WithData(t *testing.T) {
 		('org1', 'repo1', 'bob', 'pr_review', '2025-01-12', 'http://b3', '', '')`)
 	require.NoError(t, err)
 
-	series, err := store.GetPRReviewRatio(nil, nil, nil, 24)
+	series, err := store.GetPRReviewRatio(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 2, series.PRs[0])
@@ -153,14 +153,14 @@ func TestGetPRReviewRatio_WithData(t *testing.T) {
 
 func TestGetTimeToMerge_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetTimeToMerge(nil, nil, nil, 6)
+	series, err := store.GetTimeToMerge(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
 
 func TestGetTimeToMerge_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetTimeToMerge(nil, nil, nil, 6)
+	_, err := s.GetTimeToMerge(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -176,7 +176,7 @@ func TestGetTimeToMerge_WithData(t *testing.T) {
 		('org1', 'repo1', 'alice', 'pr', '2025-01-20', 'http://a2', '', '', 'closed', '2025-01-18T00:00:00Z', '2025-01-20T00:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetTimeToMerge(nil, nil, nil, 24)
+	series, err := store.GetTimeToMerge(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 2, series.Count[0])
@@ -185,14 +185,14 @@ func TestGetTimeToMerge_WithData(t *testing.T) {
 
 func TestGetTimeToClose_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetTimeToClose(nil, nil, nil, 6)
+	series, err := store.GetTimeToClose(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
 
 func TestGetTimeToClose_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetTimeToClose(nil, nil, nil, 6)
+	_, err := s.GetTimeToClose(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -208,7 +208,7 @@ func TestGetTimeToClose_WithData(t *testing.T) {
 		('org1', 'repo1', 'alice', 'issue', '2025-01-20', 'http://a2', '', '', 'closed', '2025-01-14T00:00:00Z', '2025-01-20T00:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetTimeToClose(nil, nil, nil, 24)
+	series, err := store.GetTimeToClose(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 2, series.Count[0])
@@ -217,13 +217,13 @@ func TestGetTimeToClose_WithData(t *testing.T) {
 
 func TestGetTimeToRestoreBugs_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetTimeToRestoreBugs(nil, nil, nil, 6)
+	_, err := s.GetTimeToRestoreBugs(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetTimeToRestoreBugs_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetTimeToRestoreBugs(nil, nil, nil, 6)
+	series, err := store.GetTimeToRestoreBugs(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -254,23 +254,35 @@ func TestGetTimeToRestoreBugs_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'alice', 'issue', '2025-02-17', 'http://c', '', 'bug', 'closed', '2025-02-17T10:00:00Z', '2025-02-20T10:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetTimeToRestoreBugs(nil, nil, nil, 24)
+	series, err := store.GetTimeToRestoreBugs(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1) // Only January has a qualifying bug
 	assert.Equal(t, "2025-01", series.Months[0])
 	assert.Equal(t, 1, series.Count[0])
 	assert.InDelta(t, 1.0, series.AvgDays[0], 0.01) // 1 day
+
+	// Uses the same rules as the change failure rate
+	store.failures = testFailureClassifier(t, `version: 1
+rules:
+  - labels: [bug]
+    windowDays: 0
+`)
+	series, err = store.GetTimeToRestoreBugs(&data.InsightsFilter{Months: 24})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 2)
+	assert.Equal(t, "2025-02", series.Months[1])
+	assert.InDelta(t, 3.0, series.AvgDays[1], 0.01)
 }
 
 func TestGetChangeFailureRate_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetChangeFailureRate(nil, nil, nil, 6)
+	_, err := s.GetChangeFailureRate(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetChangeFailureRate_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetChangeFailureRate(nil, nil, nil, 6)
+	series, err := store.GetChangeFailureRate(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -296,7 +308,7 @@ func TestGetChangeFailureRate_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'alice', 'pr', '2025-01-16', 'http://b', '', '', 'merged', '2025-01-16T10:00:00Z', 'Revert "Add feature"')`)
 	require.NoError(t, err)
 
-	series, err := store.GetChangeFailureRate(nil, nil, nil, 24)
+	series, err := store.GetChangeFailureRate(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.NotEmpty(t, series.Months)
 	assert.Equal(t, "2025-01", series.Months[0])
@@ -307,13 +319,13 @@ func TestGetChangeFailureRate_WithData(t *testing.T) {
 
 func TestGetReviewLatency_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetReviewLatency(nil, nil, nil, 6)
+	_, err := s.GetReviewLatency(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetReviewLatency_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetReviewLatency(nil, nil, nil, 6)
+	series, err := store.GetReviewLatency(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -339,7 +351,7 @@ func TestGetReviewLatency_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'bob', 'pr_review', '2025-01-11', 'http://c', '', '', 42, '2025-01-10T22:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetReviewLatency(nil, nil, nil, 24)
+	series, err := store.GetReviewLatency(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.NotEmpty(t, series.Months)
 
@@ -358,13 +370,13 @@ func TestGetReviewLatency_WithData(t *testing.T) {
 
 func TestGetPRSizeDistribution_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetPRSizeDistribution(nil, nil, nil, 6)
+	_, err := s.GetPRSizeDistribution(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetPRSizeDistribution_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetPRSizeDistribution(nil, nil, nil, 6)
+	series, err := store.GetPRSizeDistribution(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -395,7 +407,7 @@ func TestGetPRSizeDistribution_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'alice', 'pr', '2025-01-13', 'http://d', '', '', 'merged', '2025-01-13T10:00:00Z', 1000, 500)`)
 	require.NoError(t, err)
 
-	series, err := store.GetPRSizeDistribution(nil, nil, nil, 24)
+	series, err := store.GetPRSizeDistribution(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, "2025-01", series.Months[0])
@@ -407,13 +419,13 @@ func TestGetPRSizeDistribution_WithData(t *testing.T) {
 
 func TestGetContributorMomentum_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetContributorMomentum(nil, nil, nil, 6)
+	_, err := s.GetContributorMomentum(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetContributorMomentum_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetContributorMomentum(nil, nil, nil, 6)
+	series, err := store.GetContributorMomentum(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -435,7 +447,7 @@ func TestGetContributorMomentum_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'alice', 'pr', '2025-02-10', 'http://c', '', '')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContributorMomentum(nil, nil, nil, 24)
+	series, err := store.GetContributorMomentum(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 2)
 
@@ -452,13 +464,13 @@ func TestGetContributorMomentum_WithData(t *testing.T) {
 
 func TestGetContributorFunnel_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetContributorFunnel(nil, nil, nil, 6)
+	_, err := s.GetContributorFunnel(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetContributorFunnel_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetContributorFunnel(nil, nil, nil, 6)
+	series, err := store.GetContributorFunnel(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -481,7 +493,7 @@ func TestGetContributorFunnel_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'bob', 'issue_comment', '2025-01-08', 'http://c', '', '')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContributorFunnel(nil, nil, nil, 24)
+	series, err := store.GetContributorFunnel(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.NotEmpty(t, series.Months)
 
@@ -501,13 +513,13 @@ func TestGetContributorFunnel_WithData(t *testing.T) {
 
 func TestGetContributorProfile_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetContributorProfile("alice", nil, nil, nil, 6)
+	_, err := s.GetContributorProfile("alice", &data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetContributorProfile_EmptyUsername(t *testing.T) {
 	store := setupTestDB(t)
-	_, err := store.GetContributorProfile("", nil, nil, nil, 6)
+	_, err := store.GetContributorProfile("", &data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -517,7 +529,7 @@ func TestGetContributorProfile_EmptyDB(t *testing.T) {
 	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES ('alice', 'Alice', 'ACME')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContributorProfile("alice", nil, nil, nil, 6)
+	series, err := store.GetContributorProfile("alice", &data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Len(t, series.Metrics, 9)
 	assert.Len(t, series.Values, 9)
@@ -559,7 +571,7 @@ func TestGetContributorProfile_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'bob', 'issue_comment', '2026-01-11', 'http://b2', '', '')`)
 	require.NoError(t, err)
 
-	series, err := store.GetContributorProfile("alice", nil, nil, nil, 24)
+	series, err := store.GetContributorProfile("alice", &data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	assert.Len(t, series.Metrics, 9)
 	// alice: PRs opened = 4 (3 open + 1 merged)
@@ -576,13 +588,13 @@ func TestGetContributorProfile_WithData(t *testing.T) {
 
 func TestGetIssueOpenCloseRatio_NilDB(t *testing.T) {
 	s := &Store{}
-	_, err := s.GetIssueOpenCloseRatio(nil, nil, nil, 6)
+	_, err := s.GetIssueOpenCloseRatio(&data.InsightsFilter{Months: 6})
 	require.ErrorIs(t, err, data.ErrDBNotInitialized)
 }
 
 func TestGetIssueOpenCloseRatio_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetIssueOpenCloseRatio(nil, nil, nil, 6)
+	series, err := store.GetIssueOpenCloseRatio(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -600,7 +612,7 @@ func TestGetIssueOpenCloseRatio_WithData(t *testing.T) {
 		('org1', 'repo1', 'alice', 'issue', '2025-01-15', 'http://c', '', '', 'closed', '2025-01-08T00:00:00Z', '2025-01-15T00:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetIssueOpenCloseRatio(nil, nil, nil, 24)
+	series, err := store.GetIssueOpenCloseRatio(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, "2025-01", series.Months[0])
@@ -610,13 +622,13 @@ func TestGetIssueOpenCloseRatio_WithData(t *testing.T) {
 
 func TestGetTimeToFirstResponse_NilDB(t *testing.T) {
 	s := &Store{}
-	_, err := s.GetTimeToFirstResponse(nil, nil, nil, 6)
+	_, err := s.GetTimeToFirstResponse(&data.InsightsFilter{Months: 6})
 	require.ErrorIs(t, err, data.ErrDBNotInitialized)
 }
 
 func TestGetTimeToFirstResponse_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetTimeToFirstResponse(nil, nil, nil, 6)
+	series, err := store.GetTimeToFirstResponse(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -636,7 +648,7 @@ func TestGetTimeToFirstResponse_WithData(t *testing.T) {
 		('org1', 'repo1', 'bob', 'pr_review', '2025-01-10', 'http://p/2#r1', '', '', NULL, 2, '2025-01-10T12:00:00Z')`)
 	require.NoError(t, err)
 
-	series, err := store.GetTimeToFirstResponse(nil, nil, nil, 24)
+	series, err := store.GetTimeToFirstResponse(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, "2025-01", series.Months[0])
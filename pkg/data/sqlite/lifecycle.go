@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// selectLifecycleActivitySQL counts the events of each contributor per
+	// month, counting discussions only when opted in.
+	selectLifecycleActivitySQL = `SELECT substr(e.date, 1, 7) AS month, e.username, COUNT(*) AS cnt
+	FROM event e
+	JOIN developer d ON e.username = d.username
+	WHERE e.org = COALESCE(?, e.org)
+	  AND e.repo = COALESCE(?, e.repo)
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  AND e.date >= ?
+	  AND e.date < ?
+	  ` + developerFilterSQL + `
+	  ` + forkExcludeSQL + `
+	  ` + discussionFilterSQL + `
+	GROUP BY month, e.username
+	ORDER BY month
+	`
+)
+
+// lifecycleRank orders the active stages for promotions.
+var lifecycleRank = map[string]int{
+	data.LifecycleCasual:  1,
+	data.LifecycleRegular: 2,
+	data.LifecycleCore:    3,
+}
+
+// GetContributorLifecycle classifies every contributor in each bucket as
+// Casual, Regular, Core or Lapsed by their events in a trailing window, and
+// counts the transitions between stages. Nil thresholds use the defaults.
+func (s *Store) GetContributorLifecycle(f *data.InsightsFilter, t *data.LifecycleThresholds) (*data.LifecycleSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	if t == nil {
+		t = data.DefaultLifecycleThresholds()
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// the bucket before the range needs its window and the window before
+	// that to tell lapsed contributors
+	lookback := br.lookback(2 * t.Window)
+
+	rows, err := s.db.Query(br.query(selectLifecycleActivitySQL),
+		f.Org, f.Repo, f.Entity, lookback.Format(dateLayout), br.until(), f.IncludeBots, f.Cohort, f.IncludeDiscussions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contributor lifecycle: %w", err)
+	}
+	defer rows.Close()
+
+	events := make(map[string]map[string]int)
+	for rows.Next() {
+		var month, username string
+		var cnt int
+		if err := rows.Scan(&month, &username, &cnt); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor lifecycle row: %w", err)
+		}
+		if events[month] == nil {
+			events[month] = make(map[string]int)
+		}
+		events[month][username] += cnt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	sr := &data.LifecycleSeries{
+		Months:      make([]string, 0),
+		Thresholds:  t,
+		Casual:      make([]int, 0),
+		Regular:     make([]int, 0),
+		Core:        make([]int, 0),
+		Lapsed:      make([]int, 0),
+		Promoted:    make([]int, 0),
+		Transitions: make(map[string]map[string]int),
+	}
+
+	// window sums the events per contributor in the window ending with
+	// keys[i]; indexes before the first key are empty.
+	keys := br.keysFrom(lookback)
+	window := func(i int) map[string]int {
+		sum := make(map[string]int)
+		for j := max(0, i-t.Window+1); j <= i; j++ {
+			for u, c := range events[keys[j]] {
+				sum[u] += c
+			}
+		}
+		return sum
+	}
+
+	first := br.key(br.start)
+	var prev map[string]string
+	for i, k := range keys {
+		cur, before := window(i), window(i-t.Window)
+		stages := make(map[string]string, len(cur))
+		for u, c := range cur {
+			stages[u] = t.Stage(c)
+		}
+		for u := range before {
+			if _, ok := cur[u]; !ok {
+				stages[u] = data.LifecycleLapsed
+			}
+		}
+
+		if k >= first {
+			counts := make(map[string]int)
+			for _, st := range stages {
+				counts[st]++
+			}
+
+			var promoted int
+			for u, st := range stages {
+				from := prev[u]
+				if from == "" {
+					from = data.LifecycleNone
+				}
+				if r := lifecycleRank[from]; r > 0 && lifecycleRank[st] > r {
+					promoted++
+				}
+				addTransition(sr.Transitions, from, st)
+			}
+			for u, st := range prev {
+				if _, ok := stages[u]; !ok {
+					addTransition(sr.Transitions, st, data.LifecycleNone)
+				}
+			}
+
+			if br.aligned || len(events[k]) > 0 {
+				sr.Months = append(sr.Months, k)
+				sr.Casual = append(sr.Casual, counts[data.LifecycleCasual])
+				sr.Regular = append(sr.Regular, counts[data.LifecycleRegular])
+				sr.Core = append(sr.Core, counts[data.LifecycleCore])
+				sr.Lapsed = append(sr.Lapsed, counts[data.LifecycleLapsed])
+				sr.Promoted = append(sr.Promoted, promoted)
+			}
+		}
+		prev = stages
+	}
+
+	return sr, nil
+}
+
+func addTransition(m map[string]map[string]int, from, to string) {
+	if m[from] == nil {
+		m[from] = make(map[string]int)
+	}
+	m[from][to]++
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContributorLifecycle_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetContributorLifecycle(&data.InsightsFilter{Months: 6}, nil)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetContributorLifecycle_InvalidThresholds(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.GetContributorLifecycle(&data.InsightsFilter{Months: 6},
+		&data.LifecycleThresholds{Window: 3, Regular: 5, Core: 5})
+	assert.Error(t, err)
+}
+
+func TestGetContributorLifecycle_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob'), ('carol', 'Carol')`)
+	require.NoError(t, err)
+
+	activity := map[string]map[string]int{
+		"alice": {"2025-01": 2, "2025-02": 4, "2025-03": 4},
+		"bob":   {"2025-02": 1, "2025-03": 2, "2025-04": 4},
+		"carol": {"2025-01": 1},
+	}
+	for username, months := range activity {
+		for month, n := range months {
+			for i := 1; i <= n; i++ {
+				day := fmt.Sprintf("%s-%02d", month, i)
+				_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+					VALUES ('org1', 'repo1', ?, 'issue_comment', ?, ?, '', '')`,
+					username, day, "http://c/"+username+day)
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	f := &data.InsightsFilter{Granularity: data.GranularityMonth, From: strPtr("2025-02-01"), To: strPtr("2025-04-30")}
+	lt := &data.LifecycleThresholds{Window: 1, Regular: 2, Core: 4}
+	sr, err := store.GetContributorLifecycle(f, lt)
+	require.NoError(t, err)
+
+	assert.Equal(t, lt, sr.Thresholds)
+	assert.Equal(t, []string{"2025-02", "2025-03", "2025-04"}, sr.Months)
+	assert.Equal(t, []int{1, 0, 0}, sr.Casual)
+	assert.Equal(t, []int{0, 1, 0}, sr.Regular)
+	assert.Equal(t, []int{1, 1, 1}, sr.Core)
+	assert.Equal(t, []int{1, 0, 1}, sr.Lapsed)
+	assert.Equal(t, []int{1, 1, 1}, sr.Promoted)
+
+	assert.Equal(t, map[string]map[string]int{
+		data.LifecycleNone:    {data.LifecycleCasual: 1},
+		data.LifecycleCasual:  {data.LifecycleLapsed: 1, data.LifecycleRegular: 1},
+		data.LifecycleRegular: {data.LifecycleCore: 2},
+		data.LifecycleCore:    {data.LifecycleCore: 1, data.LifecycleLapsed: 1},
+		data.LifecycleLapsed:  {data.LifecycleNone: 1},
+	}, sr.Transitions)
+}
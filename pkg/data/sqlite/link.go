@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	insertPRIssueLinkSQL = `INSERT OR IGNORE INTO pr_issue_link (
+			org, repo, pr_number, issue_org, issue_repo, issue_number, source
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// insertConnectedLinksSQL pairs the connected timeline entries GitHub
+	// records on both the PR and the issue when they are linked in the UI.
+	// Both sides share the actor and timestamp.
+	insertConnectedLinksSQL = `INSERT OR IGNORE INTO pr_issue_link (
+			org, repo, pr_number, issue_org, issue_repo, issue_number, source
+		)
+		SELECT p.org, p.repo, p.number, i.org, i.repo, i.number, 'connected'
+		FROM timeline_event p
+		JOIN timeline_event i ON i.org = p.org AND i.repo = p.repo
+			AND i.kind = 'connected'
+			AND i.item_type = 'issue'
+			AND i.actor = p.actor
+			AND i.created_at = p.created_at
+		WHERE p.kind = 'connected'
+		  AND p.item_type = 'pr'
+		  AND p.org = ?
+		  AND p.repo = ?
+	`
+
+	// issueLinkFixesSQL lists issues linked to a merged PR with the earliest
+	// merge time and whether any fixing PR came from an external contributor,
+	// i.e. someone who is not a maintainer of that repo.
+	// Args: org, repo.
+	issueLinkFixesSQL = `SELECT
+			l.issue_org AS org,
+			l.issue_repo AS repo,
+			l.issue_number AS number,
+			MIN(p.merged_at) AS merged_at,
+			MAX(CASE WHEN (p.org, p.repo, p.username) NOT IN (` + maintainerSQL + `)
+				THEN 1 ELSE 0 END) AS external
+		FROM pr_issue_link l
+		JOIN event p ON p.org = l.org AND p.repo = l.repo AND p.number = l.pr_number
+			AND p.type = 'pr'
+			AND p.merged_at IS NOT NULL
+		WHERE l.issue_org = COALESCE(?, l.issue_org)
+		  AND l.issue_repo = COALESCE(?, l.issue_repo)
+		GROUP BY l.issue_org, l.issue_repo, l.issue_number`
+
+	// selectIssueResolutionSQL classifies closed issues by month of close.
+	// A fix by a merged PR wins over stale/won't-fix signals.
+	selectIssueResolutionSQL = `WITH issues AS (
+		SELECT e.org, e.repo, e.number,
+			MAX(e.closed_at) AS closed_at,
+			MAX(CASE WHEN e.state_reason = 'not_planned'
+				OR e.labels LIKE '%stale%'
+				OR e.labels LIKE '%wontfix%'
+				OR e.labels LIKE '%wont-fix%'
+				OR e.labels LIKE '%won''t fix%' THEN 1 ELSE 0 END) AS declined
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type = 'issue'
+		  AND e.number IS NOT NULL
+		  AND e.state = 'closed'
+		  AND e.closed_at IS NOT NULL
+		  AND e.url NOT LIKE '%/pull/%'
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND e.closed_at >= ?
+		  ` + developerFilterSQL + `
+		GROUP BY e.org, e.repo, e.number
+	),
+	fixes AS (` + issueLinkFixesSQL + `)
+	SELECT
+		substr(i.closed_at, 1, 7) AS month,
+		COUNT(*) AS closed,
+		SUM(CASE WHEN f.number IS NOT NULL THEN 1 ELSE 0 END) AS fixed,
+		SUM(CASE WHEN f.number IS NULL AND i.declined = 1 THEN 1 ELSE 0 END) AS declined,
+		SUM(CASE WHEN f.external = 1 THEN 1 ELSE 0 END) AS external
+	FROM issues i
+	LEFT JOIN fixes f ON f.org = i.org AND f.repo = i.repo AND f.number = i.number
+	GROUP BY month
+	ORDER BY month
+	`
+
+	// selectIssueFixLeadTimeSQL measures the days from issue creation to the
+	// merge of the first PR fixing it, by month of the merge.
+	selectIssueFixLeadTimeSQL = `WITH issues AS (
+		SELECT e.org, e.repo, e.number, MIN(e.created_at) AS created_at
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type = 'issue'
+		  AND e.number IS NOT NULL
+		  AND e.created_at IS NOT NULL
+		  AND e.url NOT LIKE '%/pull/%'
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  ` + developerFilterSQL + `
+		GROUP BY e.org, e.repo, e.number
+	),
+	fixes AS (` + issueLinkFixesSQL + `)
+	SELECT
+		substr(f.merged_at, 1, 7) AS month,
+		julianday(f.merged_at) - julianday(i.created_at) AS days
+	FROM issues i
+	JOIN fixes f ON f.org = i.org AND f.repo = i.repo AND f.number = i.number
+	WHERE f.merged_at >= ?
+	  AND f.merged_at >= i.created_at
+	ORDER BY month
+	`
+)
+
+// linkConnectedIssues records PR to issue links made in the GitHub UI from
+// the imported connected timeline entries.
+func (e *eventImporter) linkConnectedIssues(ctx context.Context) error {
+	res, err := e.store.db.ExecContext(ctx, insertConnectedLinksSQL, e.owner, e.repo)
+	if err != nil {
+		return fmt.Errorf("error inserting connected issue links: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		slog.Debug("connected issue links", "repo", e.owner+"/"+e.repo, "linked", n)
+	}
+	return nil
+}
+
+// GetIssueResolution returns closed issues per month split into fixed by a
+// merged PR, declined (not planned, stale, won't-fix) and fixed by external
+// contributors.
+func (s *Store) GetIssueResolution(f *data.InsightsFilter) (*data.IssueResolutionSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectIssueResolutionSQL),
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue resolution: %w", err)
+	}
+	defer rows.Close()
+
+	sr := &data.IssueResolutionSeries{
+		Months:    make([]string, 0),
+		Closed:    make([]int, 0),
+		Fixed:     make([]int, 0),
+		Declined:  make([]int, 0),
+		External:  make([]int, 0),
+		FixedRate: make([]float64, 0),
+	}
+
+	for rows.Next() {
+		var month string
+		var closed, fixed, declined, external int
+		if err := rows.Scan(&month, &closed, &fixed, &declined, &external); err != nil {
+			return nil, fmt.Errorf("failed to scan issue resolution row: %w", err)
+		}
+		sr.Months = append(sr.Months, month)
+		sr.Closed = append(sr.Closed, closed)
+		sr.Fixed = append(sr.Fixed, fixed)
+		sr.Declined = append(sr.Declined, declined)
+		sr.External = append(sr.External, external)
+
+		var rate float64
+		if closed > 0 {
+			rate = float64(fixed) / float64(closed) * 100
+		}
+		sr.FixedRate = append(sr.FixedRate, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	br.align(sr)
+	return sr, nil
+}
+
+// GetIssueFixLeadTime returns monthly statistics of the days from issue
+// creation to the merge of the PR that fixed it.
+func (s *Store) GetIssueFixLeadTime(f *data.InsightsFilter) (*data.VelocitySeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectIssueFixLeadTimeSQL),
+		f.Org, f.Repo, f.Entity, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue fix lead time: %w", err)
+	}
+	defer rows.Close()
+
+	samples, err := scanDurationSamples(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := velocitySeries(samples)
+	br.align(sr)
+	return sr, nil
+}
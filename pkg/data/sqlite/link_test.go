@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertLinkTestData(t *testing.T, store *Store) string {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob'), ('carol', 'Carol')`)
+	require.NoError(t, err)
+
+	base := time.Now().UTC().AddDate(0, -1, 0).Truncate(24 * time.Hour)
+	at := func(d int) string { return base.AddDate(0, 0, d).Format(time.RFC3339) }
+	day := func(d int) string { return base.AddDate(0, 0, d).Format("2006-01-02") }
+
+	// issue 1: fixed by bob (maintainer) 2 days after creation
+	// issue 2: fixed by carol (external) 4 days after creation
+	// issue 3: closed as not planned
+	// issue 4: closed with a stale label
+	// issue 5: closed, linked PR never merged
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, closed_at, merged_at, state_reason) VALUES
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/1', '', '', 'closed', 1, ?, ?, NULL, 'completed'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/2', '', '', 'closed', 2, ?, ?, NULL, 'completed'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/3', '', '', 'closed', 3, ?, ?, NULL, 'not_planned'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/4', '', 'lifecycle/stale', 'closed', 4, ?, ?, NULL, 'completed'),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/5', '', '', 'closed', 5, ?, ?, NULL, 'completed'),
+		('org1', 'repo1', 'bob', 'pr', ?, 'http://i/pull/10', '', '', 'closed', 10, ?, ?, ?, NULL),
+		('org1', 'repo1', 'carol', 'pr', ?, 'http://i/pull/11', '', '', 'closed', 11, ?, ?, ?, NULL),
+		('org1', 'repo1', 'carol', 'pr', ?, 'http://i/pull/12', '', '', 'closed', 12, ?, ?, NULL, NULL),
+		('org1', 'repo1', 'bob', 'pr_review', ?, 'http://i/pull/11#pullrequestreview-1', '', '', 'APPROVED', 11, ?, NULL, NULL, NULL)`,
+		day(0), at(0), at(2),
+		day(1), at(0), at(4),
+		day(2), at(0), at(1),
+		day(3), at(0), at(1),
+		day(4), at(0), at(1),
+		day(2), at(1), at(2), at(2),
+		day(4), at(3), at(4), at(4),
+		day(5), at(1), at(1),
+		day(4), at(4))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+		VALUES (1, 'org1', 'repo1', 11, 'bob', 'APPROVED', ?)`, at(4))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO pr_issue_link (org, repo, pr_number, issue_org, issue_repo, issue_number, source) VALUES
+		('org1', 'repo1', 10, 'org1', 'repo1', 1, 'keyword'),
+		('org1', 'repo1', 11, 'org1', 'repo1', 2, 'connected'),
+		('org1', 'repo1', 12, 'org1', 'repo1', 5, 'keyword')`)
+	require.NoError(t, err)
+
+	return base.Format("2006-01")
+}
+
+func TestLinkConnectedIssues(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at) VALUES
+		('org1', 'repo1', 7, 'pr', 'connected', 'bob', '', '2025-03-01T10:00:00Z'),
+		('org1', 'repo1', 3, 'issue', 'connected', 'bob', '', '2025-03-01T10:00:00Z'),
+		('org1', 'repo1', 4, 'issue', 'connected', 'bob', '', '2025-03-02T10:00:00Z')`)
+	require.NoError(t, err)
+
+	imp := &eventImporter{store: store, owner: "org1", repo: "repo1"}
+	require.NoError(t, imp.linkConnectedIssues(context.Background()))
+
+	var prNumber, issueNumber int
+	var source string
+	err = store.db.QueryRow(`SELECT pr_number, issue_number, source FROM pr_issue_link`).Scan(&prNumber, &issueNumber, &source)
+	require.NoError(t, err)
+	assert.Equal(t, 7, prNumber)
+	assert.Equal(t, 3, issueNumber)
+	assert.Equal(t, data.LinkSourceConnected, source)
+}
+
+func TestGetIssueResolution_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetIssueResolution(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetIssueResolution_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	series, err := store.GetIssueResolution(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Empty(t, series.Months)
+}
+
+func TestGetIssueResolution_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	month := insertLinkTestData(t, store)
+
+	series, err := store.GetIssueResolution(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, month, series.Months[0])
+	assert.Equal(t, 5, series.Closed[0])
+	assert.Equal(t, 2, series.Fixed[0])
+	assert.Equal(t, 2, series.Declined[0])
+	assert.Equal(t, 1, series.External[0])
+	assert.InDelta(t, 40.0, series.FixedRate[0], 0.01)
+
+	// org members are maintainers even if they never approved a PR
+	_, err = store.db.Exec(`INSERT INTO org_member (org, username, role, updated_at)
+		VALUES ('org1', 'carol', 'member', '2025-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+
+	series, err = store.GetIssueResolution(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 0, series.External[0])
+}
+
+func TestGetIssueFixLeadTime_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetIssueFixLeadTime(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetIssueFixLeadTime_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertLinkTestData(t, store)
+
+	series, err := store.GetIssueFixLeadTime(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, 2, series.Count[0])
+	assert.InDelta(t, 3.0, series.AvgDays[0], 0.01)
+}
@@ -0,0 +1,328 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/mchmarny/devpulse/pkg/data/ghutil"
+	"github.com/mchmarny/devpulse/pkg/net"
+)
+
+const (
+	deleteOrgMembersSQL = `DELETE FROM org_member WHERE org = ?`
+
+	insertOrgMemberSQL = `INSERT OR REPLACE INTO org_member (org, username, role, updated_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	// selectExternalContributionSQL counts PRs opened and merged and reviews
+	// submitted per month by contributor class: org member, outside
+	// collaborator, first-timer or returning contributor. Imported org
+	// membership wins over the association GitHub recorded on the event,
+	// which is missing for events imported before it was captured. PR rows
+	// are deduplicated by number; a PR is from a first-timer when GitHub
+	// flagged it so or, when no association was recorded, it is the author's
+	// first PR in the repo.
+	selectExternalContributionSQL = `WITH prs AS (
+		SELECT e.org, e.repo, e.number, e.username,
+			MIN(e.created_at) AS created_at,
+			MAX(e.merged_at) AS merged_at,
+			MAX(CASE WHEN e.author_association IN ('OWNER', 'MEMBER') THEN 'MEMBER'
+				WHEN e.author_association = 'COLLABORATOR' THEN 'COLLABORATOR' END) AS association,
+			MAX(CASE WHEN e.author_association IN ('FIRST_TIME_CONTRIBUTOR', 'FIRST_TIMER') THEN 1 ELSE 0 END) AS first_assoc,
+			MAX(CASE WHEN e.author_association IS NOT NULL THEN 1 ELSE 0 END) AS has_assoc
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type = 'pr'
+		  AND e.number IS NOT NULL
+		  AND e.created_at IS NOT NULL
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  ` + developerFilterSQL + `
+		GROUP BY e.org, e.repo, e.number, e.username
+	),
+	firsts AS (
+		SELECT f.org, f.repo, f.username, MIN(f.created_at) AS first_pr
+		FROM event f
+		WHERE f.type = 'pr'
+		  AND f.created_at IS NOT NULL
+		  AND f.org = COALESCE(?, f.org)
+		  AND f.repo = COALESCE(?, f.repo)
+		GROUP BY f.org, f.repo, f.username
+	),
+	classified AS (
+		SELECT p.created_at, p.merged_at,
+			CASE
+				WHEN m.role = 'member' OR p.association = 'MEMBER' THEN 'member'
+				WHEN m.role = 'outside_collaborator' OR p.association = 'COLLABORATOR' THEN 'collaborator'
+				WHEN p.first_assoc = 1 OR (p.has_assoc = 0 AND p.created_at = fi.first_pr) THEN 'first_timer'
+				ELSE 'contributor'
+			END AS class
+		FROM prs p
+		JOIN firsts fi ON fi.org = p.org AND fi.repo = p.repo AND fi.username = p.username
+		LEFT JOIN org_member m ON m.org = p.org AND m.username = p.username
+	)
+	SELECT 'opened' AS kind, substr(created_at, 1, 7) AS month, class, COUNT(*) AS items
+	FROM classified
+	WHERE created_at >= ?
+	GROUP BY month, class
+	UNION ALL
+	SELECT 'merged' AS kind, substr(merged_at, 1, 7) AS month, class, COUNT(*) AS items
+	FROM classified
+	WHERE merged_at >= ?
+	GROUP BY month, class
+	UNION ALL
+	SELECT 'reviews' AS kind, substr(e.date, 1, 7) AS month,
+		CASE
+			WHEN m.role = 'member' OR e.author_association IN ('OWNER', 'MEMBER') THEN 'member'
+			WHEN m.role = 'outside_collaborator' OR e.author_association = 'COLLABORATOR' THEN 'collaborator'
+			WHEN e.author_association IN ('FIRST_TIME_CONTRIBUTOR', 'FIRST_TIMER') THEN 'first_timer'
+			ELSE 'contributor'
+		END AS class,
+		COUNT(*) AS items
+	FROM event e
+	JOIN developer d ON e.username = d.username
+	LEFT JOIN org_member m ON m.org = e.org AND m.username = e.username
+	WHERE e.type = 'pr_review'
+	  AND e.org = COALESCE(?, e.org)
+	  AND e.repo = COALESCE(?, e.repo)
+	  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+	  AND e.date >= ?
+	  ` + developerFilterSQL + `
+	GROUP BY month, class
+	ORDER BY month
+	`
+)
+
+// ImportOrgMembers replaces the stored members and outside collaborators of
+// an org. Tokens without org access only see public members, and listing
+// outside collaborators requires an org owner, so that list is skipped when
+// not permitted. Returns the number of stored users; user accounts have no
+// members and return 0.
+func (s *Store) ImportOrgMembers(ctx context.Context, token, org string) (int, error) {
+	if s.db == nil {
+		return 0, data.ErrDBNotInitialized
+	}
+
+	client := github.NewClient(net.GetOAuthClient(ctx, token))
+
+	members, err := listOrgUsers(ctx, func(opt github.ListOptions) ([]*github.User, *github.Response, error) {
+		return client.Organizations.ListMembers(ctx, org, &github.ListMembersOptions{ListOptions: opt})
+	})
+	if err != nil {
+		if isNotFound(err) {
+			slog.Debug("no org members, owner is not an org", "org", org)
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error listing members of %s: %w", org, err)
+	}
+
+	collaborators, err := listOrgUsers(ctx, func(opt github.ListOptions) ([]*github.User, *github.Response, error) {
+		return client.Organizations.ListOutsideCollaborators(ctx, org, &github.ListOutsideCollaboratorsOptions{ListOptions: opt})
+	})
+	if err != nil {
+		slog.Debug("skipping outside collaborators", "org", org, "error", err)
+		collaborators = nil
+	}
+
+	roles := make(map[string]string, len(members)+len(collaborators))
+	for _, u := range collaborators {
+		roles[u.GetLogin()] = data.OrgRoleOutsideCollaborator
+	}
+	for _, u := range members {
+		roles[u.GetLogin()] = data.OrgRoleMember
+	}
+
+	if err := s.saveOrgMembers(org, roles); err != nil {
+		return 0, err
+	}
+
+	slog.Debug("org members done", "org", org, "members", len(members), "collaborators", len(collaborators))
+	return len(roles), nil
+}
+
+// ImportAllOrgMembers imports members for every previously imported org.
+func (s *Store) ImportAllOrgMembers(ctx context.Context, token string) error {
+	list, err := s.GetAllOrgRepos()
+	if err != nil {
+		return fmt.Errorf("error getting org/repo list: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range list {
+		if seen[r.Org] {
+			continue
+		}
+		seen[r.Org] = true
+		if _, err := s.ImportOrgMembers(ctx, token, r.Org); err != nil {
+			slog.Error("org members failed", "org", r.Org, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
+
+// listOrgUsers pages through a user list of an org.
+func listOrgUsers(ctx context.Context, list func(opt github.ListOptions) ([]*github.User, *github.Response, error)) ([]*github.User, error) {
+	opt := github.ListOptions{PerPage: pageSizeDefault, Page: 1}
+
+	users := make([]*github.User, 0)
+	for {
+		items, resp, err := list(opt)
+		if err != nil {
+			return nil, err
+		}
+		if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
+			return nil, err
+		}
+
+		users = append(users, items...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return users, nil
+}
+
+func (s *Store) saveOrgMembers(org string, roles map[string]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting org member tx: %w", err)
+	}
+
+	if _, err := tx.Exec(deleteOrgMembersSQL, org); err != nil {
+		rollbackTransaction(tx)
+		return fmt.Errorf("error clearing org members of %s: %w", org, err)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for username, role := range roles {
+		if _, err := tx.Exec(insertOrgMemberSQL, org, username, role, now); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting org member %s/%s: %w", org, username, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing org member tx: %w", err)
+	}
+	return nil
+}
+
+func newContributionBreakdown(months int) *data.ContributionBreakdown {
+	return &data.ContributionBreakdown{
+		Member:        make([]int, months),
+		Collaborator:  make([]int, months),
+		Contributor:   make([]int, months),
+		FirstTimer:    make([]int, months),
+		ExternalShare: make([]float64, months),
+	}
+}
+
+// GetExternalContributionShare returns PRs opened, PRs merged and reviews
+// per month split by contributor class, with the share made by people
+// outside the owning org.
+func (s *Store) GetExternalContributionShare(f *data.InsightsFilter) (*data.ExternalContributionShare, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectExternalContributionSQL),
+		f.Org, f.Repo, f.Entity, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo,
+		since, since,
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query external contribution share: %w", err)
+	}
+	defer rows.Close()
+
+	type count struct {
+		kind, month, class string
+		items              int
+	}
+	counts := make([]count, 0)
+	monthSet := make(map[string]bool)
+	for rows.Next() {
+		var c count
+		if err := rows.Scan(&c.kind, &c.month, &c.class, &c.items); err != nil {
+			return nil, fmt.Errorf("failed to scan external contribution row: %w", err)
+		}
+		counts = append(counts, c)
+		monthSet[c.month] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	months := make([]string, 0, len(monthSet))
+	for m := range monthSet {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	index := make(map[string]int, len(months))
+	for i, m := range months {
+		index[m] = i
+	}
+
+	res := &data.ExternalContributionShare{
+		Months:  months,
+		Opened:  newContributionBreakdown(len(months)),
+		Merged:  newContributionBreakdown(len(months)),
+		Reviews: newContributionBreakdown(len(months)),
+	}
+	kinds := map[string]*data.ContributionBreakdown{
+		"opened":  res.Opened,
+		"merged":  res.Merged,
+		"reviews": res.Reviews,
+	}
+
+	for _, c := range counts {
+		b, i := kinds[c.kind], index[c.month]
+		switch c.class {
+		case data.ContributorClassMember:
+			b.Member[i] += c.items
+		case data.ContributorClassCollaborator:
+			b.Collaborator[i] += c.items
+		case data.ContributorClassFirstTimer:
+			b.FirstTimer[i] += c.items
+		default:
+			b.Contributor[i] += c.items
+		}
+	}
+
+	for _, b := range kinds {
+		for i := range months {
+			external := b.Collaborator[i] + b.Contributor[i] + b.FirstTimer[i]
+			if total := external + b.Member[i]; total > 0 {
+				b.ExternalShare[i] = float64(external) / float64(total) * 100
+			}
+		}
+	}
+
+	br.align(res)
+	return res, nil
+}
@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertMembershipTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity, is_bot) VALUES
+		('alice', 'Alice', 'ACME', 0), ('bob', 'Bob', 'INITECH', 0), ('carol', 'Carol', '', 0),
+		('dave', 'Dave', '', 0), ('ci-bot', 'CI', '', 1)`)
+	require.NoError(t, err)
+
+	require.NoError(t, store.saveOrgMembers("org1", map[string]string{"alice": data.OrgRoleMember}))
+
+	now := time.Now().UTC()
+	day := func(d int) string { return now.AddDate(0, 0, -d).Format("2006-01-02") }
+	ts := func(d int) string { return now.AddDate(0, 0, -d).Format(time.RFC3339) }
+
+	// alice: org member, PR 1 merged
+	// bob: collaborator by association, PR 2 merged
+	// carol: no association; PR 3 is her first and merged, PR 4 still open
+	// dave: first-time contributor on the first row of PR 5, merged later
+	// ci-bot: excluded
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, merged_at, author_association) VALUES
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/1', '', '', 'merged', 1, ?, ?, NULL),
+		('org1', 'repo1', 'bob', 'pr', ?, 'http://p/2', '', '', 'merged', 2, ?, ?, 'COLLABORATOR'),
+		('org1', 'repo1', 'carol', 'pr', ?, 'http://p/3', '', '', 'merged', 3, ?, ?, NULL),
+		('org1', 'repo1', 'carol', 'pr', ?, 'http://p/4', '', '', 'open', 4, ?, NULL, NULL),
+		('org1', 'repo1', 'dave', 'pr', ?, 'http://p/5', '', '', 'open', 5, ?, NULL, 'FIRST_TIME_CONTRIBUTOR'),
+		('org1', 'repo1', 'dave', 'pr', ?, 'http://p/5', '', '', 'merged', 5, ?, ?, 'CONTRIBUTOR'),
+		('org1', 'repo1', 'ci-bot', 'pr', ?, 'http://p/6', '', '', 'merged', 6, ?, ?, 'NONE'),
+		('org1', 'repo1', 'alice', 'pr_review', ?, 'http://p/4#r1', '', '', 'APPROVED', 4, ?, NULL, 'MEMBER'),
+		('org1', 'repo1', 'carol', 'pr_review', ?, 'http://p/5#r2', '', '', 'APPROVED', 5, ?, NULL, 'CONTRIBUTOR')`,
+		day(9), ts(10), ts(9),
+		day(8), ts(10), ts(8),
+		day(15), ts(20), ts(15),
+		day(6), ts(6),
+		day(7), ts(7),
+		day(3), ts(7), ts(3),
+		day(5), ts(5), ts(5),
+		day(4), ts(4),
+		day(3), ts(3))
+	require.NoError(t, err)
+}
+
+func sumInts(v []int) int {
+	var n int
+	for _, i := range v {
+		n += i
+	}
+	return n
+}
+
+func TestSaveOrgMembers_Replaces(t *testing.T) {
+	store := setupTestDB(t)
+
+	require.NoError(t, store.saveOrgMembers("org1", map[string]string{
+		"alice": data.OrgRoleMember,
+		"bob":   data.OrgRoleOutsideCollaborator,
+	}))
+	require.NoError(t, store.saveOrgMembers("org1", map[string]string{
+		"alice": data.OrgRoleMember,
+	}))
+	require.NoError(t, store.saveOrgMembers("org2", map[string]string{
+		"bob": data.OrgRoleMember,
+	}))
+
+	var count int
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM org_member WHERE org = 'org1'`).Scan(&count))
+	assert.Equal(t, 1, count)
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM org_member`).Scan(&count))
+	assert.Equal(t, 2, count)
+}
+
+func TestImportOrgMembers_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.ImportOrgMembers(context.Background(), "token", "org1")
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetExternalContributionShare_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetExternalContributionShare(&data.InsightsFilter{Months: 6})
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetExternalContributionShare_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetExternalContributionShare(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Empty(t, res.Months)
+	assert.Empty(t, res.Merged.Member)
+}
+
+func TestGetExternalContributionShare_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertMembershipTestData(t, store)
+
+	res, err := store.GetExternalContributionShare(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Months)
+
+	assert.Equal(t, 1, sumInts(res.Opened.Member))
+	assert.Equal(t, 1, sumInts(res.Opened.Collaborator))
+	assert.Equal(t, 1, sumInts(res.Opened.Contributor))
+	assert.Equal(t, 2, sumInts(res.Opened.FirstTimer))
+
+	assert.Equal(t, 1, sumInts(res.Merged.Member))
+	assert.Equal(t, 1, sumInts(res.Merged.Collaborator))
+	assert.Equal(t, 0, sumInts(res.Merged.Contributor))
+	assert.Equal(t, 2, sumInts(res.Merged.FirstTimer))
+
+	assert.Equal(t, 1, sumInts(res.Reviews.Member))
+	assert.Equal(t, 1, sumInts(res.Reviews.Contributor))
+
+	// every month with merges has an external share between 0 and 100
+	for i := range res.Months {
+		merged := res.Merged.Member[i] + res.Merged.Collaborator[i] + res.Merged.Contributor[i] + res.Merged.FirstTimer[i]
+		if merged == 0 {
+			assert.Zero(t, res.Merged.ExternalShare[i])
+			continue
+		}
+		external := merged - res.Merged.Member[i]
+		assert.InDelta(t, float64(external)/float64(merged)*100, res.Merged.ExternalShare[i], 0.01)
+	}
+
+	entity := "ACME"
+	res, err = store.GetExternalContributionShare(&data.InsightsFilter{Months: 6, Entity: &entity})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sumInts(res.Merged.Member))
+	assert.Equal(t, 0, sumInts(res.Merged.FirstTimer))
+	for _, v := range res.Merged.ExternalShare {
+		assert.Zero(t, v)
+	}
+}
@@ -26,6 +26,7 @@ const (
 		WHERE org = COALESCE(?, org)
 		  AND repo = COALESCE(?, repo)
 		  AND date >= ?
+		  AND date < ?
 		ORDER BY org, repo, date
 	`
 
@@ -34,6 +35,7 @@ const (
 		FROM repo_metric_history
 		WHERE org = COALESCE(?, org)
 		  AND date >= ?
+		  AND date < ?
 		GROUP BY date
 		ORDER BY date
 	`
@@ -41,19 +43,26 @@ const (
 	backfillDays = 30
 )
 
-func (s *Store) GetRepoMetricHistory(org, repo *string, months int) ([]*data.RepoMetricHistory, error) {
+// GetRepoMetricHistory returns the daily star and fork totals in the range,
+// summed across repos unless the filter names one. The totals are
+// snapshots rather than counts, so they are not bucketed by granularity,
+// and the developer filters do not apply.
+func (s *Store) GetRepoMetricHistory(f *data.InsightsFilter) ([]*data.RepoMetricHistory, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since, until := br.since(), br.until()
 
 	var rows *sql.Rows
-	var err error
-	if repo == nil {
-		rows, err = s.db.Query(selectRepoMetricHistoryAggSQL, org, org, since)
+	if f.Repo == nil {
+		rows, err = s.db.Query(selectRepoMetricHistoryAggSQL, f.Org, f.Org, since, until)
 	} else {
-		rows, err = s.db.Query(selectRepoMetricHistorySQL, org, repo, since)
+		rows, err = s.db.Query(selectRepoMetricHistorySQL, f.Org, f.Repo, since, until)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to query repo metric history: %w", err)
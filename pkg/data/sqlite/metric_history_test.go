@@ -11,13 +11,13 @@ import (
 
 func TestGetRepoMetricHistory_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetRepoMetricHistory(nil, nil, 6)
+	_, err := s.GetRepoMetricHistory(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetRepoMetricHistory_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	list, err := store.GetRepoMetricHistory(nil, nil, 6)
+	list, err := store.GetRepoMetricHistory(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, list)
 }
@@ -32,7 +32,7 @@ func TestGetRepoMetricHistory_WithData(t *testing.T) {
 		('org1', 'repo1', '2026-03-12', 110, 55)`)
 	require.NoError(t, err)
 
-	list, err := store.GetRepoMetricHistory(nil, nil, 6)
+	list, err := store.GetRepoMetricHistory(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 3)
 	assert.Equal(t, "2026-03-10", list[0].Date)
@@ -51,7 +51,7 @@ func TestGetRepoMetricHistory_WithFilter(t *testing.T) {
 
 	org := "org1"
 	repo := "repo1"
-	list, err := store.GetRepoMetricHistory(&org, &repo, 6)
+	list, err := store.GetRepoMetricHistory(&data.InsightsFilter{Org: &org, Repo: &repo, Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 1)
 	assert.Equal(t, "org1", list[0].Org)
@@ -69,7 +69,7 @@ func TestGetRepoMetricHistory_AggregateByDate(t *testing.T) {
 	require.NoError(t, err)
 
 	org := "org1"
-	list, err := store.GetRepoMetricHistory(&org, nil, 6)
+	list, err := store.GetRepoMetricHistory(&data.InsightsFilter{Org: &org, Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 2)
 	assert.Equal(t, "2026-03-10", list[0].Date)
@@ -110,7 +110,7 @@ func TestUpsertMetricHistory(t *testing.T) {
 	err := store.upsertMetricHistory("org1", "repo1", history)
 	require.NoError(t, err)
 
-	list, err := store.GetRepoMetricHistory(nil, nil, 6)
+	list, err := store.GetRepoMetricHistory(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 2)
 	assert.Equal(t, 100, list[0].Stars)
@@ -120,7 +120,7 @@ func TestUpsertMetricHistory(t *testing.T) {
 	err = store.upsertMetricHistory("org1", "repo1", history)
 	require.NoError(t, err)
 
-	list, err = store.GetRepoMetricHistory(nil, nil, 6)
+	list, err = store.GetRepoMetricHistory(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 2)
 	assert.Equal(t, 101, list[0].Stars)
@@ -0,0 +1,474 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/mchmarny/devpulse/pkg/data/ghutil"
+	"github.com/mchmarny/devpulse/pkg/net"
+)
+
+const (
+	// milestoneItemWindowDays is how long after closing a milestone its
+	// items are still refreshed.
+	milestoneItemWindowDays = 90
+
+	// milestoneBurnupMaxDays caps the length of a burn-up series.
+	milestoneBurnupMaxDays = 365
+
+	upsertMilestoneSQL = `INSERT INTO milestone (
+			org, repo, number, title, state, url, due_on, created_at, closed_at,
+			open_issues, closed_issues, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(org, repo, number) DO UPDATE SET
+			title = excluded.title,
+			state = excluded.state,
+			url = excluded.url,
+			due_on = excluded.due_on,
+			created_at = excluded.created_at,
+			closed_at = excluded.closed_at,
+			open_issues = excluded.open_issues,
+			closed_issues = excluded.closed_issues,
+			updated_at = excluded.updated_at
+	`
+
+	deleteMilestoneItemsSQL = `DELETE FROM milestone_item WHERE org = ? AND repo = ? AND milestone = ?`
+
+	upsertMilestoneItemSQL = `INSERT INTO milestone_item (org, repo, milestone, number, item_type, created_at, closed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(org, repo, number) DO UPDATE SET
+			milestone = excluded.milestone,
+			item_type = excluded.item_type,
+			created_at = excluded.created_at,
+			closed_at = excluded.closed_at
+	`
+
+	// selectMilestonesSQL lists open milestones and those closed in the
+	// window with the number of their items closed since the rate window
+	// start (args: rate window start, org, repo, window start).
+	selectMilestonesSQL = `SELECT
+			m.org, m.repo, m.number, m.title, m.state, m.url, m.due_on, m.created_at, m.closed_at,
+			m.open_issues, m.closed_issues,
+			(
+				SELECT COUNT(*) FROM milestone_item i
+				WHERE i.org = m.org AND i.repo = m.repo AND i.milestone = m.number
+				  AND i.closed_at >= ?
+			) AS recent_closed
+		FROM milestone m
+		WHERE m.org = COALESCE(?, m.org)
+		  AND m.repo = COALESCE(?, m.repo)
+		  AND m.created_at < ?
+		  AND (m.state = 'open' OR (m.closed_at >= ? AND m.closed_at < ?))
+		ORDER BY m.state DESC, CASE WHEN m.due_on IS NULL THEN 1 ELSE 0 END, m.due_on, m.org, m.repo, m.number
+	`
+
+	selectMilestoneSQL = `SELECT org, repo, number, title, state, url, due_on, created_at, closed_at,
+			open_issues, closed_issues
+		FROM milestone
+		WHERE org = ? AND repo = ? AND number = ?
+	`
+
+	selectMilestoneItemsSQL = `SELECT milestone, number, item_type, created_at, closed_at
+		FROM milestone_item
+		WHERE org = ? AND repo = ? AND milestone = ?
+	`
+)
+
+// ImportMilestones imports the milestones of a repo and the items of open
+// and recently closed milestones, then the repo's project boards.
+func (s *Store) ImportMilestones(ctx context.Context, token, owner, repo string) error {
+	if s.db == nil {
+		return data.ErrDBNotInitialized
+	}
+
+	client := github.NewClient(net.GetOAuthClient(ctx, token))
+
+	milestones, err := listMilestones(ctx, client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -milestoneItemWindowDays)
+	for _, m := range milestones {
+		if err := s.saveMilestone(owner, repo, m); err != nil {
+			return err
+		}
+
+		if m.GetState() != "open" && m.ClosedAt != nil && m.ClosedAt.Before(cutoff) {
+			continue
+		}
+
+		items, err := listMilestoneItems(ctx, client, owner, repo, m.GetNumber())
+		if err != nil {
+			return err
+		}
+		if err := s.saveMilestoneItems(owner, repo, m.GetNumber(), items); err != nil {
+			return err
+		}
+	}
+
+	slog.Debug("milestones done", "org", owner, "repo", repo, "count", len(milestones))
+
+	// project boards need the read:project scope, which older tokens lack
+	if err := s.importProjectItems(ctx, client, owner, repo); err != nil {
+		slog.Warn("skipping project boards", "org", owner, "repo", repo, "error", err)
+	}
+
+	return nil
+}
+
+// ImportAllMilestones imports milestones for every previously imported repo.
+func (s *Store) ImportAllMilestones(ctx context.Context, token string) error {
+	list, err := s.GetAllOrgRepos()
+	if err != nil {
+		return fmt.Errorf("error getting org/repo list: %w", err)
+	}
+
+	for _, r := range list {
+		if err := s.ImportMilestones(ctx, token, r.Org, r.Repo); err != nil {
+			slog.Error("milestones failed", "org", r.Org, "repo", r.Repo, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func listMilestones(ctx context.Context, client *github.Client, owner, repo string) ([]*github.Milestone, error) {
+	opt := &github.MilestoneListOptions{
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: pageSizeDefault, Page: 1},
+	}
+
+	list := make([]*github.Milestone, 0)
+	for {
+		items, resp, err := client.Issues.ListMilestones(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("error listing milestones %s/%s: %w", owner, repo, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			net.PrintHTTPResponse(resp.Response)
+			return nil, fmt.Errorf("error listing milestones, rate: %s, status: %d", ghutil.RateInfo(&resp.Rate), resp.StatusCode)
+		}
+		if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
+			return nil, err
+		}
+
+		list = append(list, items...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return list, nil
+}
+
+func listMilestoneItems(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*data.MilestoneItem, error) {
+	opt := &github.IssueListByRepoOptions{
+		Milestone:   strconv.Itoa(number),
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: pageSizeDefault, Page: 1},
+	}
+
+	list := make([]*data.MilestoneItem, 0)
+	for {
+		items, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("error listing milestone items %s/%s#%d: %w", owner, repo, number, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			net.PrintHTTPResponse(resp.Response)
+			return nil, fmt.Errorf("error listing milestone items, rate: %s, status: %d", ghutil.RateInfo(&resp.Rate), resp.StatusCode)
+		}
+		if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
+			return nil, err
+		}
+
+		for _, it := range items {
+			list = append(list, mapMilestoneItem(number, it))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.ListOptions.Page = resp.NextPage
+	}
+
+	return list, nil
+}
+
+// mapMilestoneItem maps an issue or PR from the issues list to a milestone
+// item.
+func mapMilestoneItem(milestone int, it *github.Issue) *data.MilestoneItem {
+	itemType := data.EventTypeIssue
+	if it.IsPullRequest() {
+		itemType = data.EventTypePR
+	}
+	item := &data.MilestoneItem{
+		Milestone: milestone,
+		Number:    it.GetNumber(),
+		ItemType:  itemType,
+		ClosedAt:  timestampStr(it.ClosedAt),
+	}
+	if created := timestampStr(it.CreatedAt); created != nil {
+		item.CreatedAt = *created
+	}
+	return item
+}
+
+func (s *Store) saveMilestone(owner, repo string, m *github.Milestone) error {
+	var created string
+	if c := timestampStr(m.CreatedAt); c != nil {
+		created = *c
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	if _, err := s.db.Exec(upsertMilestoneSQL,
+		owner, repo, m.GetNumber(), m.GetTitle(), m.GetState(), m.GetHTMLURL(),
+		timestampStr(m.DueOn), created, timestampStr(m.ClosedAt),
+		m.GetOpenIssues(), m.GetClosedIssues(), now); err != nil {
+		return fmt.Errorf("error inserting milestone %s/%s#%d: %w", owner, repo, m.GetNumber(), err)
+	}
+	return nil
+}
+
+// saveMilestoneItems replaces the items of a milestone so items moved out
+// of it are dropped.
+func (s *Store) saveMilestoneItems(owner, repo string, number int, items []*data.MilestoneItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting milestone item tx: %w", err)
+	}
+
+	if _, err := tx.Exec(deleteMilestoneItemsSQL, owner, repo, number); err != nil {
+		rollbackTransaction(tx)
+		return fmt.Errorf("error clearing milestone items %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	for _, it := range items {
+		if _, err := tx.Exec(upsertMilestoneItemSQL,
+			owner, repo, it.Milestone, it.Number, it.ItemType, it.CreatedAt, it.ClosedAt); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting milestone item %s/%s#%d: %w", owner, repo, it.Number, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing milestone item tx: %w", err)
+	}
+	return nil
+}
+
+func parseDay(s string) (time.Time, bool) {
+	if len(s) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", s[:10])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// classifyMilestone sets the completion, close rate, projected completion
+// and status of a milestone. The close rate is measured over the last
+// data.MilestoneRateWindowDays, or over the milestone's life when it is
+// younger than that.
+func classifyMilestone(p *data.MilestoneProgress, recentClosed int, now time.Time) {
+	total := p.OpenIssues + p.ClosedIssues
+	if total > 0 {
+		p.PercentComplete = float64(p.ClosedIssues) / float64(total) * 100
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	if created, ok := parseDay(p.CreatedAt); ok && today.Sub(created).Hours()/24 < float64(data.MilestoneRateWindowDays) {
+		age := math.Max(1, today.Sub(created).Hours()/24)
+		p.ClosedPerDay = float64(p.ClosedIssues) / age
+	} else {
+		p.ClosedPerDay = float64(recentClosed) / float64(data.MilestoneRateWindowDays)
+	}
+
+	if p.OpenIssues > 0 && p.ClosedPerDay > 0 {
+		days := int(math.Ceil(float64(p.OpenIssues) / p.ClosedPerDay))
+		p.ProjectedOn = today.AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	var due time.Time
+	hasDue := false
+	if p.DueOn != nil {
+		due, hasDue = parseDay(*p.DueOn)
+	}
+
+	switch {
+	case p.State == "closed" || (p.OpenIssues == 0 && p.ClosedIssues > 0):
+		p.Status = data.MilestoneStatusComplete
+		p.ProjectedOn = ""
+	case !hasDue:
+		p.Status = data.MilestoneStatusNoDueDate
+	case due.Before(today):
+		p.Status = data.MilestoneStatusOverdue
+	case p.OpenIssues == 0:
+		p.Status = data.MilestoneStatusOnTrack
+	case p.ProjectedOn != "" && p.ProjectedOn <= due.Format("2006-01-02"):
+		p.Status = data.MilestoneStatusOnTrack
+	default:
+		p.Status = data.MilestoneStatusAtRisk
+	}
+}
+
+// GetMilestones returns open milestones created before the end of the range
+// and those closed in it with their progress and on-track classification.
+// Progress is current, as only the latest issue counts are kept, so the
+// granularity and the developer filters do not apply.
+func (s *Store) GetMilestones(f *data.InsightsFilter) ([]*data.MilestoneProgress, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	until := br.until()
+
+	now := time.Now().UTC()
+	rateSince := now.AddDate(0, 0, -data.MilestoneRateWindowDays).Format("2006-01-02")
+
+	rows, err := s.db.Query(selectMilestonesSQL, rateSince, f.Org, f.Repo, until, br.since(), until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query milestones: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.MilestoneProgress, 0)
+	for rows.Next() {
+		p := &data.MilestoneProgress{}
+		var recentClosed int
+		if err := rows.Scan(&p.Org, &p.Repo, &p.Number, &p.Title, &p.State, &p.URL, &p.DueOn,
+			&p.CreatedAt, &p.ClosedAt, &p.OpenIssues, &p.ClosedIssues, &recentClosed); err != nil {
+			return nil, fmt.Errorf("failed to scan milestone row: %w", err)
+		}
+		classifyMilestone(p, recentClosed, now)
+		list = append(list, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetMilestoneBurnup returns the daily scope and closed item counts of a
+// milestone from its creation to its close or today. Items count toward
+// the scope from their creation, as the time they were added to the
+// milestone is not tracked. Returns nil if the milestone does not exist.
+func (s *Store) GetMilestoneBurnup(org, repo string, number int) (*data.MilestoneBurnup, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	m := &data.Milestone{}
+	if err := s.db.QueryRow(selectMilestoneSQL, org, repo, number).Scan(&m.Org, &m.Repo, &m.Number,
+		&m.Title, &m.State, &m.URL, &m.DueOn, &m.CreatedAt, &m.ClosedAt,
+		&m.OpenIssues, &m.ClosedIssues); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to select milestone %s/%s#%d: %w", org, repo, number, err)
+	}
+
+	rows, err := s.db.Query(selectMilestoneItemsSQL, org, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query milestone items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*data.MilestoneItem, 0)
+	for rows.Next() {
+		it := &data.MilestoneItem{}
+		if err := rows.Scan(&it.Milestone, &it.Number, &it.ItemType, &it.CreatedAt, &it.ClosedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan milestone item row: %w", err)
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return buildBurnup(m, items, time.Now().UTC()), nil
+}
+
+// buildBurnup builds the daily burn-up series of a milestone.
+func buildBurnup(m *data.Milestone, items []*data.MilestoneItem, now time.Time) *data.MilestoneBurnup {
+	res := &data.MilestoneBurnup{
+		Org:    m.Org,
+		Repo:   m.Repo,
+		Number: m.Number,
+		Title:  m.Title,
+		Dates:  make([]string, 0),
+		Scope:  make([]int, 0),
+		Done:   make([]int, 0),
+	}
+
+	end := now.UTC().Truncate(24 * time.Hour)
+	if m.ClosedAt != nil {
+		if closed, ok := parseDay(*m.ClosedAt); ok && closed.Before(end) {
+			end = closed
+		}
+	}
+	start, ok := parseDay(m.CreatedAt)
+	if !ok || start.After(end) {
+		start = end
+	}
+	if end.Sub(start).Hours()/24 > milestoneBurnupMaxDays {
+		start = end.AddDate(0, 0, -milestoneBurnupMaxDays)
+	}
+
+	var due time.Time
+	hasDue := false
+	if m.DueOn != nil {
+		due, hasDue = parseDay(*m.DueOn)
+		res.DueOn = due.Format("2006-01-02")
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		var scope, done int
+		for _, it := range items {
+			if it.CreatedAt != "" && it.CreatedAt[:10] > key {
+				continue
+			}
+			scope++
+			if it.ClosedAt != nil && len(*it.ClosedAt) >= 10 && (*it.ClosedAt)[:10] <= key {
+				done++
+			}
+		}
+		res.Dates = append(res.Dates, key)
+		res.Scope = append(res.Scope, scope)
+		res.Done = append(res.Done, done)
+	}
+
+	if hasDue && due.After(start) && len(res.Scope) > 0 {
+		target := float64(res.Scope[len(res.Scope)-1])
+		span := due.Sub(start).Hours() / 24
+		res.Ideal = make([]float64, len(res.Dates))
+		for i := range res.Dates {
+			res.Ideal[i] = math.Min(target, target*float64(i)/span)
+		}
+	}
+
+	return res
+}
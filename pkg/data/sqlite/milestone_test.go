@@ -0,0 +1,279 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertMilestoneTestData(t *testing.T, store *Store) {
+	t.Helper()
+
+	now := time.Now().UTC()
+	day := func(d int) string { return now.AddDate(0, 0, d).Format(time.RFC3339) }
+
+	// milestone 1: open, due in 30 days, 4 of 6 closed, 4 closed recently
+	// milestone 2: open, due 10 days ago, 1 of 3 closed
+	// milestone 3: closed 200 days ago, outside the window
+	_, err := store.db.Exec(`INSERT INTO milestone (org, repo, number, title, state, url, due_on, created_at, closed_at, open_issues, closed_issues, updated_at) VALUES
+		('org1', 'repo1', 1, 'v1.0', 'open', 'http://m/1', ?, ?, NULL, 2, 4, ?),
+		('org1', 'repo1', 2, 'v0.9', 'open', 'http://m/2', ?, ?, NULL, 2, 1, ?),
+		('org1', 'repo2', 3, 'v0.1', 'closed', 'http://m/3', NULL, ?, ?, 0, 1, ?)`,
+		day(30), day(-60), day(0),
+		day(-10), day(-90), day(0),
+		day(-300), day(-200), day(0))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO milestone_item (org, repo, milestone, number, item_type, created_at, closed_at) VALUES
+		('org1', 'repo1', 1, 10, 'issue', ?, ?),
+		('org1', 'repo1', 1, 11, 'issue', ?, ?),
+		('org1', 'repo1', 1, 12, 'pr', ?, ?),
+		('org1', 'repo1', 1, 13, 'pr', ?, ?),
+		('org1', 'repo1', 1, 14, 'issue', ?, NULL),
+		('org1', 'repo1', 1, 15, 'issue', ?, NULL),
+		('org1', 'repo1', 2, 20, 'issue', ?, ?)`,
+		day(-60), day(-20),
+		day(-60), day(-10),
+		day(-30), day(-5),
+		day(-30), day(-1),
+		day(-60),
+		day(-2),
+		day(-90), day(-50))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO project_item (org, repo, project, project_title, item_id, number, item_type, status, updated_at) VALUES
+		('org1', 'repo1', 1, 'Roadmap', 'PVTI_1', 10, 'issue', 'Done', ?),
+		('org1', 'repo1', 1, 'Roadmap', 'PVTI_2', 14, 'issue', 'In Progress', ?),
+		('org1', 'repo1', 1, 'Roadmap', 'PVTI_3', 15, 'issue', 'In Progress', ?),
+		('org1', 'repo1', 1, 'Roadmap', 'PVTI_4', NULL, 'draft_issue', '', ?),
+		('org1', 'repo2', 2, 'Triage', 'PVTI_5', 3, 'issue', 'Todo', ?)`,
+		day(0), day(0), day(0), day(0), day(0))
+	require.NoError(t, err)
+}
+
+func TestMapMilestoneItem(t *testing.T) {
+	created := github.Timestamp{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	it := mapMilestoneItem(3, &github.Issue{Number: github.Ptr(7), CreatedAt: &created})
+	assert.Equal(t, 3, it.Milestone)
+	assert.Equal(t, 7, it.Number)
+	assert.Equal(t, data.EventTypeIssue, it.ItemType)
+	assert.Equal(t, "2025-01-02T03:04:05Z", it.CreatedAt)
+	assert.Nil(t, it.ClosedAt)
+
+	it = mapMilestoneItem(3, &github.Issue{Number: github.Ptr(8), PullRequestLinks: &github.PullRequestLinks{}})
+	assert.Equal(t, data.EventTypePR, it.ItemType)
+}
+
+func TestMapProjectItem(t *testing.T) {
+	p := &projectNode{Number: 4, Title: "Roadmap"}
+
+	n := &projectItemNode{ID: "PVTI_1", Type: "ISSUE"}
+	n.FieldValueByName = &struct {
+		Name string `json:"name"`
+	}{Name: "Todo"}
+	n.Content = &struct {
+		Number     int `json:"number"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	}{Number: 9}
+	n.Content.Repository.NameWithOwner = "Org1/Repo1"
+
+	item := mapProjectItem("org1", "repo1", p, n)
+	require.NotNil(t, item)
+	assert.Equal(t, 4, item.Project)
+	assert.Equal(t, data.ProjectItemIssue, item.ItemType)
+	assert.Equal(t, "Todo", item.Status)
+	assert.Equal(t, 9, *item.Number)
+
+	// items of other repos are skipped
+	assert.Nil(t, mapProjectItem("org1", "repo2", p, n))
+
+	// draft issues have no content
+	item = mapProjectItem("org1", "repo1", p, &projectItemNode{ID: "PVTI_2", Type: "DRAFT_ISSUE"})
+	require.NotNil(t, item)
+	assert.Equal(t, data.ProjectItemDraftIssue, item.ItemType)
+	assert.Nil(t, item.Number)
+	assert.Empty(t, item.Status)
+
+	assert.Nil(t, mapProjectItem("org1", "repo1", p, &projectItemNode{ID: "PVTI_3", Type: "REDACTED"}))
+}
+
+func TestClassifyMilestone(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		m            data.Milestone
+		recentClosed int
+		status       string
+		projected    string
+	}{
+		{
+			name:         "on track at recent rate",
+			m:            data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", DueOn: ptr("2025-06-30T00:00:00Z"), OpenIssues: 7, ClosedIssues: 3},
+			recentClosed: 14,
+			status:       data.MilestoneStatusOnTrack,
+			projected:    "2025-06-15",
+		},
+		{
+			name:         "at risk at recent rate",
+			m:            data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", DueOn: ptr("2025-06-10T00:00:00Z"), OpenIssues: 7, ClosedIssues: 3},
+			recentClosed: 14,
+			status:       data.MilestoneStatusAtRisk,
+			projected:    "2025-06-15",
+		},
+		{
+			name:   "at risk without recent closes",
+			m:      data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", DueOn: ptr("2025-12-31T00:00:00Z"), OpenIssues: 1, ClosedIssues: 3},
+			status: data.MilestoneStatusAtRisk,
+		},
+		{
+			name:   "young milestone uses lifetime rate",
+			m:      data.Milestone{State: "open", CreatedAt: "2025-05-22T00:00:00Z", DueOn: ptr("2025-06-30T00:00:00Z"), OpenIssues: 5, ClosedIssues: 10},
+			status: data.MilestoneStatusOnTrack,
+			// 10 closed in 10 days
+			projected: "2025-06-06",
+		},
+		{
+			name:   "overdue",
+			m:      data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", DueOn: ptr("2025-05-01T00:00:00Z"), OpenIssues: 1},
+			status: data.MilestoneStatusOverdue,
+		},
+		{
+			name:   "no due date",
+			m:      data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", OpenIssues: 1},
+			status: data.MilestoneStatusNoDueDate,
+		},
+		{
+			name:   "all items closed",
+			m:      data.Milestone{State: "open", CreatedAt: "2025-01-01T00:00:00Z", DueOn: ptr("2025-05-01T00:00:00Z"), ClosedIssues: 4},
+			status: data.MilestoneStatusComplete,
+		},
+		{
+			name:   "closed",
+			m:      data.Milestone{State: "closed", CreatedAt: "2025-01-01T00:00:00Z", OpenIssues: 2, ClosedIssues: 4},
+			status: data.MilestoneStatusComplete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &data.MilestoneProgress{Milestone: tt.m}
+			classifyMilestone(p, tt.recentClosed, now)
+			assert.Equal(t, tt.status, p.Status)
+			assert.Equal(t, tt.projected, p.ProjectedOn)
+		})
+	}
+}
+
+func TestGetMilestones_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetMilestones(&data.InsightsFilter{Months: 6})
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetMilestones_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	list, err := store.GetMilestones(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestGetMilestones_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertMilestoneTestData(t, store)
+
+	list, err := store.GetMilestones(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	// ordered by due date
+	assert.Equal(t, 2, list[0].Number)
+	assert.Equal(t, data.MilestoneStatusOverdue, list[0].Status)
+
+	assert.Equal(t, 1, list[1].Number)
+	assert.InDelta(t, 66.67, list[1].PercentComplete, 0.01)
+	assert.InDelta(t, 4.0/28.0, list[1].ClosedPerDay, 0.001)
+	assert.Equal(t, data.MilestoneStatusOnTrack, list[1].Status)
+
+	list, err = store.GetMilestones(&data.InsightsFilter{Months: 12})
+	require.NoError(t, err)
+	assert.Len(t, list, 3)
+
+	repo := "repo2"
+	list, err = store.GetMilestones(&data.InsightsFilter{Repo: &repo, Months: 12})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, data.MilestoneStatusComplete, list[0].Status)
+}
+
+func TestGetMilestoneBurnup_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetMilestoneBurnup("org1", "repo1", 1)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetMilestoneBurnup_NotFound(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetMilestoneBurnup("org1", "repo1", 1)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestGetMilestoneBurnup_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertMilestoneTestData(t, store)
+
+	res, err := store.GetMilestoneBurnup("org1", "repo1", 1)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, "v1.0", res.Title)
+	require.Len(t, res.Dates, 61)
+	assert.Equal(t, 3, res.Scope[0])
+	assert.Equal(t, 0, res.Done[0])
+
+	last := len(res.Dates) - 1
+	assert.Equal(t, 6, res.Scope[last])
+	assert.Equal(t, 4, res.Done[last])
+
+	// the ideal path reaches the scope on the due date, 90 days in
+	require.Len(t, res.Ideal, 61)
+	assert.InDelta(t, 0.0, res.Ideal[0], 0.001)
+	assert.InDelta(t, 6.0*60/90, res.Ideal[last], 0.001)
+}
+
+func TestGetProjectStatus_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetProjectStatus(nil, nil)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetProjectStatus_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertMilestoneTestData(t, store)
+
+	list, err := store.GetProjectStatus(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	p := list[0]
+	assert.Equal(t, "Roadmap", p.Title)
+	assert.Equal(t, []string{"", "Done", "In Progress"}, p.Statuses)
+	assert.Equal(t, []int{1, 1, 2}, p.Counts)
+	assert.Equal(t, 4, p.Total)
+
+	repo := "repo2"
+	list, err = store.GetProjectStatus(nil, &repo)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Triage", list[0].Title)
+}
@@ -24,7 +24,9 @@ const (
 			AND e.org = COALESCE(?, e.org)
 			AND e.repo = COALESCE(?, e.repo)
 			AND d.entity NOT IN (%s)
+			` + botExcludeSQL + `
 			` + forkExcludeSQL + `
+			` + discussionExcludeSQL + `
 			GROUP BY d.entity
 		) dt
 		ORDER BY 2 DESC
@@ -44,8 +46,9 @@ const (
 			AND e.org = COALESCE(?, e.org)
 			AND e.repo = COALESCE(?, e.repo)
 			AND d.username NOT IN (%s)
-			AND d.username NOT LIKE '%%[bot]'
+			` + botExcludeSQL + `
 			` + forkExcludeSQL + `
+			` + discussionExcludeSQL + `
 			GROUP BY d.username
 		) dt
 		ORDER BY 2 DESC
@@ -67,9 +70,10 @@ const (
 		WHERE d.username LIKE ?
 		  AND e.org = COALESCE(?, e.org)
 		  AND e.repo = COALESCE(?, e.repo)
-		  AND d.username NOT LIKE '%[bot]'
+		  ` + botExcludeSQL + `
 		  AND e.date >= ?
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 		ORDER BY d.username
 		LIMIT ?
 	`
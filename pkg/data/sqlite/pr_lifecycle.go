@@ -0,0 +1,215 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// updateReadyForReviewSQL copies the first ready_for_review timeline entry
+	// of each PR onto its event rows.
+	updateReadyForReviewSQL = `UPDATE event
+		SET ready_at = (
+			SELECT MIN(t.created_at) FROM timeline_event t
+			WHERE t.org = event.org AND t.repo = event.repo AND t.number = event.number
+			  AND t.item_type = 'pr'
+			  AND t.kind = 'ready_for_review'
+		)
+		WHERE type = 'pr'
+		  AND org = ?
+		  AND repo = ?
+		  AND ready_at IS NULL
+		  AND EXISTS (
+			SELECT 1 FROM timeline_event t
+			WHERE t.org = event.org AND t.repo = event.repo AND t.number = event.number
+			  AND t.item_type = 'pr'
+			  AND t.kind = 'ready_for_review'
+		  )
+	`
+
+	// selectPRLifecycleSQL classifies each PR opened in the window as merged,
+	// closed without merge, stale (open and inactive for the given days) or
+	// open, and flags PRs that are the author's first in the repo. PR rows are
+	// deduplicated by number since a PR updated on several days is stored
+	// once per day.
+	selectPRLifecycleSQL = `WITH prs AS (
+		SELECT e.org, e.repo, e.number, e.username,
+			MIN(e.created_at) AS created_at,
+			MAX(e.merged_at) AS merged_at,
+			MAX(e.closed_at) AS closed_at,
+			MAX(e.date) AS last_active,
+			MAX(IFNULL(e.draft, 0)) AS draft,
+			MIN(e.ready_at) AS ready_at
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type = 'pr'
+		  AND e.number IS NOT NULL
+		  AND e.created_at IS NOT NULL
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND e.created_at >= ?
+		  AND e.created_at < ?
+		  ` + developerFilterSQL + `
+		GROUP BY e.org, e.repo, e.number, e.username
+	),
+	firsts AS (
+		SELECT f.org, f.repo, f.username, MIN(f.created_at) AS first_pr
+		FROM event f
+		WHERE f.type = 'pr'
+		  AND f.created_at IS NOT NULL
+		  AND f.org = COALESCE(?, f.org)
+		  AND f.repo = COALESCE(?, f.repo)
+		GROUP BY f.org, f.repo, f.username
+	),
+	outcomes AS (
+		SELECT
+			substr(p.created_at, 1, 7) AS month,
+			CASE WHEN p.created_at = fi.first_pr THEN 1 ELSE 0 END AS first_time,
+			CASE WHEN p.merged_at IS NOT NULL THEN 1 ELSE 0 END AS merged,
+			CASE WHEN p.merged_at IS NULL AND p.closed_at IS NOT NULL THEN 1 ELSE 0 END AS closed,
+			CASE WHEN p.merged_at IS NULL AND p.closed_at IS NULL
+				AND julianday('now') - julianday(p.last_active) > ? THEN 1 ELSE 0 END AS stale,
+			CASE WHEN p.draft = 1 OR p.ready_at IS NOT NULL THEN 1 ELSE 0 END AS drafted,
+			CASE WHEN p.ready_at IS NOT NULL
+				THEN (julianday(p.ready_at) - julianday(p.created_at)) * 24 END AS draft_hours
+		FROM prs p
+		JOIN firsts fi ON fi.org = p.org AND fi.repo = p.repo AND fi.username = p.username
+	)
+	SELECT
+		month,
+		first_time,
+		COUNT(*) AS opened,
+		SUM(merged) AS merged,
+		SUM(closed) AS closed,
+		SUM(stale) AS stale,
+		SUM(drafted) AS drafted,
+		COUNT(draft_hours) AS ready,
+		COALESCE(SUM(draft_hours), 0) AS draft_hours
+	FROM outcomes
+	GROUP BY month, first_time
+	ORDER BY month, first_time
+	`
+)
+
+// updateReadyForReview records when PRs opened as drafts were first marked
+// ready for review, based on the imported timeline.
+func (e *eventImporter) updateReadyForReview(ctx context.Context) error {
+	if _, err := e.store.db.ExecContext(ctx, updateReadyForReviewSQL, e.owner, e.repo); err != nil {
+		return fmt.Errorf("error updating ready for review times: %w", err)
+	}
+	return nil
+}
+
+// GetPRLifecycle returns monthly PR outcomes (merged, closed without merge,
+// stale), time spent in draft, and outcome totals for first-time versus
+// returning authors. Open PRs without activity for staleDays count as stale.
+func (s *Store) GetPRLifecycle(f *data.InsightsFilter, staleDays int) (*data.PRLifecycleSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	if staleDays <= 0 {
+		staleDays = data.PRStaleDaysDefault
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectPRLifecycleSQL),
+		f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort,
+		f.Org, f.Repo, staleDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PR lifecycle: %w", err)
+	}
+	defer rows.Close()
+
+	sr := &data.PRLifecycleSeries{
+		Months:        make([]string, 0),
+		Opened:        make([]int, 0),
+		Merged:        make([]int, 0),
+		Closed:        make([]int, 0),
+		Stale:         make([]int, 0),
+		MergeRate:     make([]float64, 0),
+		AbandonRate:   make([]float64, 0),
+		Drafts:        make([]int, 0),
+		AvgDraftHours: make([]float64, 0),
+		StaleDays:     staleDays,
+		FirstTime:     &data.PROutcomes{},
+		Returning:     &data.PROutcomes{},
+	}
+
+	// rows come per month and author group; ready and draft hours are summed
+	// so the monthly average weights both groups
+	var ready []int
+	var draftHours []float64
+	for rows.Next() {
+		var month string
+		var firstTime, opened, merged, closed, stale, drafted, readyCnt int
+		var hours float64
+		if err := rows.Scan(&month, &firstTime, &opened, &merged, &closed, &stale, &drafted, &readyCnt, &hours); err != nil {
+			return nil, fmt.Errorf("failed to scan PR lifecycle row: %w", err)
+		}
+
+		i := len(sr.Months) - 1
+		if i < 0 || sr.Months[i] != month {
+			sr.Months = append(sr.Months, month)
+			sr.Opened = append(sr.Opened, 0)
+			sr.Merged = append(sr.Merged, 0)
+			sr.Closed = append(sr.Closed, 0)
+			sr.Stale = append(sr.Stale, 0)
+			sr.Drafts = append(sr.Drafts, 0)
+			ready = append(ready, 0)
+			draftHours = append(draftHours, 0)
+			i++
+		}
+		sr.Opened[i] += opened
+		sr.Merged[i] += merged
+		sr.Closed[i] += closed
+		sr.Stale[i] += stale
+		sr.Drafts[i] += drafted
+		ready[i] += readyCnt
+		draftHours[i] += hours
+
+		group := sr.Returning
+		if firstTime == 1 {
+			group = sr.FirstTime
+		}
+		group.Opened += opened
+		group.Merged += merged
+		group.Abandoned += closed + stale
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for i := range sr.Months {
+		var mergeRate, abandonRate, avgDraft float64
+		if sr.Opened[i] > 0 {
+			mergeRate = float64(sr.Merged[i]) / float64(sr.Opened[i]) * 100
+			abandonRate = float64(sr.Closed[i]+sr.Stale[i]) / float64(sr.Opened[i]) * 100
+		}
+		if ready[i] > 0 {
+			avgDraft = draftHours[i] / float64(ready[i])
+		}
+		sr.MergeRate = append(sr.MergeRate, mergeRate)
+		sr.AbandonRate = append(sr.AbandonRate, abandonRate)
+		sr.AvgDraftHours = append(sr.AvgDraftHours, avgDraft)
+	}
+
+	for _, g := range []*data.PROutcomes{sr.FirstTime, sr.Returning} {
+		if g.Opened > 0 {
+			g.MergeRate = float64(g.Merged) / float64(g.Opened) * 100
+			g.AbandonRate = float64(g.Abandoned) / float64(g.Opened) * 100
+		}
+	}
+
+	br.align(sr)
+	return sr, nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertPRLifecycleTestData(t *testing.T, store *Store) string {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob')`)
+	require.NoError(t, err)
+
+	base := time.Now().UTC().AddDate(0, -2, 0).Truncate(24 * time.Hour)
+	at := func(h int) string { return base.Add(time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	day := func(d int) string { return base.AddDate(0, 0, d).Format("2006-01-02") }
+
+	// alice: PR 1 (first, draft for 6h, merged), PR 2 (closed unmerged)
+	// bob: PR 3 (first, open and inactive since creation)
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at, closed_at, merged_at, draft) VALUES
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/1', '', '', 'merged', 1, ?, ?, ?, 0),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/2', '', '', 'closed', 2, ?, ?, NULL, 0),
+		('org1', 'repo1', 'bob', 'pr', ?, 'http://p/3', '', '', 'open', 3, ?, NULL, NULL, 1)`,
+		day(0), at(0), at(24), at(24),
+		day(1), at(2), at(26),
+		day(0), at(1))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at) VALUES
+		('org1', 'repo1', 1, 'pr', 'ready_for_review', 'alice', '', ?)`, at(6))
+	require.NoError(t, err)
+
+	return base.Format("2006-01")
+}
+
+func TestUpdateReadyForReview(t *testing.T) {
+	store := setupTestDB(t)
+	insertPRLifecycleTestData(t, store)
+
+	imp := &eventImporter{store: store, owner: "org1", repo: "repo1"}
+	require.NoError(t, imp.updateReadyForReview(context.Background()))
+
+	var ready int
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM event WHERE ready_at IS NOT NULL`).Scan(&ready))
+	assert.Equal(t, 1, ready)
+}
+
+func TestGetPRLifecycle_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetPRLifecycle(&data.InsightsFilter{Months: 6}, data.PRStaleDaysDefault)
+	assert.Error(t, err)
+}
+
+func TestGetPRLifecycle_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	series, err := store.GetPRLifecycle(&data.InsightsFilter{Months: 6}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, series.Months)
+	assert.Equal(t, data.PRStaleDaysDefault, series.StaleDays)
+	assert.Equal(t, 0, series.FirstTime.Opened)
+}
+
+func TestGetPRLifecycle_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	month := insertPRLifecycleTestData(t, store)
+
+	imp := &eventImporter{store: store, owner: "org1", repo: "repo1"}
+	require.NoError(t, imp.updateReadyForReview(context.Background()))
+
+	series, err := store.GetPRLifecycle(&data.InsightsFilter{Months: 6}, 30)
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, month, series.Months[0])
+	assert.Equal(t, 3, series.Opened[0])
+	assert.Equal(t, 1, series.Merged[0])
+	assert.Equal(t, 1, series.Closed[0])
+	assert.Equal(t, 1, series.Stale[0])
+	assert.InDelta(t, 100.0/3.0, series.MergeRate[0], 0.01)
+	assert.InDelta(t, 200.0/3.0, series.AbandonRate[0], 0.01)
+	// PR 1 was ready after 6h, PR 3 is still a draft
+	assert.Equal(t, 2, series.Drafts[0])
+	assert.InDelta(t, 6.0, series.AvgDraftHours[0], 0.01)
+
+	assert.Equal(t, 2, series.FirstTime.Opened)
+	assert.Equal(t, 1, series.FirstTime.Merged)
+	assert.Equal(t, 1, series.FirstTime.Abandoned)
+	assert.Equal(t, 1, series.Returning.Opened)
+	assert.Equal(t, 1, series.Returning.Abandoned)
+	assert.InDelta(t, 100.0, series.Returning.AbandonRate, 0.01)
+
+	// with a long inactivity threshold the open PR is not abandoned
+	series, err = store.GetPRLifecycle(&data.InsightsFilter{Months: 6}, 365)
+	require.NoError(t, err)
+	assert.Equal(t, 0, series.Stale[0])
+}
@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	selectProjectsGraphQL = `query($owner: String!, $name: String!) {
+		repository(owner: $owner, name: $name) {
+			projectsV2(first: 20) {
+				nodes { id number title closed }
+			}
+		}
+	}`
+
+	selectProjectItemsGraphQL = `query($id: ID!, $after: String) {
+		node(id: $id) {
+			... on ProjectV2 {
+				items(first: 100, after: $after) {
+					pageInfo { hasNextPage endCursor }
+					nodes {
+						id
+						type
+						fieldValueByName(name: "Status") {
+							... on ProjectV2ItemFieldSingleSelectValue { name }
+						}
+						content {
+							... on Issue { number repository { nameWithOwner } }
+							... on PullRequest { number repository { nameWithOwner } }
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	deleteProjectItemsSQL = `DELETE FROM project_item WHERE org = ? AND repo = ?`
+
+	insertProjectItemSQL = `INSERT OR REPLACE INTO project_item (
+			org, repo, project, project_title, item_id, number, item_type, status, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	selectProjectStatusSQL = `SELECT org, repo, project, project_title, status, COUNT(*) AS items
+		FROM project_item
+		WHERE org = COALESCE(?, org)
+		  AND repo = COALESCE(?, repo)
+		GROUP BY org, repo, project, project_title, status
+		ORDER BY org, repo, project, status
+	`
+)
+
+type projectNode struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Closed bool   `json:"closed"`
+}
+
+type projectsData struct {
+	Repository *struct {
+		ProjectsV2 struct {
+			Nodes []*projectNode `json:"nodes"`
+		} `json:"projectsV2"`
+	} `json:"repository"`
+}
+
+type projectItemNode struct {
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	FieldValueByName *struct {
+		Name string `json:"name"`
+	} `json:"fieldValueByName"`
+	Content *struct {
+		Number     int `json:"number"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"content"`
+}
+
+type projectItemsData struct {
+	Node *struct {
+		Items struct {
+			PageInfo graphQLPageInfo    `json:"pageInfo"`
+			Nodes    []*projectItemNode `json:"nodes"`
+		} `json:"items"`
+	} `json:"node"`
+}
+
+// mapProjectItem maps a board item to a project item of the given repo.
+// Items of other repos and redacted items are skipped.
+func mapProjectItem(owner, repo string, p *projectNode, n *projectItemNode) *data.ProjectItem {
+	item := &data.ProjectItem{
+		Project:      p.Number,
+		ProjectTitle: p.Title,
+		ItemID:       n.ID,
+	}
+	if n.FieldValueByName != nil {
+		item.Status = n.FieldValueByName.Name
+	}
+
+	switch n.Type {
+	case "DRAFT_ISSUE":
+		item.ItemType = data.ProjectItemDraftIssue
+		return item
+	case "ISSUE":
+		item.ItemType = data.ProjectItemIssue
+	case "PULL_REQUEST":
+		item.ItemType = data.ProjectItemPR
+	default:
+		return nil
+	}
+
+	if n.Content == nil || !strings.EqualFold(n.Content.Repository.NameWithOwner, owner+"/"+repo) {
+		return nil
+	}
+	number := n.Content.Number
+	item.Number = &number
+	return item
+}
+
+// importProjectItems replaces the board items of a repo with those on its
+// open GitHub Projects (v2) boards.
+func (s *Store) importProjectItems(ctx context.Context, client *github.Client, owner, repo string) error {
+	out, _, err := queryGraphQL[projectsData](ctx, client, selectProjectsGraphQL, map[string]any{
+		"owner": owner,
+		"name":  repo,
+	})
+	if err != nil {
+		return fmt.Errorf("error querying projects for %s/%s: %w", owner, repo, err)
+	}
+	if out.Repository == nil {
+		return nil
+	}
+
+	items := make([]*data.ProjectItem, 0)
+	for _, p := range out.Repository.ProjectsV2.Nodes {
+		if p == nil || p.Closed {
+			continue
+		}
+
+		vars := map[string]any{"id": p.ID, "after": nil}
+		for {
+			page, _, err := queryGraphQL[projectItemsData](ctx, client, selectProjectItemsGraphQL, vars)
+			if err != nil {
+				return fmt.Errorf("error querying project %d items: %w", p.Number, err)
+			}
+			if page.Node == nil {
+				break
+			}
+
+			for _, n := range page.Node.Items.Nodes {
+				if n == nil {
+					continue
+				}
+				if item := mapProjectItem(owner, repo, p, n); item != nil {
+					items = append(items, item)
+				}
+			}
+
+			if !page.Node.Items.PageInfo.HasNextPage {
+				break
+			}
+			vars["after"] = page.Node.Items.PageInfo.EndCursor
+		}
+	}
+
+	if err := s.saveProjectItems(owner, repo, items); err != nil {
+		return err
+	}
+
+	slog.Debug("project boards done", "org", owner, "repo", repo, "items", len(items))
+	return nil
+}
+
+func (s *Store) saveProjectItems(owner, repo string, items []*data.ProjectItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting project item tx: %w", err)
+	}
+
+	if _, err := tx.Exec(deleteProjectItemsSQL, owner, repo); err != nil {
+		rollbackTransaction(tx)
+		return fmt.Errorf("error clearing project items %s/%s: %w", owner, repo, err)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, it := range items {
+		if _, err := tx.Exec(insertProjectItemSQL, owner, repo, it.Project, it.ProjectTitle,
+			it.ItemID, it.Number, it.ItemType, it.Status, now); err != nil {
+			rollbackTransaction(tx)
+			return fmt.Errorf("error inserting project item %s: %w", it.ItemID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing project item tx: %w", err)
+	}
+	return nil
+}
+
+// GetProjectStatus returns the number of items per status on each project
+// board of the matching repos.
+func (s *Store) GetProjectStatus(org, repo *string) ([]*data.ProjectStatus, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectProjectStatusSQL, org, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project status: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.ProjectStatus, 0)
+	var cur *data.ProjectStatus
+	for rows.Next() {
+		var o, r, title, status string
+		var project, count int
+		if err := rows.Scan(&o, &r, &project, &title, &status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan project status row: %w", err)
+		}
+
+		if cur == nil || cur.Org != o || cur.Repo != r || cur.Project != project {
+			cur = &data.ProjectStatus{
+				Org:      o,
+				Repo:     r,
+				Project:  project,
+				Title:    title,
+				Statuses: make([]string, 0),
+				Counts:   make([]int, 0),
+			}
+			list = append(list, cur)
+		}
+		cur.Statuses = append(cur.Statuses, status)
+		cur.Counts = append(cur.Counts, count)
+		cur.Total += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
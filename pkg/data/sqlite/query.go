@@ -17,6 +17,7 @@ const (
 			date,
 			SUM(prs) as prs,
 			SUM(pr_review) as pr_review,
+			SUM(pr_review_comment) as pr_review_comment,
 			SUM(issues) as issues,
 			SUM(issue_comments) as issue_comments,
 			SUM(forks) as forks
@@ -32,6 +33,7 @@ const (
 				substr(dates.date, 0, 8) as date,
 				CASE WHEN e.type = ? THEN 1 ELSE 0 END as prs,
 				CASE WHEN e.type = ? THEN 1 ELSE 0 END as pr_review,
+				CASE WHEN e.type = ? THEN 1 ELSE 0 END as pr_review_comment,
 				CASE WHEN e.type = ? THEN 1 ELSE 0 END as issues,
 				CASE WHEN e.type = ? THEN 1 ELSE 0 END as issue_comments,
 				CASE WHEN e.type = ? THEN 1 ELSE 0 END as forks
@@ -41,7 +43,7 @@ const (
 			AND e.org = COALESCE(?, e.org)
 			AND e.repo = COALESCE(?, e.repo)
 			AND d.entity = COALESCE(?, d.entity)
-			` + botExcludeSQL + `
+			` + developerFilterSQL + `
 		) dt
 		GROUP BY date
 		ORDER BY 1
@@ -64,6 +66,7 @@ const (
 			e.deletions,
 			e.changed_files,
 			e.commits,
+			e.event_at,
 			d.username,
 			d.email,
 			d.full_name,
@@ -80,8 +83,10 @@ const (
 		AND e.username = COALESCE(?, e.username)
 		AND e.mentions LIKE COALESCE(?, e.mentions)
 		AND e.labels LIKE COALESCE(?, e.labels)
+		AND IFNULL((SELECT g.reactions FROM engagement g
+			WHERE g.org = e.org AND g.repo = e.repo AND g.number = e.number), 0) >= COALESCE(?, 0)
 		AND d.entity = COALESCE(?, d.entity)
-		` + botExcludeSQL + `
+		` + developerFilterSQL + `
 		ORDER BY 1 DESC, 2, 3
 		LIMIT ? OFFSET ?
 	`
@@ -107,7 +112,7 @@ func (s *Store) SearchEvents(q *data.EventSearchCriteria) ([]*data.EventDetails,
 	defer stmt.Close()
 
 	offset := (q.Page - 1) * q.PageSize
-	rows, err := stmt.Query(q.FromDate, q.ToDate, q.Type, q.Org, q.Repo, q.Username, optionalLike(q.Mention), optionalLike(q.Label), q.Entity, q.PageSize, offset)
+	rows, err := stmt.Query(q.FromDate, q.ToDate, q.Type, q.Org, q.Repo, q.Username, optionalLike(q.Mention), optionalLike(q.Label), q.MinReactions, q.Entity, q.IncludeBots, q.Cohort, q.PageSize, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute event search statement: %w", err)
 	}
@@ -123,7 +128,7 @@ func (s *Store) SearchEvents(q *data.EventSearchCriteria) ([]*data.EventDetails,
 		if err := rows.Scan(&e.Event.Org, &e.Event.Repo, &e.Event.Date, &e.Event.Type, &e.Event.URL,
 			&e.Event.Mentions, &e.Event.Labels,
 			&e.Event.State, &e.Event.Number, &e.Event.CreatedAt, &e.Event.ClosedAt, &e.Event.MergedAt,
-			&e.Event.Additions, &e.Event.Deletions, &e.Event.ChangedFiles, &e.Event.Commits,
+			&e.Event.Additions, &e.Event.Deletions, &e.Event.ChangedFiles, &e.Event.Commits, &e.Event.EventAt,
 			&e.Developer.Username, &e.Developer.Email, &e.Developer.FullName,
 			&e.Developer.AvatarURL, &e.Developer.ProfileURL, &e.Developer.Entity); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -151,7 +156,7 @@ func (s *Store) GetMinEventDate(org, repo *string) (string, error) {
 	return minDate, nil
 }
 
-func (s *Store) GetEventTypeSeries(org, repo, entity *string, months int) (*data.EventTypeSeries, error) {
+func (s *Store) GetEventTypeSeries(f *data.InsightsFilter) (*data.EventTypeSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
@@ -162,41 +167,43 @@ func (s *Store) GetEventTypeSeries(org, repo, entity *string, months int) (*data
 	}
 	defer stmt.Close()
 
-	since := sinceDate(months)
+	since := sinceDate(f.Months)
 	to := time.Now().UTC().Format("2006-01-02")
 
 	rows, err := stmt.Query(since, to,
-		data.EventTypePR, data.EventTypePRReview, data.EventTypeIssue, data.EventTypeIssueComment, data.EventTypeFork,
-		org, repo, entity)
+		data.EventTypePR, data.EventTypePRReview, data.EventTypePRReviewComment, data.EventTypeIssue, data.EventTypeIssueComment, data.EventTypeFork,
+		f.Org, f.Repo, f.Entity, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute series select statement: %w", err)
 	}
 	defer rows.Close()
 
 	series := &data.EventTypeSeries{
-		Dates:         make([]string, 0),
-		PRs:           make([]int, 0),
-		PRReviews:     make([]int, 0),
-		Issues:        make([]int, 0),
-		IssueComments: make([]int, 0),
-		Forks:         make([]int, 0),
-		Total:         make([]int, 0),
-		Trend:         make([]float32, 0),
+		Dates:            make([]string, 0),
+		PRs:              make([]int, 0),
+		PRReviews:        make([]int, 0),
+		PRReviewComments: make([]int, 0),
+		Issues:           make([]int, 0),
+		IssueComments:    make([]int, 0),
+		Forks:            make([]int, 0),
+		Total:            make([]int, 0),
+		Trend:            make([]float32, 0),
 	}
 
 	for rows.Next() {
 		var date string
-		var prs, prComments, issues, issueComments, forks int
-		if err := rows.Scan(&date, &prs, &prComments, &issues, &issueComments, &forks); err != nil {
+		var prs, prReviews, prComments, issues, issueComments, forks int
+		if err := rows.Scan(&date, &prs, &prReviews, &prComments, &issues, &issueComments, &forks); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		series.Dates = append(series.Dates, date)
 		series.PRs = append(series.PRs, prs)
-		series.PRReviews = append(series.PRReviews, prComments)
+		series.PRReviews = append(series.PRReviews, prReviews)
+		series.PRReviewComments = append(series.PRReviewComments, prComments)
 		series.Issues = append(series.Issues, issues)
 		series.IssueComments = append(series.IssueComments, issueComments)
 		series.Forks = append(series.Forks, forks)
-		series.Total = append(series.Total, prs+prComments+issues+issueComments+forks)
+		series.Total = append(series.Total, prs+prReviews+prComments+issues+issueComments+forks)
 	}
 
 	if err := rows.Err(); err != nil {
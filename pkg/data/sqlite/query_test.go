@@ -100,7 +100,7 @@ func TestSearchEvents_Pagination(t *testing.T) {
 
 func TestGetEventTypeSeries_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetEventTypeSeries(nil, nil, nil, 6)
+	_, err := s.GetEventTypeSeries(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -109,7 +109,7 @@ func TestGetEventTypeSeries(t *testing.T) {
 	seedTestData(t, store)
 	org := "testorg"
 	repo := "testrepo"
-	series, err := store.GetEventTypeSeries(&org, &repo, nil, 24)
+	series, err := store.GetEventTypeSeries(&data.InsightsFilter{Org: &org, Repo: &repo, Months: 24})
 	require.NoError(t, err)
 	assert.NotNil(t, series)
 	assert.NotEmpty(t, series.Dates)
@@ -46,6 +46,7 @@ const (
 		WHERE ra.org = COALESCE(?, ra.org)
 		  AND ra.repo = COALESCE(?, ra.repo)
 		  AND r.published_at >= ?
+		  AND r.published_at < ?
 		GROUP BY month
 		ORDER BY month
 	`
@@ -62,7 +63,7 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.merged_at >= ?
-		  ` + botExcludeSQL + `
+		  ` + developerFilterSQL + `
 		GROUP BY month
 		ORDER BY month
 	`
@@ -78,6 +79,7 @@ const (
 			WHERE r.org = COALESCE(?, r.org)
 			  AND r.repo = COALESCE(?, r.repo)
 			  AND r.published_at >= ?
+			  AND r.published_at < ?
 			ORDER BY r.published_at DESC
 			LIMIT 9
 		), top AS (
@@ -87,6 +89,7 @@ const (
 			WHERE ra.org = COALESCE(?, ra.org)
 			  AND ra.repo = COALESCE(?, ra.repo)
 			  AND r.published_at >= ?
+			  AND r.published_at < ?
 			GROUP BY ra.org, ra.repo, ra.tag
 			ORDER BY SUM(ra.download_count) DESC
 			LIMIT 1
@@ -226,14 +229,18 @@ func (s *Store) ImportAllReleases(ctx context.Context, token string) error {
 	return nil
 }
 
-func (s *Store) GetReleaseCadence(org, repo, entity *string, months int) (*data.ReleaseCadenceSeries, error) {
+func (s *Store) GetReleaseCadence(f *data.InsightsFilter) (*data.ReleaseCadenceSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
 
-	rows, err := s.db.Query(selectReleaseCadenceSQL, org, repo, since)
+	rows, err := s.db.Query(br.query(selectReleaseCadenceSQL), f.Org, f.Repo, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query release cadence: %w", err)
 	}
@@ -252,6 +259,10 @@ func (s *Store) GetReleaseCadence(org, repo, entity *string, months int) (*data.
 		if scanErr := rows.Scan(&month, &total, &stable); scanErr != nil {
 			return nil, fmt.Errorf("failed to scan release cadence row: %w", scanErr)
 		}
+		// releases after the range must not disable the merged PR fallback
+		if month == bucketOverflow {
+			continue
+		}
 		sr.Months = append(sr.Months, month)
 		sr.Total = append(sr.Total, total)
 		sr.Stable = append(sr.Stable, stable)
@@ -263,10 +274,11 @@ func (s *Store) GetReleaseCadence(org, repo, entity *string, months int) (*data.
 
 	if len(sr.Months) > 0 {
 		sr.Deployments = append(sr.Deployments, sr.Total...)
+		br.align(sr)
 		return sr, nil
 	}
 
-	fallbackRows, err := s.db.Query(selectMergedPRDeploymentsSQL, org, repo, entity, since)
+	fallbackRows, err := s.db.Query(br.query(selectMergedPRDeploymentsSQL), f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query merged PR deployments: %w", err)
 	}
@@ -286,17 +298,24 @@ func (s *Store) GetReleaseCadence(org, repo, entity *string, months int) (*data.
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetReleaseDownloads(org, repo *string, months int) (*data.ReleaseDownloadsSeries, error) { //nolint:dupl,nolintlint // different types and SQL than GetContainerActivity
+// GetReleaseDownloads returns the downloads of the release assets by the
+// period the release was published in. Releases are not attributed to
+// developers, so the entity, cohort and bot filters do not apply.
+func (s *Store) GetReleaseDownloads(f *data.InsightsFilter) (*data.ReleaseDownloadsSeries, error) { //nolint:dupl,nolintlint // different types and SQL than GetContainerActivity
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := s.db.Query(selectReleaseDownloadsSQL, org, repo, since)
+	rows, err := s.db.Query(br.query(selectReleaseDownloadsSQL), f.Org, f.Repo, br.since(), br.until())
 	if err != nil {
 		return nil, fmt.Errorf("failed to query release downloads: %w", err)
 	}
@@ -321,17 +340,26 @@ func (s *Store) GetReleaseDownloads(org, repo *string, months int) (*data.Releas
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	br.align(sr)
 	return sr, nil
 }
 
-func (s *Store) GetReleaseDownloadsByTag(org, repo *string, months int) (*data.ReleaseDownloadsByTagSeries, error) {
+// GetReleaseDownloadsByTag returns the downloads of the most recent
+// releases published in the range and of its most downloaded one. The
+// series is keyed by tag, so the granularity does not apply; neither do
+// the developer filters.
+func (s *Store) GetReleaseDownloadsByTag(f *data.InsightsFilter) (*data.ReleaseDownloadsByTagSeries, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since, until := br.since(), br.until()
 
-	rows, err := s.db.Query(selectReleaseDownloadsByTagSQL, org, repo, since, org, repo, since)
+	rows, err := s.db.Query(selectReleaseDownloadsByTagSQL, f.Org, f.Repo, since, until, f.Org, f.Repo, since, until)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query release downloads by tag: %w", err)
 	}
@@ -3,20 +3,21 @@ package sqlite
 import (
 	"testing"
 
+	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetReleaseCadence_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetReleaseCadence(nil, nil, nil, 6)
+	series, err := store.GetReleaseCadence(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
 
 func TestGetReleaseCadence_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetReleaseCadence(nil, nil, nil, 6)
+	_, err := s.GetReleaseCadence(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -30,7 +31,7 @@ func TestGetReleaseCadence_WithData(t *testing.T) {
 		('org1', 'repo1', 'v1.1.0', 'Release 1.1', '2025-02-10T00:00:00Z', 0)`)
 	require.NoError(t, err)
 
-	series, err := store.GetReleaseCadence(nil, nil, nil, 24)
+	series, err := store.GetReleaseCadence(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 2)
 
@@ -55,7 +56,7 @@ func TestGetReleaseCadence_WithFilter(t *testing.T) {
 	require.NoError(t, err)
 
 	org := "org1"
-	series, err := store.GetReleaseCadence(&org, nil, nil, 24)
+	series, err := store.GetReleaseCadence(&data.InsightsFilter{Org: &org, Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 1, series.Total[0])
@@ -63,13 +64,13 @@ func TestGetReleaseCadence_WithFilter(t *testing.T) {
 
 func TestGetReleaseDownloads_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetReleaseDownloads(nil, nil, 6)
+	_, err := s.GetReleaseDownloads(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetReleaseDownloads_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetReleaseDownloads(nil, nil, 6)
+	series, err := store.GetReleaseDownloads(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Months)
 }
@@ -90,7 +91,7 @@ func TestGetReleaseDownloads_WithData(t *testing.T) {
 		('org1', 'repo1', 'v1.1.0', 'app_linux_amd64.tar.gz', 'application/gzip', 1000, 30)`)
 	require.NoError(t, err)
 
-	series, err := store.GetReleaseDownloads(nil, nil, 24)
+	series, err := store.GetReleaseDownloads(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 2)
 
@@ -117,7 +118,7 @@ func TestGetReleaseDownloads_WithFilter(t *testing.T) {
 	require.NoError(t, err)
 
 	org := "org1"
-	series, err := store.GetReleaseDownloads(&org, nil, 24)
+	series, err := store.GetReleaseDownloads(&data.InsightsFilter{Org: &org, Months: 24})
 	require.NoError(t, err)
 	require.Len(t, series.Months, 1)
 	assert.Equal(t, 100, series.Downloads[0])
@@ -125,13 +126,13 @@ func TestGetReleaseDownloads_WithFilter(t *testing.T) {
 
 func TestGetReleaseDownloadsByTag_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetReleaseDownloadsByTag(nil, nil, 6)
+	_, err := s.GetReleaseDownloadsByTag(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetReleaseDownloadsByTag_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	series, err := store.GetReleaseDownloadsByTag(nil, nil, 6)
+	series, err := store.GetReleaseDownloadsByTag(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, series.Tags)
 }
@@ -168,7 +169,7 @@ func TestGetReleaseDownloadsByTag_WithData(t *testing.T) {
 		('org1', 'repo1', 'v1.1.0', 'app.tar.gz', 'application/gzip', 100, 100)`)
 	require.NoError(t, err)
 
-	series, err := store.GetReleaseDownloadsByTag(nil, nil, 24)
+	series, err := store.GetReleaseDownloadsByTag(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 
 	// 9 recent (v0.3.0..v1.1.0) + top (v0.1.0) = 10
@@ -190,7 +191,7 @@ func TestGetReleaseCadence_WithDeployments(t *testing.T) {
 		VALUES ('org1', 'repo1', 'v1.0', 'v1.0', '2025-01-15T00:00:00Z', 0)`)
 	require.NoError(t, err)
 
-	series, err := store.GetReleaseCadence(nil, nil, nil, 24)
+	series, err := store.GetReleaseCadence(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.NotEmpty(t, series.Months)
 	assert.Equal(t, 1, series.Deployments[0])
@@ -210,7 +211,7 @@ func TestGetReleaseCadence_MergeFallback(t *testing.T) {
 
 	org := "org2"
 	repo := "repo2"
-	series, err := store.GetReleaseCadence(&org, &repo, nil, 24)
+	series, err := store.GetReleaseCadence(&data.InsightsFilter{Org: &org, Repo: &repo, Months: 24})
 	require.NoError(t, err)
 	require.NotEmpty(t, series.Months)
 	assert.Equal(t, 2, series.Deployments[0])
@@ -232,7 +233,7 @@ func TestGetReleaseDownloadsByTag_Dedup(t *testing.T) {
 		('org1', 'repo1', 'v1.2.0', 'app.tar.gz', 'application/gzip', 100, 20)`)
 	require.NoError(t, err)
 
-	series, err := store.GetReleaseDownloadsByTag(nil, nil, 24)
+	series, err := store.GetReleaseDownloadsByTag(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 
 	// Only 3 entries -- no duplicate for v1.1.0
@@ -49,9 +49,18 @@ const (
 			rm.language, rm.license, rm.archived,
 			rm.last_import_at
 		FROM repo_meta rm
-		LEFT JOIN event e ON rm.org = e.org AND rm.repo = e.repo AND e.date >= ?
+		LEFT JOIN (
+			SELECT e.org, e.repo, e.username, e.type
+			FROM event e
+			JOIN developer d ON e.username = d.username
+			WHERE e.date >= ?
+			  AND e.date < ?
+			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+			  ` + developerFilterSQL + `
+		) e ON rm.org = e.org AND rm.repo = e.repo
 		LEFT JOIN developer d ON e.username = d.username
 		WHERE rm.org = COALESCE(?, rm.org)
+		  AND rm.repo = COALESCE(?, rm.repo)
 		GROUP BY rm.org, rm.repo
 		ORDER BY rm.org, rm.repo
 	`
@@ -219,14 +228,20 @@ func (s *Store) GetRepoMetas(org, repo *string) ([]*data.RepoMeta, error) {
 	return list, nil
 }
 
-func (s *Store) GetRepoOverview(org *string, months int) ([]*data.RepoOverview, error) {
+// GetRepoOverview returns the metadata of each repo with its event and
+// contributor counts in the range. There is one row per repo, so the
+// granularity does not apply.
+func (s *Store) GetRepoOverview(f *data.InsightsFilter) ([]*data.RepoOverview, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := s.db.Query(selectRepoOverviewSQL, since, org)
+	rows, err := s.db.Query(selectRepoOverviewSQL, br.since(), br.until(), f.Entity, f.IncludeBots, f.Cohort, f.Org, f.Repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query repo overview: %w", err)
 	}
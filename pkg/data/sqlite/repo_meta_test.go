@@ -3,6 +3,7 @@ package sqlite
 import (
 	"testing"
 
+	"github.com/mchmarny/devpulse/pkg/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -54,14 +55,14 @@ func TestGetRepoMetas_WithFilter(t *testing.T) {
 
 func TestGetRepoOverview_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	list, err := store.GetRepoOverview(nil, 6)
+	list, err := store.GetRepoOverview(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, list)
 }
 
 func TestGetRepoOverview_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetRepoOverview(nil, 6)
+	_, err := s.GetRepoOverview(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
@@ -84,7 +85,7 @@ func TestGetRepoOverview_WithData(t *testing.T) {
 		('org1', 'repo1', 'user2', 'issue', '2026-03-02', '', '', '')`)
 	require.NoError(t, err)
 
-	list, err := store.GetRepoOverview(nil, 6)
+	list, err := store.GetRepoOverview(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 1)
 	assert.Equal(t, "org1", list[0].Org)
@@ -108,7 +109,7 @@ func TestGetRepoOverview_WithOrgFilter(t *testing.T) {
 	require.NoError(t, err)
 
 	org := "org1"
-	list, err := store.GetRepoOverview(&org, 6)
+	list, err := store.GetRepoOverview(&data.InsightsFilter{Org: &org, Months: 6})
 	require.NoError(t, err)
 	require.Len(t, list, 1)
 	assert.Equal(t, "org1", list[0].Org)
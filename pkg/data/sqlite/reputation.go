@@ -24,8 +24,9 @@ const (
 		FROM developer d
 		JOIN event e ON d.username = e.username
 		WHERE 1=1
-		  ` + botExcludeDSQL + `
+		  ` + botExcludeSQL + `
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 		  AND e.org = COALESCE(?, e.org)
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND (d.reputation_deep IS NULL OR d.reputation_deep = 0)
@@ -57,8 +58,9 @@ const (
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
 		  AND d.reputation IS NOT NULL
-		  ` + botExcludeDSQL + `
+		  ` + developerFilterSQL + `
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 		GROUP BY d.username
 		ORDER BY d.reputation ASC
 		LIMIT 10
@@ -73,8 +75,9 @@ const (
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
 		  AND e.date >= ?
-		  ` + botExcludeDSQL + `
+		  ` + developerFilterSQL + `
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 	`
 
 	selectDistinctOrgsSQL = `SELECT DISTINCT org FROM event`
@@ -83,8 +86,9 @@ const (
 		FROM developer d
 		JOIN event e ON d.username = e.username
 		WHERE d.reputation IS NOT NULL
-		  ` + botExcludeDSQL + `
+		  ` + botExcludeSQL + `
 		  ` + forkExcludeSQL + `
+		  ` + discussionExcludeSQL + `
 		  AND e.org = COALESCE(?, e.org)
 		  AND e.repo = COALESCE(?, e.repo)
 		  AND (d.reputation_deep IS NULL OR d.reputation_deep = 0
@@ -336,14 +340,14 @@ func (s *Store) ComputeDeepReputation(ctx context.Context, token, username strin
 	}, nil
 }
 
-func (s *Store) GetReputationDistribution(org, repo, entity *string, months int) (*data.ReputationDistribution, error) {
+func (s *Store) GetReputationDistribution(f *data.InsightsFilter) (*data.ReputationDistribution, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
-	since := sinceDate(months)
+	since := sinceDate(f.Months)
 
-	rows, err := s.db.Query(selectReputationSQL, org, repo, entity, since)
+	rows, err := s.db.Query(selectReputationSQL, f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query reputation distribution: %w", err)
 	}
@@ -368,7 +372,7 @@ func (s *Store) GetReputationDistribution(org, repo, entity *string, months int)
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	if err := s.db.QueryRow(selectReputationCountSQL, org, repo, entity, since).Scan(&d.Total, &d.Scored); err != nil {
+	if err := s.db.QueryRow(selectReputationCountSQL, f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort).Scan(&d.Total, &d.Scored); err != nil {
 		return nil, fmt.Errorf("failed to query reputation counts: %w", err)
 	}
 
@@ -13,13 +13,13 @@ import (
 
 func TestGetReputationDistribution_NilDB(t *testing.T) {
 	s := &Store{db: nil}
-	_, err := s.GetReputationDistribution(nil, nil, nil, 6)
+	_, err := s.GetReputationDistribution(&data.InsightsFilter{Months: 6})
 	assert.Error(t, err)
 }
 
 func TestGetReputationDistribution_EmptyDB(t *testing.T) {
 	store := setupTestDB(t)
-	dist, err := store.GetReputationDistribution(nil, nil, nil, 6)
+	dist, err := store.GetReputationDistribution(&data.InsightsFilter{Months: 6})
 	require.NoError(t, err)
 	assert.Empty(t, dist.Labels)
 	assert.Empty(t, dist.Data)
@@ -158,7 +158,7 @@ func TestGetReputationDistribution_WithData(t *testing.T) {
 		VALUES ('org1', 'repo1', 'noscorer', 'pr', '2025-01-10', 'http://example.com', '', '')`)
 	require.NoError(t, err)
 
-	dist, err := store.GetReputationDistribution(nil, nil, nil, 24)
+	dist, err := store.GetReputationDistribution(&data.InsightsFilter{Months: 24})
 	require.NoError(t, err)
 	require.Len(t, dist.Labels, 2)
 	// Ordered by reputation ASC (lowest first)
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// retentionGroupLimit is the number of largest entities kept as their
+	// own group when splitting cohorts by entity.
+	retentionGroupLimit = 6
+
+	// selectCohortRetentionSQL lists, for each contributor who first
+	// contributed in the range, the month and type of the first
+	// contribution, their entity, and every month they were active in
+	// through the end of the range. The first contribution is looked up
+	// over all imported history.
+	selectCohortRetentionSQL = `WITH events AS (
+		SELECT e.username, e.date, e.type, IFNULL(d.entity, '') AS entity
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  ` + developerFilterSQL + `
+		  ` + forkExcludeSQL + `
+		  ` + discussionFilterSQL + `
+	),
+	firsts AS (
+		SELECT username, date AS first_date, type AS first_type, entity,
+			ROW_NUMBER() OVER (PARTITION BY username ORDER BY date, type) AS rn
+		FROM events
+	),
+	active AS (
+		SELECT DISTINCT username, substr(date, 1, 7) AS month
+		FROM events
+		WHERE date < ?
+	)
+	SELECT substr(f.first_date, 1, 7) AS cohort, f.username, f.entity, f.first_type, a.month
+	FROM firsts f
+	JOIN active a ON a.username = f.username
+	WHERE f.rn = 1
+	  AND f.first_date >= ?
+	  AND f.first_date < ?
+	ORDER BY cohort, f.username, a.month
+	`
+)
+
+// retentionMember is a contributor in a retention cohort with the months
+// they were active in.
+type retentionMember struct {
+	cohort string
+	group  string
+	months []string
+}
+
+// retentionContributionType groups event types into the contribution type
+// of a first contribution: issue, PR or discussion.
+func retentionContributionType(eventType string) string {
+	switch eventType {
+	case data.EventTypePR, data.EventTypePRReview, data.EventTypePRReviewComment:
+		return data.EventTypePR
+	case data.EventTypeDiscussion, data.EventTypeDiscussionComment:
+		return data.EventTypeDiscussion
+	default:
+		return data.EventTypeIssue
+	}
+}
+
+// monthsBetween returns the whole months from one YYYY-MM month to another.
+func monthsBetween(from, to string) (int, error) {
+	f, err := time.Parse("2006-01", from)
+	if err != nil {
+		return 0, fmt.Errorf("invalid month %q: %w", from, err)
+	}
+	t, err := time.Parse("2006-01", to)
+	if err != nil {
+		return 0, fmt.Errorf("invalid month %q: %w", to, err)
+	}
+	return (t.Year()-f.Year())*12 + int(t.Month()) - int(f.Month()), nil
+}
+
+// GetCohortRetention returns the share of each monthly cohort of new
+// contributors active in every month since their first contribution,
+// optionally split by entity or by the type of the first contribution.
+// Cohorts are always monthly.
+func (s *Store) GetCohortRetention(f *data.InsightsFilter, split string) (*data.CohortRetention, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+	if !data.Contains(data.RetentionSplits, split) {
+		return nil, fmt.Errorf("invalid retention split: %s", split)
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(selectCohortRetentionSQL,
+		f.Org, f.Repo, f.Entity, f.IncludeBots, f.Cohort, f.IncludeDiscussions,
+		br.until(), br.since(), br.until())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cohort retention: %w", err)
+	}
+	defer rows.Close()
+
+	members := make(map[string]*retentionMember)
+	order := make([]string, 0)
+	for rows.Next() {
+		var cohort, username, entity, firstType, month string
+		if err := rows.Scan(&cohort, &username, &entity, &firstType, &month); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort retention row: %w", err)
+		}
+
+		m, ok := members[username]
+		if !ok {
+			m = &retentionMember{cohort: cohort}
+			switch split {
+			case data.RetentionSplitEntity:
+				m.group = entity
+			case data.RetentionSplitType:
+				m.group = retentionContributionType(firstType)
+			}
+			members[username] = m
+			order = append(order, username)
+		}
+		m.months = append(m.months, month)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	if split == data.RetentionSplitEntity {
+		groupLargestEntities(members)
+	}
+
+	end := br.end.Format("2006-01")
+
+	res := &data.CohortRetention{
+		Split: split,
+		Rows:  make([]*data.CohortRetentionRow, 0),
+	}
+	index := make(map[string]*data.CohortRetentionRow)
+	for _, username := range order {
+		m := members[username]
+		key := m.cohort + "|" + m.group
+		row, ok := index[key]
+		if !ok {
+			span, err := monthsBetween(m.cohort, end)
+			if err != nil {
+				return nil, err
+			}
+			row = &data.CohortRetentionRow{
+				Cohort:    m.cohort,
+				Group:     m.group,
+				Active:    make([]int, span+1),
+				Retention: make([]float64, span+1),
+			}
+			index[key] = row
+			res.Rows = append(res.Rows, row)
+			res.Months = max(res.Months, span+1)
+		}
+
+		row.Size++
+		for _, month := range m.months {
+			offset, err := monthsBetween(m.cohort, month)
+			if err != nil {
+				return nil, err
+			}
+			if offset >= 0 && offset < len(row.Active) {
+				row.Active[offset]++
+			}
+		}
+	}
+
+	for _, row := range res.Rows {
+		for i, n := range row.Active {
+			row.Retention[i] = float64(n) / float64(row.Size) * 100
+		}
+	}
+
+	sort.SliceStable(res.Rows, func(i, j int) bool {
+		if res.Rows[i].Cohort != res.Rows[j].Cohort {
+			return res.Rows[i].Cohort < res.Rows[j].Cohort
+		}
+		return res.Rows[i].Group < res.Rows[j].Group
+	})
+
+	return res, nil
+}
+
+// groupLargestEntities keeps the retentionGroupLimit entities with the most
+// members as their own group and moves the rest to data.RetentionGroupOther.
+func groupLargestEntities(members map[string]*retentionMember) {
+	sizes := make(map[string]int)
+	for _, m := range members {
+		sizes[m.group]++
+	}
+	if len(sizes) <= retentionGroupLimit {
+		return
+	}
+
+	names := make([]string, 0, len(sizes))
+	for n := range sizes {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if sizes[names[i]] != sizes[names[j]] {
+			return sizes[names[i]] > sizes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	keep := make(map[string]bool, retentionGroupLimit)
+	for _, n := range names[:retentionGroupLimit] {
+		keep[n] = true
+	}
+	for _, m := range members {
+		if !keep[m.group] {
+			m.group = data.RetentionGroupOther
+		}
+	}
+}
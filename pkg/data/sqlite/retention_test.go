@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCohortRetention_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetCohortRetention(&data.InsightsFilter{Months: 6}, "")
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetCohortRetention_InvalidSplit(t *testing.T) {
+	store := setupTestDB(t)
+	_, err := store.GetCohortRetention(&data.InsightsFilter{Months: 6}, "repo")
+	assert.Error(t, err)
+}
+
+func TestGetCohortRetention_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES
+		('alice', 'Alice', 'ACME'), ('bob', 'Bob', 'BETA'), ('carol', 'Carol', 'ACME'), ('dave', 'Dave', NULL)`)
+	require.NoError(t, err)
+
+	// dave first contributed before the range and is not in any cohort
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+		VALUES
+		('org1', 'repo1', 'dave', 'issue', '2024-12-05', 'http://i/0', '', ''),
+		('org1', 'repo1', 'dave', 'issue_comment', '2025-01-05', 'http://c/0', '', ''),
+		('org1', 'repo1', 'alice', 'pr', '2025-01-03', 'http://p/1', '', ''),
+		('org1', 'repo1', 'alice', 'issue_comment', '2025-01-20', 'http://c/1', '', ''),
+		('org1', 'repo1', 'alice', 'pr_review', '2025-03-02', 'http://p/2#r1', '', ''),
+		('org1', 'repo1', 'bob', 'issue', '2025-01-10', 'http://i/1', '', ''),
+		('org1', 'repo1', 'bob', 'issue_comment', '2025-02-11', 'http://c/2', '', ''),
+		('org1', 'repo1', 'carol', 'issue', '2025-02-14', 'http://i/2', '', ''),
+		('org1', 'repo1', 'carol', 'issue_comment', '2025-03-01', 'http://c/3', '', '')`)
+	require.NoError(t, err)
+
+	f := &data.InsightsFilter{From: strPtr("2025-01-01"), To: strPtr("2025-03-31")}
+	res, err := store.GetCohortRetention(f, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Months)
+	require.Len(t, res.Rows, 2)
+
+	jan := res.Rows[0]
+	assert.Equal(t, "2025-01", jan.Cohort)
+	assert.Equal(t, 2, jan.Size)
+	assert.Equal(t, []int{2, 1, 1}, jan.Active)
+	assert.Equal(t, []float64{100, 50, 50}, jan.Retention)
+
+	feb := res.Rows[1]
+	assert.Equal(t, "2025-02", feb.Cohort)
+	assert.Equal(t, 1, feb.Size)
+	assert.Equal(t, []int{1, 1}, feb.Active)
+
+	res, err = store.GetCohortRetention(f, data.RetentionSplitType)
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 3)
+	assert.Equal(t, "2025-01", res.Rows[0].Cohort)
+	assert.Equal(t, data.EventTypeIssue, res.Rows[0].Group)
+	assert.Equal(t, []int{1, 1, 0}, res.Rows[0].Active)
+	assert.Equal(t, data.EventTypePR, res.Rows[1].Group)
+	assert.Equal(t, []int{1, 0, 1}, res.Rows[1].Active)
+
+	res, err = store.GetCohortRetention(f, data.RetentionSplitEntity)
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 3)
+	assert.Equal(t, "ACME", res.Rows[0].Group)
+	assert.Equal(t, "BETA", res.Rows[1].Group)
+	assert.Equal(t, "ACME", res.Rows[2].Group)
+	assert.Equal(t, "2025-02", res.Rows[2].Cohort)
+}
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	approverConcentrationTopLimit = 10
+
+	upsertPRReviewSQL = `INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			state = excluded.state,
+			submitted_at = excluded.submitted_at
+	`
+
+	// selectReviewDepthSQL aggregates submitted reviews per PR, bucketed by
+	// the month the PR was opened. PR rows are deduplicated by number since a
+	// PR updated on several days is stored once per day; reviews are stored
+	// once per review. Approval rounds count the change requests before the
+	// first approval.
+	selectReviewDepthSQL = `WITH prs AS (
+		SELECT pr.org, pr.repo, pr.number, MIN(substr(pr.created_at, 1, 7)) AS month
+		FROM event pr
+		JOIN developer d ON pr.username = d.username
+		WHERE pr.type = 'pr'
+		  AND pr.number IS NOT NULL
+		  AND pr.created_at IS NOT NULL
+		  AND pr.org = COALESCE(?, pr.org)
+		  AND pr.repo = COALESCE(?, pr.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND pr.created_at >= ?
+		  ` + developerFilterSQL + `
+		GROUP BY pr.org, pr.repo, pr.number
+	),
+	submitted AS (
+		SELECT rev.org, rev.repo, rev.number, rev.state, rev.submitted_at,
+			MIN(CASE WHEN rev.state = 'APPROVED' THEN rev.submitted_at END)
+				OVER (PARTITION BY rev.org, rev.repo, rev.number) AS approved_at
+		FROM pr_review rev
+		WHERE rev.org = COALESCE(?, rev.org)
+		  AND rev.repo = COALESCE(?, rev.repo)
+	),
+	reviews AS (
+		SELECT org, repo, number,
+			COUNT(*) AS reviews,
+			SUM(CASE WHEN state = 'CHANGES_REQUESTED' THEN 1 ELSE 0 END) AS change_requests,
+			SUM(CASE WHEN state = 'CHANGES_REQUESTED' AND submitted_at < approved_at THEN 1 ELSE 0 END) AS rounds,
+			MAX(approved_at) AS approved_at
+		FROM submitted
+		GROUP BY org, repo, number
+	)
+	SELECT
+		p.month,
+		COUNT(*) AS reviewed,
+		AVG(r.reviews) AS reviews_per_pr,
+		COALESCE(AVG(CASE WHEN r.approved_at IS NOT NULL THEN r.rounds + 1 END), 0) AS approval_rounds,
+		AVG(CASE WHEN r.change_requests > 0 THEN 100.0 ELSE 0 END) AS change_request_rate
+	FROM prs p
+	JOIN reviews r ON r.org = p.org AND r.repo = p.repo AND r.number = p.number
+	GROUP BY p.month
+	ORDER BY p.month
+	`
+
+	selectApproverCountsSQL = `SELECT r.username, COUNT(DISTINCT r.org || '/' || r.repo || '#' || r.number) AS approvals
+		FROM pr_review r
+		JOIN developer d ON r.username = d.username
+		WHERE r.state = 'APPROVED'
+		  AND r.org = COALESCE(?, r.org)
+		  AND r.repo = COALESCE(?, r.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND substr(r.submitted_at, 1, 10) >= ?
+		  AND substr(r.submitted_at, 1, 10) < ?
+		  ` + developerFilterSQL + `
+		GROUP BY r.username
+		ORDER BY approvals DESC, r.username
+	`
+)
+
+// GetReviewDepth returns monthly review depth of PRs: reviews per PR, review
+// rounds to approval and the share of PRs with requested changes.
+func (s *Store) GetReviewDepth(f *data.InsightsFilter) (*data.ReviewDepthSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectReviewDepthSQL),
+		f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort,
+		f.Org, f.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review depth: %w", err)
+	}
+	defer rows.Close()
+
+	sr := &data.ReviewDepthSeries{
+		Months:            make([]string, 0),
+		Reviewed:          make([]int, 0),
+		ReviewsPerPR:      make([]float64, 0),
+		ApprovalRounds:    make([]float64, 0),
+		ChangeRequestRate: make([]float64, 0),
+	}
+
+	for rows.Next() {
+		var month string
+		var reviewed int
+		var perPR, rounds, rate float64
+		if err := rows.Scan(&month, &reviewed, &perPR, &rounds, &rate); err != nil {
+			return nil, fmt.Errorf("failed to scan review depth row: %w", err)
+		}
+		sr.Months = append(sr.Months, month)
+		sr.Reviewed = append(sr.Reviewed, reviewed)
+		sr.ReviewsPerPR = append(sr.ReviewsPerPR, perPR)
+		sr.ApprovalRounds = append(sr.ApprovalRounds, rounds)
+		sr.ChangeRequestRate = append(sr.ChangeRequestRate, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	br.align(sr)
+	return sr, nil
+}
+
+// GetApproverConcentration returns how PR approvals are spread across
+// reviewers: the top approvers and how many of them give half of all approvals.
+func (s *Store) GetApproverConcentration(f *data.InsightsFilter) (*data.ApproverConcentration, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectApproverCountsSQL, f.Org, f.Repo, f.Entity, since, br.until(), f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approver counts: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.ApproverShare, 0)
+	res := &data.ApproverConcentration{Top: make([]*data.ApproverShare, 0)}
+	for rows.Next() {
+		a := &data.ApproverShare{}
+		if err := rows.Scan(&a.Username, &a.Approvals); err != nil {
+			return nil, fmt.Errorf("failed to scan approver row: %w", err)
+		}
+		res.Approvals += a.Approvals
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	res.Approvers = len(list)
+
+	// rows are sorted by approvals so the running total finds the factor
+	var running int
+	for i, a := range list {
+		if res.Approvals > 0 {
+			a.Percent = float64(a.Approvals) / float64(res.Approvals) * 100
+		}
+		if running*2 < res.Approvals {
+			res.Factor++
+		}
+		running += a.Approvals
+		if i < approverConcentrationTopLimit {
+			res.Top = append(res.Top, a)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertReviewTestData(t *testing.T, store *Store) string {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob'), ('carol', 'Carol')`)
+	require.NoError(t, err)
+
+	opened := time.Now().UTC().AddDate(0, -1, 0)
+	day := func(d int) string { return opened.AddDate(0, 0, d).Format("2006-01-02") }
+	at := func(d, h int) string {
+		return opened.AddDate(0, 0, d).Truncate(24 * time.Hour).Add(time.Duration(h) * time.Hour).Format(time.RFC3339)
+	}
+	created := opened.Format(time.RFC3339)
+
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at) VALUES
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/1', '', '', 'closed', 1, ?),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/2', '', '', 'closed', 2, ?),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://p/3', '', '', 'open', 3, ?),
+		('org1', 'repo1', 'carol', 'pr_review_comment', ?, 'http://p/3#discussion_r6', '', '', NULL, 3, ?)`,
+		day(0), created, day(1), created, day(2), created, day(4), created)
+	require.NoError(t, err)
+
+	// PR 1: changes requested twice on the same day by bob, then approved
+	// by bob and carol
+	// PR 2: approved by bob right away, on the same day as PR 1
+	// PR 3: only commented on by carol
+	_, err = store.db.Exec(`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at) VALUES
+		(1, 'org1', 'repo1', 1, 'bob', 'CHANGES_REQUESTED', ?),
+		(2, 'org1', 'repo1', 1, 'bob', 'CHANGES_REQUESTED', ?),
+		(3, 'org1', 'repo1', 1, 'bob', 'APPROVED', ?),
+		(4, 'org1', 'repo1', 1, 'carol', 'APPROVED', ?),
+		(5, 'org1', 'repo1', 2, 'bob', 'APPROVED', ?),
+		(7, 'org1', 'repo1', 3, 'carol', 'COMMENTED', ?)`,
+		at(1, 9), at(1, 15), at(2, 10), at(2, 11), at(2, 12), at(4, 9))
+	require.NoError(t, err)
+
+	return opened.Format("2006-01")
+}
+
+func TestGetReviewDepth_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetReviewDepth(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetReviewDepth_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	series, err := store.GetReviewDepth(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Empty(t, series.Months)
+}
+
+func TestGetReviewDepth_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	month := insertReviewTestData(t, store)
+
+	series, err := store.GetReviewDepth(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, month, series.Months[0])
+	assert.Equal(t, 3, series.Reviewed[0])
+	// 6 reviews across 3 PRs; the inline comment is not a review
+	assert.InDelta(t, 2.0, series.ReviewsPerPR[0], 0.01)
+	// PR 1 took 3 rounds, PR 2 took 1
+	assert.InDelta(t, 2.0, series.ApprovalRounds[0], 0.01)
+	// 1 of 3 reviewed PRs had changes requested
+	assert.InDelta(t, 100.0/3.0, series.ChangeRequestRate[0], 0.01)
+}
+
+func TestGetApproverConcentration_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetApproverConcentration(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetApproverConcentration_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+
+	res, err := store.GetApproverConcentration(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Approvals)
+	assert.Equal(t, 0, res.Factor)
+	assert.Empty(t, res.Top)
+}
+
+func TestGetApproverConcentration_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertReviewTestData(t, store)
+
+	res, err := store.GetApproverConcentration(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Approvals)
+	assert.Equal(t, 2, res.Approvers)
+	assert.Equal(t, 1, res.Factor)
+	require.Len(t, res.Top, 2)
+	assert.Equal(t, "bob", res.Top[0].Username)
+	assert.Equal(t, 2, res.Top[0].Approvals)
+	assert.InDelta(t, 200.0/3.0, res.Top[0].Percent, 0.01)
+}
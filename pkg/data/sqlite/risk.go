@@ -0,0 +1,282 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+const (
+	// riskRecentDays and riskRecentLongDays are the recent windows compared
+	// with the baseline, which ends where the long window starts.
+	riskRecentDays     = 30
+	riskRecentLongDays = 60
+
+	// riskDaysPerInterval is the interval of the baseline rates.
+	riskDaysPerInterval = 30.0
+
+	// riskDecline is the decline versus the baseline, as a fraction, at or
+	// above which a contributor is at risk.
+	riskDecline = 0.5
+
+	// riskMinActivity, riskMinReviews and riskMinMerges are the baseline
+	// rates per 30 days below which a kind of contribution is too sparse
+	// to tell a decline.
+	riskMinActivity = 4.0
+	riskMinReviews  = 2.0
+	riskMinMerges   = 2.0
+
+	// selectContributorRiskSQL counts the events, submitted reviews and PR
+	// merges of each contributor per day from the start of the baseline
+	// through the as-of date. Merges are by the merging user.
+	selectContributorRiskSQL = `WITH acts AS (
+		SELECT e.username, IFNULL(d.entity, '') AS entity, e.date AS day,
+			CASE WHEN e.type = 'pr_review' THEN 1 ELSE 0 END AS review, 0 AS merge
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND e.date > ?
+		  AND e.date <= ?
+		  ` + developerFilterSQL + `
+		  ` + forkExcludeSQL + `
+		  ` + discussionFilterSQL + `
+		UNION ALL
+		SELECT t.actor, IFNULL(d.entity, ''), substr(t.created_at, 1, 10), 0, 1
+		FROM timeline_event t
+		JOIN developer d ON t.actor = d.username
+		WHERE t.kind = 'merged'
+		  AND t.org = COALESCE(?, t.org)
+		  AND t.repo = COALESCE(?, t.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND substr(t.created_at, 1, 10) > ?
+		  AND substr(t.created_at, 1, 10) <= ?
+		  ` + developerFilterSQL + `
+	)
+	SELECT username, entity, day, SUM(1 - merge) AS events, SUM(review) AS reviews, SUM(merge) AS merges
+	FROM acts
+	GROUP BY username, entity, day
+	ORDER BY username, day
+	`
+)
+
+// riskCounts are the events, reviews and merges of a contributor in a
+// period.
+type riskCounts struct {
+	events, reviews, merges int
+}
+
+func (c *riskCounts) add(events, reviews, merges int) {
+	c.events += events
+	c.reviews += reviews
+	c.merges += merges
+}
+
+// riskContributor is the activity of a contributor in the baseline and in
+// the recent windows.
+type riskContributor struct {
+	entity     string
+	baseline   riskCounts
+	recent     riskCounts
+	recentLong riskCounts
+	days       map[string]bool
+	lastActive string
+}
+
+// riskDeclineOf returns the decline of a recent count over days versus a
+// baseline rate per 30 days, as a fraction, and false when the baseline
+// rate is below minRate.
+func riskDeclineOf(baseRate, minRate float64, recent, days int) (float64, bool) {
+	if baseRate < minRate || baseRate == 0 {
+		return 0, false
+	}
+	rate := float64(recent) / (float64(days) / riskDaysPerInterval)
+	return math.Max(0, 1-rate/baseRate), true
+}
+
+// riskFrequency describes how often a contributor was active on the
+// baseline days.
+func riskFrequency(days map[string]bool, from, to time.Time) string {
+	span := to.Sub(from).Hours() / 24
+	weeks := make(map[string]bool)
+	months := make(map[string]bool)
+	for d := range days {
+		t, err := time.Parse(dateLayout, d)
+		if err != nil {
+			continue
+		}
+		y, w := t.ISOWeek()
+		weeks[fmt.Sprintf("%d-%02d", y, w)] = true
+		months[d[:7]] = true
+	}
+
+	switch {
+	case float64(len(days)) >= span*3/7:
+		return "daily"
+	case float64(len(weeks)) >= span/7/2:
+		return "weekly"
+	case float64(len(months)) >= span/riskDaysPerInterval/2:
+		return "monthly"
+	default:
+		return "occasionally"
+	}
+}
+
+// GetContributorsAtRisk compares the activity of each contributor in the
+// last 30 and 60 days with their baseline rate in the f.Months months
+// before that, and lists those whose events, reviews or merges in the last
+// 30 days fell by half or more, weighted by their share of reviews and
+// merges. The recent windows end on f.To, or today; f.From is not used. A
+// positive limit caps the number of contributors.
+func (s *Store) GetContributorsAtRisk(f *data.InsightsFilter, limit int) (*data.ContributorsAtRisk, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+	if f.Months < 1 {
+		return nil, fmt.Errorf("baseline months must be positive: %d", f.Months)
+	}
+
+	asOf := time.Now().UTC().Truncate(24 * time.Hour)
+	if f.To != nil {
+		t, err := time.Parse(dateLayout, *f.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %q: %w", *f.To, err)
+		}
+		asOf = t
+	}
+	recentFrom := asOf.AddDate(0, 0, -riskRecentDays)
+	baselineTo := asOf.AddDate(0, 0, -riskRecentLongDays)
+	baselineFrom := baselineTo.AddDate(0, -f.Months, 0)
+
+	since, until := baselineFrom.Format(dateLayout), asOf.Format(dateLayout)
+	rows, err := s.db.Query(selectContributorRiskSQL,
+		f.Org, f.Repo, f.Entity, since, until, f.IncludeBots, f.Cohort, f.IncludeDiscussions,
+		f.Org, f.Repo, f.Entity, since, until, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contributor risk: %w", err)
+	}
+	defer rows.Close()
+
+	contributors := make(map[string]*riskContributor)
+	var totalReviews, totalMerges int
+	for rows.Next() {
+		var username, entity, day string
+		var events, reviews, merges int
+		if err := rows.Scan(&username, &entity, &day, &events, &reviews, &merges); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor risk row: %w", err)
+		}
+
+		c, ok := contributors[username]
+		if !ok {
+			c = &riskContributor{entity: entity, days: make(map[string]bool)}
+			contributors[username] = c
+		}
+		c.lastActive = max(c.lastActive, day)
+
+		switch {
+		case day <= baselineTo.Format(dateLayout):
+			c.baseline.add(events, reviews, merges)
+			c.days[day] = true
+			totalReviews += reviews
+			totalMerges += merges
+		default:
+			c.recentLong.add(events, reviews, merges)
+			if day > recentFrom.Format(dateLayout) {
+				c.recent.add(events, reviews, merges)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	res := &data.ContributorsAtRisk{
+		AsOf:         until,
+		BaselineFrom: since,
+		BaselineTo:   baselineTo.Format(dateLayout),
+		Contributors: make([]*data.ContributorRisk, 0),
+	}
+
+	intervals := baselineTo.Sub(baselineFrom).Hours() / 24 / riskDaysPerInterval
+	for username, c := range contributors {
+		baseRate := float64(c.baseline.events) / intervals
+		activity, hasActivity := riskDeclineOf(baseRate, riskMinActivity, c.recent.events, riskRecentDays)
+		reviews, hasReviews := riskDeclineOf(float64(c.baseline.reviews)/intervals, riskMinReviews, c.recent.reviews, riskRecentDays)
+		merges, hasMerges := riskDeclineOf(float64(c.baseline.merges)/intervals, riskMinMerges, c.recent.merges, riskRecentDays)
+
+		if !hasActivity && !hasReviews && !hasMerges {
+			continue
+		}
+
+		var decline float64
+		reasons := make([]string, 0)
+		if c.recent.events == 0 && c.recent.merges == 0 {
+			last, err := time.Parse(dateLayout, c.lastActive)
+			if err != nil {
+				return nil, fmt.Errorf("invalid activity date %q: %w", c.lastActive, err)
+			}
+			decline = 1
+			reasons = append(reasons, fmt.Sprintf("no activity in %d days, previously %s",
+				int(asOf.Sub(last).Hours()/24), riskFrequency(c.days, baselineFrom, baselineTo)))
+		} else {
+			for _, d := range []struct {
+				name    string
+				decline float64
+				ok      bool
+			}{
+				{"activity", activity, hasActivity},
+				{"reviews", reviews, hasReviews},
+				{"merges", merges, hasMerges},
+			} {
+				if d.ok && d.decline >= riskDecline {
+					decline = math.Max(decline, d.decline)
+					reasons = append(reasons, fmt.Sprintf("%s down %.0f%%", d.name, d.decline*100))
+				}
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		short, _ := riskDeclineOf(baseRate, 0, c.recent.events, riskRecentDays)
+		long, _ := riskDeclineOf(baseRate, 0, c.recentLong.events, riskRecentLongDays)
+
+		r := &data.ContributorRisk{
+			Username:   username,
+			Entity:     c.entity,
+			Baseline:   math.Round(baseRate*10) / 10,
+			Recent30:   c.recent.events,
+			Recent60:   c.recentLong.events,
+			Decline30:  math.Round(short * 100),
+			Decline60:  math.Round(long * 100),
+			LastActive: c.lastActive,
+			Reason:     strings.Join(reasons, "; "),
+		}
+		if totalReviews > 0 {
+			r.ReviewShare = math.Round(float64(c.baseline.reviews)/float64(totalReviews)*1000) / 10
+		}
+		if totalMerges > 0 {
+			r.MergeShare = math.Round(float64(c.baseline.merges)/float64(totalMerges)*1000) / 10
+		}
+		r.Score = math.Round(decline * (1 + (r.ReviewShare+r.MergeShare)/100) * 100)
+		res.Contributors = append(res.Contributors, r)
+	}
+
+	sort.Slice(res.Contributors, func(i, j int) bool {
+		if res.Contributors[i].Score != res.Contributors[j].Score {
+			return res.Contributors[i].Score > res.Contributors[j].Score
+		}
+		return res.Contributors[i].Username < res.Contributors[j].Username
+	})
+	if limit > 0 && len(res.Contributors) > limit {
+		res.Contributors = res.Contributors[:limit]
+	}
+
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContributorsAtRisk_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetContributorsAtRisk(&data.InsightsFilter{Months: 3}, 0)
+	assert.ErrorIs(t, err, data.ErrDBNotInitialized)
+}
+
+func TestGetContributorsAtRisk_WithData(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name, entity) VALUES
+		('alice', 'Alice', 'ACME'), ('bob', 'Bob', 'BETA'), ('carol', 'Carol', 'ACME'), ('dave', 'Dave', NULL)`)
+	require.NoError(t, err)
+
+	insert := func(username, typ string, day time.Time) {
+		d := day.Format(dateLayout)
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+			VALUES ('org1', 'repo1', ?, ?, ?, ?, '', '')`,
+			username, typ, d, fmt.Sprintf("http://e/%s/%s/%s", username, typ, d))
+		require.NoError(t, err)
+	}
+
+	// baseline is 2025-02-02 through 2025-05-02, recent windows end 2025-07-01
+	start := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	for d := start; d.Before(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 7) {
+		// alice comments and reviews weekly, then stops
+		insert("alice", data.EventTypeIssueComment, d)
+		insert("alice", data.EventTypePRReview, d)
+		// carol comments and reviews weekly, then only comments
+		insert("carol", data.EventTypeIssueComment, d)
+		insert("carol", data.EventTypePRReview, d)
+	}
+	for d := start; d.Before(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 3) {
+		// bob is steady throughout
+		insert("bob", data.EventTypeIssueComment, d)
+		if d.After(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) {
+			insert("carol", data.EventTypeIssueComment, d)
+		}
+	}
+	// dave is too sparse to tell
+	insert("dave", data.EventTypeIssue, start)
+	insert("dave", data.EventTypeIssue, start.AddDate(0, 1, 0))
+
+	_, err = store.db.Exec(`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at)
+		VALUES
+		('org1', 'repo1', 1, 'pr', 'merged', 'alice', '', '2025-02-05T12:00:00Z'),
+		('org1', 'repo1', 2, 'pr', 'merged', 'alice', '', '2025-03-05T12:00:00Z')`)
+	require.NoError(t, err)
+
+	res, err := store.GetContributorsAtRisk(&data.InsightsFilter{Months: 3, To: strPtr("2025-07-01")}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-07-01", res.AsOf)
+	assert.Equal(t, "2025-02-02", res.BaselineFrom)
+	assert.Equal(t, "2025-05-02", res.BaselineTo)
+	require.Len(t, res.Contributors, 2)
+
+	alice := res.Contributors[0]
+	assert.Equal(t, "alice", alice.Username)
+	assert.Equal(t, "ACME", alice.Entity)
+	assert.Equal(t, "no activity in 64 days, previously weekly", alice.Reason)
+	assert.Equal(t, "2025-04-28", alice.LastActive)
+	assert.Zero(t, alice.Recent60)
+	assert.InDelta(t, 100, alice.Decline30, 0.01)
+	assert.InDelta(t, 50, alice.ReviewShare, 0.01)
+	assert.InDelta(t, 100, alice.MergeShare, 0.01)
+	assert.InDelta(t, 250, alice.Score, 0.01)
+
+	carol := res.Contributors[1]
+	assert.Equal(t, "carol", carol.Username)
+	assert.Equal(t, "reviews down 100%", carol.Reason)
+	assert.Zero(t, carol.Decline30)
+	assert.InDelta(t, 150, carol.Score, 0.01)
+
+	res, err = store.GetContributorsAtRisk(&data.InsightsFilter{Months: 3, To: strPtr("2025-07-01")}, 1)
+	require.NoError(t, err)
+	require.Len(t, res.Contributors, 1)
+	assert.Equal(t, "alice", res.Contributors[0].Username)
+}
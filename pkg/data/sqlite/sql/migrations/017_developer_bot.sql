@@ -0,0 +1,21 @@
+-- Bot classification: is_bot is the effective flag used by all insight
+-- queries; bot_override holds the user-managed allow/deny list.
+ALTER TABLE developer ADD COLUMN is_bot INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS bot_override (
+    username TEXT NOT NULL PRIMARY KEY,
+    is_bot INTEGER NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- Backfill from the same naming rules as isBotUsername; _ is a LIKE
+-- wildcard so it is escaped.
+UPDATE developer
+SET is_bot = 1
+WHERE username LIKE '%[bot]'
+   OR username LIKE '%-bot'
+   OR username LIKE '%-robot'
+   OR username LIKE '%\_bot' ESCAPE '\'
+   OR LOWER(username) IN ('copilot', 'github-copilot', 'claude', 'anthropic-claude');
+
+CREATE INDEX IF NOT EXISTS idx_developer_is_bot ON developer (is_bot);
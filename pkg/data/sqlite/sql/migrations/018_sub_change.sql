@@ -0,0 +1,13 @@
+-- Records what each substitution changed so it can be undone. Developer
+-- property substitutions store the affected username; username (person)
+-- substitutions store the key of each event moved to the new username.
+CREATE TABLE IF NOT EXISTS sub_change (
+    type TEXT NOT NULL,
+    old TEXT NOT NULL,
+    username TEXT NOT NULL,
+    org TEXT NOT NULL DEFAULT '',
+    repo TEXT NOT NULL DEFAULT '',
+    event_type TEXT NOT NULL DEFAULT '',
+    date TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (type, old, username, org, repo, event_type, date)
+);
@@ -0,0 +1,20 @@
+-- Named contributor cohorts. Membership is the union of static members and
+-- developers matching the (optional) dynamic rules; rule matches are
+-- materialized in cohort_member and refreshed on save and after imports.
+CREATE TABLE IF NOT EXISTS cohort (
+    name TEXT NOT NULL PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    min_merged_prs INTEGER NOT NULL DEFAULT 0,
+    months INTEGER NOT NULL DEFAULT 0,
+    entity TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS cohort_member (
+    cohort TEXT NOT NULL,
+    username TEXT NOT NULL,
+    static INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (cohort, username)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cohort_member_username ON cohort_member (username);
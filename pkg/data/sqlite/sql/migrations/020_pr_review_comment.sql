@@ -0,0 +1,16 @@
+-- Inline diff comments were imported as pr_review; they are now stored as
+-- pr_review_comment so pr_review only holds submitted reviews. Inline
+-- comment URLs carry a #discussion_r anchor, review URLs #pullrequestreview.
+UPDATE OR IGNORE event
+SET type = 'pr_review_comment'
+WHERE type = 'pr_review'
+  AND url LIKE '%#discussion_r%';
+
+DELETE FROM event
+WHERE type = 'pr_review'
+  AND url LIKE '%#discussion_r%';
+
+-- Resume inline comment pagination under the new type.
+UPDATE OR IGNORE state
+SET query = 'pr_review_comment'
+WHERE query = 'pr_review';
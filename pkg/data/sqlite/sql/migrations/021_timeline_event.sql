@@ -0,0 +1,19 @@
+-- Issue and PR timeline entries used for triage metrics. subject holds the
+-- label name, assignee, milestone title, requested reviewer, or the URL of
+-- the cross-referencing issue depending on kind.
+CREATE TABLE IF NOT EXISTS timeline_event (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    item_type TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (org, repo, number, kind, subject, created_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_timeline_event_kind ON timeline_event (org, repo, kind, created_at);
+
+-- Timeline metrics look up issue and PR authors by number.
+CREATE INDEX IF NOT EXISTS idx_event_org_repo_number ON event (org, repo, number);
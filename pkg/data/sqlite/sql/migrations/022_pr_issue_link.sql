@@ -0,0 +1,18 @@
+-- Links from PRs to the issues they resolve. source is 'keyword' for
+-- closing keywords in the PR body (fixes #123) and 'connected' for links
+-- made in the GitHub UI (paired connected timeline events).
+CREATE TABLE IF NOT EXISTS pr_issue_link (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    pr_number INTEGER NOT NULL,
+    issue_org TEXT NOT NULL,
+    issue_repo TEXT NOT NULL,
+    issue_number INTEGER NOT NULL,
+    source TEXT NOT NULL,
+    PRIMARY KEY (org, repo, pr_number, issue_org, issue_repo, issue_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_issue_link_issue ON pr_issue_link (issue_org, issue_repo, issue_number);
+
+-- Issue close reason (completed, not_planned) to tell fixes from won't-fix.
+ALTER TABLE event ADD COLUMN state_reason TEXT;
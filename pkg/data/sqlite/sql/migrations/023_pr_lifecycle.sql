@@ -0,0 +1,7 @@
+-- PR draft status and the time the PR was first marked ready for review.
+ALTER TABLE event ADD COLUMN draft INTEGER;
+ALTER TABLE event ADD COLUMN ready_at TEXT;
+
+-- GitHub reports merged PRs as closed; mark them merged so merge-based
+-- queries can rely on state.
+UPDATE event SET state = 'merged' WHERE type = 'pr' AND merged_at IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Daily counts of open issues and PRs by age bucket, used to chart backlog
+-- growth. no_response counts open items without a maintainer response.
+CREATE TABLE IF NOT EXISTS backlog_snapshot (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    date TEXT NOT NULL,
+    issues INTEGER NOT NULL DEFAULT 0,
+    prs INTEGER NOT NULL DEFAULT 0,
+    no_response INTEGER NOT NULL DEFAULT 0,
+    age_7d INTEGER NOT NULL DEFAULT 0,
+    age_30d INTEGER NOT NULL DEFAULT 0,
+    age_90d INTEGER NOT NULL DEFAULT 0,
+    age_1y INTEGER NOT NULL DEFAULT 0,
+    age_older INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (org, repo, date)
+);
@@ -0,0 +1,11 @@
+-- Full UTC time of each event alongside the day key in date.
+ALTER TABLE event ADD COLUMN event_at TEXT;
+
+-- Backfill from created_at where it falls on the day key, which holds for
+-- reviews and for items not updated after the day they were created.
+UPDATE event SET event_at = created_at
+WHERE event_at IS NULL
+  AND created_at IS NOT NULL
+  AND substr(created_at, 1, 10) = date;
+
+CREATE INDEX IF NOT EXISTS idx_event_event_at ON event(event_at);
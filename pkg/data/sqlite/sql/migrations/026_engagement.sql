@@ -0,0 +1,20 @@
+-- Latest reaction and comment counts per issue or PR, refreshed on import.
+-- Issue and PR numbers share one sequence per repo.
+CREATE TABLE IF NOT EXISTS engagement (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    item_type TEXT NOT NULL,
+    comments INTEGER NOT NULL DEFAULT 0,
+    reactions INTEGER NOT NULL DEFAULT 0,
+    plus_one INTEGER NOT NULL DEFAULT 0,
+    minus_one INTEGER NOT NULL DEFAULT 0,
+    laugh INTEGER NOT NULL DEFAULT 0,
+    hooray INTEGER NOT NULL DEFAULT 0,
+    confused INTEGER NOT NULL DEFAULT 0,
+    heart INTEGER NOT NULL DEFAULT 0,
+    rocket INTEGER NOT NULL DEFAULT 0,
+    eyes INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (org, repo, number)
+);
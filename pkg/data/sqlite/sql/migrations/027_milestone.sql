@@ -0,0 +1,46 @@
+-- Repository milestones with their latest issue counts.
+CREATE TABLE IF NOT EXISTS milestone (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    state TEXT NOT NULL,
+    url TEXT NOT NULL,
+    due_on TEXT,
+    created_at TEXT NOT NULL,
+    closed_at TEXT,
+    open_issues INTEGER NOT NULL DEFAULT 0,
+    closed_issues INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (org, repo, number)
+);
+
+-- Issues and PRs currently in a milestone, used to build burn-up series.
+-- Issue and PR numbers share one sequence per repo.
+CREATE TABLE IF NOT EXISTS milestone_item (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    milestone INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    item_type TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    closed_at TEXT,
+    PRIMARY KEY (org, repo, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestone_item_milestone ON milestone_item (org, repo, milestone);
+
+-- Status of repository items on GitHub Projects (v2) boards, taken from the
+-- board's Status field.
+CREATE TABLE IF NOT EXISTS project_item (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    project INTEGER NOT NULL,
+    project_title TEXT NOT NULL,
+    item_id TEXT NOT NULL,
+    number INTEGER,
+    item_type TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (org, repo, project, item_id)
+);
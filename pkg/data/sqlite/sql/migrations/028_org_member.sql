@@ -0,0 +1,13 @@
+-- GitHub author association (OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR,
+-- FIRST_TIME_CONTRIBUTOR, FIRST_TIMER, NONE) of the event author at the
+-- time of import.
+ALTER TABLE event ADD COLUMN author_association TEXT;
+
+-- Members and outside collaborators of the imported orgs.
+CREATE TABLE IF NOT EXISTS org_member (
+    org TEXT NOT NULL,
+    username TEXT NOT NULL,
+    role TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (org, username)
+);
@@ -0,0 +1,16 @@
+-- Anomalies detected in the monthly and weekly metric series of each repo,
+-- of each org (repo is empty) and across orgs (org and repo are empty),
+-- replaced on every import.
+CREATE TABLE IF NOT EXISTS anomaly (
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    granularity TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    period TEXT NOT NULL,
+    value REAL NOT NULL,
+    median REAL NOT NULL,
+    mad REAL NOT NULL,
+    score REAL NOT NULL,
+    detected_at TEXT NOT NULL,
+    PRIMARY KEY (org, repo, granularity, metric, period)
+);
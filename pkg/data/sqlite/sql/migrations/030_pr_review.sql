@@ -0,0 +1,25 @@
+-- Submitted PR reviews keyed by their GitHub review ID. The event table
+-- keeps one pr_review row per reviewer and day, which collapses several
+-- reviews of the same day into one.
+CREATE TABLE IF NOT EXISTS pr_review (
+    id INTEGER NOT NULL PRIMARY KEY,
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    state TEXT NOT NULL,
+    submitted_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_review_number ON pr_review (org, repo, number);
+CREATE INDEX IF NOT EXISTS idx_pr_review_submitted ON pr_review (org, repo, submitted_at);
+
+-- Backfill from the review events imported so far; the review ID is the
+-- #pullrequestreview- anchor of the URL.
+INSERT OR IGNORE INTO pr_review (id, org, repo, number, username, state, submitted_at)
+SELECT CAST(substr(url, instr(url, '#pullrequestreview-') + 19) AS INTEGER),
+    org, repo, number, username, IFNULL(state, ''), COALESCE(created_at, event_at, date)
+FROM event
+WHERE type = 'pr_review'
+  AND number IS NOT NULL
+  AND url LIKE '%#pullrequestreview-%';
@@ -0,0 +1,57 @@
+-- GitHub discussions and their comments keyed by URL. The event table keeps
+-- one row per author and day, which collapses same-day comments on several
+-- discussions and adds a row for each later day a comment is edited on.
+-- answer_url is the URL of the accepted answer comment, if any.
+CREATE TABLE IF NOT EXISTS discussion (
+    url TEXT NOT NULL PRIMARY KEY,
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    state TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    answer_url TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_discussion_created ON discussion (org, repo, created_at);
+CREATE INDEX IF NOT EXISTS idx_discussion_answer ON discussion (answer_url);
+
+CREATE TABLE IF NOT EXISTS discussion_comment (
+    url TEXT NOT NULL PRIMARY KEY,
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_discussion_comment_number ON discussion_comment (org, repo, number);
+CREATE INDEX IF NOT EXISTS idx_discussion_comment_created ON discussion_comment (org, repo, created_at);
+
+-- Backfill from the discussion events imported so far, taking the latest
+-- row of each URL.
+INSERT OR IGNORE INTO discussion (url, org, repo, number, username, state, created_at, updated_at, answer_url)
+SELECT url, org, repo, number, username, IFNULL(state, ''), created_at, COALESCE(event_at, date),
+    (SELECT MAX(c.url) FROM event c
+        WHERE c.org = l.org AND c.repo = l.repo AND c.number = l.number
+          AND c.type = 'discussion_comment' AND c.state = 'answer')
+FROM (
+    SELECT e.*, ROW_NUMBER() OVER (PARTITION BY e.url ORDER BY e.date DESC) AS rn
+    FROM event e
+    WHERE e.type = 'discussion'
+      AND e.number IS NOT NULL
+      AND e.created_at IS NOT NULL
+) l
+WHERE l.rn = 1;
+
+INSERT OR IGNORE INTO discussion_comment (url, org, repo, number, username, created_at, updated_at)
+SELECT url, org, repo, number, username, COALESCE(created_at, event_at, date), COALESCE(event_at, date)
+FROM (
+    SELECT e.*, ROW_NUMBER() OVER (PARTITION BY e.url ORDER BY e.date DESC) AS rn
+    FROM event e
+    WHERE e.type = 'discussion_comment'
+      AND e.number IS NOT NULL
+) l
+WHERE l.rn = 1;
@@ -0,0 +1,21 @@
+-- Username substitutions now move rows in every table holding a username,
+-- so changes record the table (source) and the row key within it (item)
+-- instead of event columns. Developer property changes keep both empty.
+CREATE TABLE IF NOT EXISTS sub_change_new (
+    type TEXT NOT NULL,
+    old TEXT NOT NULL,
+    username TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    item TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (type, old, username, source, item)
+);
+
+INSERT OR IGNORE INTO sub_change_new (type, old, username, source, item)
+SELECT type, old, username,
+    CASE WHEN event_type = '' THEN '' ELSE 'event' END,
+    CASE WHEN event_type = '' THEN '' ELSE org || '|' || repo || '|' || event_type || '|' || date END
+FROM sub_change;
+
+DROP TABLE sub_change;
+
+ALTER TABLE sub_change_new RENAME TO sub_change;
@@ -0,0 +1,23 @@
+-- Time of each imported issue, PR, comment, review, fork and discussion
+-- keyed by type and URL: its creation time, or its last update when GitHub
+-- has no creation time for it. The event table keeps one row per author,
+-- type and day, which collapses same-day items into one.
+CREATE TABLE IF NOT EXISTS event_item (
+    type TEXT NOT NULL,
+    url TEXT NOT NULL,
+    org TEXT NOT NULL,
+    repo TEXT NOT NULL,
+    username TEXT NOT NULL,
+    at TEXT NOT NULL,
+    PRIMARY KEY (type, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_item_at ON event_item (org, repo, at);
+
+-- Backfill from the events imported so far, latest day first.
+INSERT OR IGNORE INTO event_item (type, url, org, repo, username, at)
+SELECT type, url, org, repo, username, COALESCE(created_at, event_at)
+FROM event
+WHERE url != ''
+  AND COALESCE(created_at, event_at) IS NOT NULL
+ORDER BY date DESC;
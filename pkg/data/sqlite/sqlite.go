@@ -5,6 +5,7 @@ import (
 	"embed"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -20,7 +21,9 @@ var migrationsFS embed.FS
 
 // Store implements data.Store for SQLite.
 type Store struct {
-	db *sql.DB
+	db         *sql.DB
+	normalizer *entityNormalizer
+	failures   *failureClassifier
 }
 
 // New creates a new SQLite Store, running migrations automatically.
@@ -29,6 +32,26 @@ func New(dbFilePath string) (*Store, error) {
 		return nil, fmt.Errorf("dbFilePath not specified")
 	}
 
+	rules, err := data.LoadEntityRules(filepath.Join(filepath.Dir(dbFilePath), data.EntityRulesFileName))
+	if err != nil {
+		return nil, fmt.Errorf("loading entity rules: %w", err)
+	}
+
+	normalizer, err := newEntityNormalizer(rules)
+	if err != nil {
+		return nil, fmt.Errorf("compiling entity rules: %w", err)
+	}
+
+	failureRules, err := data.LoadFailureRules(filepath.Join(filepath.Dir(dbFilePath), data.FailureRulesFileName))
+	if err != nil {
+		return nil, fmt.Errorf("loading failure rules: %w", err)
+	}
+
+	failures, err := newFailureClassifier(failureRules)
+	if err != nil {
+		return nil, fmt.Errorf("compiling failure rules: %w", err)
+	}
+
 	db, err := openDB(dbFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("opening database %s: %w", dbFilePath, err)
@@ -39,7 +62,7 @@ func New(dbFilePath string) (*Store, error) {
 		return nil, fmt.Errorf("running migrations: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: db, normalizer: normalizer, failures: failures}, nil
 }
 
 // Close closes the underlying database connection pool.
@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+)
+
+// durationSamples collects duration samples per month. Rows are expected to
+// be ordered by month, so a new month starts a new bucket.
+type durationSamples struct {
+	months []string
+	values [][]float64
+}
+
+func newDurationSamples() *durationSamples {
+	return &durationSamples{
+		months: make([]string, 0),
+		values: make([][]float64, 0),
+	}
+}
+
+// bucket returns the index of the month, adding an empty bucket when the
+// month differs from the last one.
+func (d *durationSamples) bucket(month string) int {
+	i := len(d.months) - 1
+	if i < 0 || d.months[i] != month {
+		d.months = append(d.months, month)
+		d.values = append(d.values, make([]float64, 0))
+		i++
+	}
+	return i
+}
+
+func (d *durationSamples) add(month string, v float64) {
+	i := d.bucket(month)
+	d.values[i] = append(d.values[i], v)
+}
+
+// scanDurationSamples reads (month, duration) rows; NULL durations only
+// add the month.
+func scanDurationSamples(rows *sql.Rows) (*durationSamples, error) {
+	d := newDurationSamples()
+	for rows.Next() {
+		var month string
+		var v sql.NullFloat64
+		if err := rows.Scan(&month, &v); err != nil {
+			return nil, fmt.Errorf("failed to scan duration row: %w", err)
+		}
+		if !v.Valid {
+			d.bucket(month)
+			continue
+		}
+		d.add(month, v.Float64)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return d, nil
+}
+
+// durationStats summarizes a sample of durations.
+type durationStats struct {
+	count int
+	avg   float64
+	p50   float64
+	p75   float64
+	p90   float64
+	min   float64
+	max   float64
+}
+
+// summarizeDurations returns the mean, percentiles and range of values.
+// An empty sample returns zeros.
+func summarizeDurations(values []float64) durationStats {
+	if len(values) == 0 {
+		return durationStats{}
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	return durationStats{
+		count: len(sorted),
+		avg:   sum / float64(len(sorted)),
+		p50:   percentile(sorted, 50),
+		p75:   percentile(sorted, 75),
+		p90:   percentile(sorted, 90),
+		min:   sorted[0],
+		max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// velocitySeries summarizes samples in days by month.
+func velocitySeries(d *durationSamples) *data.VelocitySeries {
+	sr := &data.VelocitySeries{
+		Months:  d.months,
+		Count:   make([]int, len(d.months)),
+		AvgDays: make([]float64, len(d.months)),
+		P50Days: make([]float64, len(d.months)),
+		P75Days: make([]float64, len(d.months)),
+		P90Days: make([]float64, len(d.months)),
+		MinDays: make([]float64, len(d.months)),
+		MaxDays: make([]float64, len(d.months)),
+	}
+
+	for i, values := range d.values {
+		st := summarizeDurations(values)
+		sr.Count[i] = st.count
+		sr.AvgDays[i] = st.avg
+		sr.P50Days[i] = st.p50
+		sr.P75Days[i] = st.p75
+		sr.P90Days[i] = st.p90
+		sr.MinDays[i] = st.min
+		sr.MaxDays[i] = st.max
+	}
+
+	return sr
+}
+
+// reviewLatencySeries summarizes samples in hours by month. When business
+// is not nil it must hold the business hour samples of the same months.
+func reviewLatencySeries(d, business *durationSamples) *data.ReviewLatencySeries {
+	sr := &data.ReviewLatencySeries{
+		Months:   d.months,
+		Count:    make([]int, len(d.months)),
+		AvgHours: make([]float64, len(d.months)),
+		P50Hours: make([]float64, len(d.months)),
+		P75Hours: make([]float64, len(d.months)),
+		P90Hours: make([]float64, len(d.months)),
+		MinHours: make([]float64, len(d.months)),
+		MaxHours: make([]float64, len(d.months)),
+	}
+
+	for i, values := range d.values {
+		st := summarizeDurations(values)
+		sr.Count[i] = st.count
+		sr.AvgHours[i] = st.avg
+		sr.P50Hours[i] = st.p50
+		sr.P75Hours[i] = st.p75
+		sr.P90Hours[i] = st.p90
+		sr.MinHours[i] = st.min
+		sr.MaxHours[i] = st.max
+	}
+
+	if business != nil {
+		sr.BusinessAvgHours = make([]float64, len(d.months))
+		sr.BusinessP50Hours = make([]float64, len(d.months))
+		for i, values := range business.values {
+			st := summarizeDurations(values)
+			sr.BusinessAvgHours[i] = st.avg
+			sr.BusinessP50Hours[i] = st.p50
+		}
+	}
+
+	return sr
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 10}
+
+	assert.InDelta(t, 0.0, percentile(nil, 50), 0.001)
+	assert.InDelta(t, 3.0, percentile(sorted, 50), 0.001)
+	assert.InDelta(t, 4.0, percentile(sorted, 75), 0.001)
+	assert.InDelta(t, 7.6, percentile(sorted, 90), 0.001) // 4 + (10-4)*0.6
+	assert.InDelta(t, 1.0, percentile(sorted, 0), 0.001)
+	assert.InDelta(t, 10.0, percentile(sorted, 100), 0.001)
+	assert.InDelta(t, 5.0, percentile([]float64{5}, 90), 0.001)
+}
+
+func TestSummarizeDurations(t *testing.T) {
+	assert.Equal(t, durationStats{}, summarizeDurations(nil))
+
+	values := []float64{10, 1, 3, 2, 4}
+	st := summarizeDurations(values)
+	assert.Equal(t, 5, st.count)
+	assert.InDelta(t, 4.0, st.avg, 0.001)
+	assert.InDelta(t, 3.0, st.p50, 0.001)
+	assert.InDelta(t, 4.0, st.p75, 0.001)
+	assert.InDelta(t, 7.6, st.p90, 0.001)
+	assert.InDelta(t, 1.0, st.min, 0.001)
+	assert.InDelta(t, 10.0, st.max, 0.001)
+	// input is left unsorted
+	assert.Equal(t, []float64{10, 1, 3, 2, 4}, values)
+}
+
+func TestDurationSamples_Buckets(t *testing.T) {
+	d := newDurationSamples()
+	d.add("2025-01", 1)
+	d.add("2025-01", 2)
+	d.bucket("2025-02")
+	d.add("2025-03", 5)
+
+	assert.Equal(t, []string{"2025-01", "2025-02", "2025-03"}, d.months)
+	assert.Equal(t, [][]float64{{1, 2}, {}, {5}}, d.values)
+
+	sr := velocitySeries(d)
+	assert.Equal(t, []int{2, 0, 1}, sr.Count)
+	assert.InDelta(t, 1.5, sr.P50Days[0], 0.001)
+	assert.InDelta(t, 0.0, sr.P50Days[1], 0.001)
+	assert.Nil(t, reviewLatencySeries(d, nil).BusinessAvgHours)
+}
+
+func TestGetTimeToMerge_Percentiles(t *testing.T) {
+	store := setupTestDB(t)
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES ('alice', 'Alice')`)
+	require.NoError(t, err)
+
+	// three quick merges and one PR that sat open for almost a year
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, created_at, merged_at)
+		VALUES
+		('org1', 'repo1', 'alice', 'pr', '2025-03-02', 'http://a1', '', '', 'merged', '2025-03-01T00:00:00Z', '2025-03-02T00:00:00Z'),
+		('org1', 'repo1', 'alice', 'pr', '2025-03-04', 'http://a2', '', '', 'merged', '2025-03-02T00:00:00Z', '2025-03-04T00:00:00Z'),
+		('org1', 'repo1', 'alice', 'pr', '2025-03-06', 'http://a3', '', '', 'merged', '2025-03-03T00:00:00Z', '2025-03-06T00:00:00Z'),
+		('org1', 'repo1', 'alice', 'pr', '2026-03-01', 'http://a4', '', '', 'merged', '2025-03-05T00:00:00Z', '2026-02-28T00:00:00Z')`)
+	require.NoError(t, err)
+
+	series, err := store.GetTimeToMerge(&data.InsightsFilter{Months: 24})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, 4, series.Count[0])
+	assert.InDelta(t, 2.5, series.P50Days[0], 0.01) // (2+3)/2
+	assert.Greater(t, series.AvgDays[0], 90.0)
+	assert.InDelta(t, 1.0, series.MinDays[0], 0.01)
+	assert.InDelta(t, 360.0, series.MaxDays[0], 0.01)
+	assert.LessOrEqual(t, series.P75Days[0], series.P90Days[0])
+}
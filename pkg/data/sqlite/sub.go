@@ -1,7 +1,10 @@
 package sqlite
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/mchmarny/devpulse/pkg/data"
 )
@@ -12,8 +15,169 @@ const (
 	`
 
 	selectSubSQL = `SELECT type, old, new FROM sub`
+
+	selectSubByKeySQL = `SELECT type, old, new FROM sub WHERE type = ? AND old = ?`
+
+	selectSubListSQL = `SELECT s.type, s.old, s.new, COUNT(c.username)
+		FROM sub s
+		LEFT JOIN sub_change c ON c.type = s.type AND c.old = s.old
+		GROUP BY s.type, s.old, s.new
+		ORDER BY s.type, s.old
+	`
+
+	deleteSubSQL = `DELETE FROM sub WHERE type = ? AND old = ?`
+
+	deleteSubChangeSQL = `DELETE FROM sub_change WHERE type = ? AND old = ?`
+
+	// insertSubDeveloperChangeSQL records developers about to be updated.
+	// Formatted with the developer property column.
+	insertSubDeveloperChangeSQL = `INSERT OR IGNORE INTO sub_change (type, old, username)
+		SELECT ?, ?, username FROM developer WHERE %s = ?
+	`
+
+	// restoreSubDeveloperSQL reverts recorded developers still holding the
+	// substituted value. Formatted with the developer property column.
+	restoreSubDeveloperSQL = `UPDATE developer SET %s = ?
+		WHERE %s = ?
+		AND username IN (SELECT username FROM sub_change WHERE type = ? AND old = ?)
+	`
+
+	// The username substitution statements below are formatted with a
+	// subTable: table, username column, row key expression and row filter,
+	// all on the "t" table alias.
+
+	// mergeSubRowSQL drops the rows of the old username that the new
+	// username already has, leaving the new username's row in their place.
+	// Args: old, new.
+	mergeSubRowSQL = `DELETE FROM %[1]s AS t
+		WHERE t.%[2]s = ? %[4]s
+		AND %[3]s IN (SELECT %[3]s FROM %[1]s AS t WHERE t.%[2]s = ?)
+	`
+
+	// recordSubRowSQL records the rows about to be moved. Args: type, old, old.
+	recordSubRowSQL = `INSERT OR IGNORE INTO sub_change (type, old, username, source, item)
+		SELECT ?, ?, t.%[2]s, '%[1]s', %[3]s
+		FROM %[1]s AS t
+		WHERE t.%[2]s = ? %[4]s
+	`
+
+	// updateSubRowSQL moves the rows to the new username. Args: new, old.
+	updateSubRowSQL = `UPDATE %[1]s AS t SET %[2]s = ? WHERE t.%[2]s = ? %[4]s`
+
+	// unmergeSubRowSQL drops recorded rows the old username has again since,
+	// leaving the old username's row in their place. Args: new, type, old, old.
+	unmergeSubRowSQL = `DELETE FROM %[1]s AS t
+		WHERE t.%[2]s = ?
+		AND %[3]s IN (SELECT item FROM sub_change WHERE type = ? AND old = ? AND source = '%[1]s')
+		AND %[3]s IN (SELECT %[3]s FROM %[1]s AS t WHERE t.%[2]s = ?)
+	`
+
+	// restoreSubRowSQL moves recorded rows back to the old username.
+	// Args: old, new, type, old.
+	restoreSubRowSQL = `UPDATE %[1]s AS t SET %[2]s = ?
+		WHERE t.%[2]s = ?
+		AND %[3]s IN (SELECT item FROM sub_change WHERE type = ? AND old = ? AND source = '%[1]s')
+	`
+
+	// promoteSubCohortMemberSQL keeps cohort memberships static when a static
+	// member of the old username merges into a rule-matched one of the new
+	// username. Args: new, old.
+	promoteSubCohortMemberSQL = `UPDATE cohort_member SET static = 1
+		WHERE username = ?
+		AND cohort IN (SELECT cohort FROM cohort_member WHERE username = ? AND static = 1)
+	`
+
+	selectSubDeveloperExistsSQL = `SELECT COUNT(*) FROM developer WHERE username = ?`
 )
 
+// subPropUsername maps all activity of one username to another (person).
+const subPropUsername = "username"
+
+// subTable is a table moved by username substitutions. key identifies a row
+// apart from its username; merge is set when the username is part of the
+// primary key, so moved rows can collide with rows of the new username.
+type subTable struct {
+	name   string
+	col    string
+	key    string
+	filter string
+	merge  bool
+}
+
+func (t subTable) sql(q string) string {
+	return fmt.Sprintf(q, t.name, t.col, t.key, t.filter)
+}
+
+// subTables lists every table holding a username. Rule-matched cohort
+// members are left to the next cohort refresh.
+var subTables = []subTable{
+	{name: "event", col: "username", key: "t.org || '|' || t.repo || '|' || t.type || '|' || t.date", merge: true},
+	{name: "event_item", col: "username", key: "t.type || '|' || t.url"},
+	{name: "pr_review", col: "username", key: "CAST(t.id AS TEXT)"},
+	{name: "discussion", col: "username", key: "t.url"},
+	{name: "discussion_comment", col: "username", key: "t.url"},
+	{name: "timeline_event", col: "actor",
+		key: "t.org || '|' || t.repo || '|' || t.number || '|' || t.kind || '|' || t.subject || '|' || t.created_at"},
+	{name: "org_member", col: "username", key: "t.org", merge: true},
+	{name: "cohort_member", col: "username", key: "t.cohort", filter: "AND t.static = 1", merge: true},
+}
+
+// applyUsernameSub moves the rows of the old username in every table to
+// the new one. Rows the new username already has are merged into its row
+// and are not restored. Returns the number of moved rows.
+func applyUsernameSub(tx *sql.Tx, sub *data.Substitution) (int64, error) {
+	if _, err := tx.Exec(promoteSubCohortMemberSQL, sub.New, sub.Old); err != nil {
+		return 0, fmt.Errorf("failed to merge substituted cohort members: %w", err)
+	}
+
+	var rows int64
+	for _, t := range subTables {
+		if t.merge {
+			if _, err := tx.Exec(t.sql(mergeSubRowSQL), sub.Old, sub.New); err != nil {
+				return 0, fmt.Errorf("failed to merge substituted %s rows: %w", t.name, err)
+			}
+		}
+		if _, err := tx.Exec(t.sql(recordSubRowSQL), sub.Prop, sub.Old, sub.Old); err != nil {
+			return 0, fmt.Errorf("failed to record substituted %s rows: %w", t.name, err)
+		}
+		res, err := tx.Exec(t.sql(updateSubRowSQL), sub.New, sub.Old)
+		if err != nil {
+			return 0, fmt.Errorf("failed to substitute %s rows: %w", t.name, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		rows += n
+	}
+
+	return rows, nil
+}
+
+// restoreUsernameSub moves the rows recorded by the substitution back to
+// the old username. Returns the number of restored rows.
+func restoreUsernameSub(tx *sql.Tx, sub *data.Substitution) (int64, error) {
+	var rows int64
+	for _, t := range subTables {
+		if t.merge {
+			if _, err := tx.Exec(t.sql(unmergeSubRowSQL), sub.New, sub.Prop, sub.Old, sub.Old); err != nil {
+				return 0, fmt.Errorf("failed to merge restored %s rows: %w", t.name, err)
+			}
+		}
+		res, err := tx.Exec(t.sql(restoreSubRowSQL), sub.Old, sub.New, sub.Prop, sub.Old)
+		if err != nil {
+			return 0, fmt.Errorf("failed to restore %s rows: %w", t.name, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		rows += n
+	}
+
+	return rows, nil
+}
+
 func (s *Store) applyDeveloperSub(sub *data.Substitution) error {
 	if s.db == nil {
 		return data.ErrDBNotInitialized
@@ -27,38 +191,88 @@ func (s *Store) applyDeveloperSub(sub *data.Substitution) error {
 		return fmt.Errorf("invalid property: %s (permitted options: %v)", sub.Prop, data.UpdatableProperties)
 	}
 
-	stmt, err := s.db.Prepare(fmt.Sprintf(updateDeveloperPropertySQL, sub.Prop, sub.Prop))
+	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to prepare sql statement: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if sub.Prop == subPropUsername {
+		rows, subErr := applyUsernameSub(tx, sub)
+		if subErr != nil {
+			rollbackTransaction(tx)
+			return subErr
+		}
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		sub.Records = rows
+		return nil
 	}
-	defer stmt.Close()
 
-	res, err := stmt.Exec(sub.New, sub.Old)
+	if _, err = tx.Exec(fmt.Sprintf(insertSubDeveloperChangeSQL, sub.Prop), sub.Prop, sub.Old, sub.Old); err != nil {
+		rollbackTransaction(tx)
+		return fmt.Errorf("failed to record substituted developers: %w", err)
+	}
+
+	res, err := tx.Exec(fmt.Sprintf(updateDeveloperPropertySQL, sub.Prop, sub.Prop), sub.New, sub.Old)
 	if err != nil {
+		rollbackTransaction(tx)
 		return fmt.Errorf("failed to execute developer property update statement: %w", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
+		rollbackTransaction(tx)
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	sub.Records = rows
 
 	return nil
 }
 
+// SaveAndApplyDeveloperSub saves the substitution and applies it to existing
+// data. Saving a different new value for an existing substitution first
+// restores the data changed by the previous one.
 func (s *Store) SaveAndApplyDeveloperSub(prop, old, new string) (*data.Substitution, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
 	}
 
+	if strings.TrimSpace(old) == "" || strings.TrimSpace(new) == "" {
+		return nil, errors.New("old and new values are required")
+	}
+
 	sub := &data.Substitution{
 		Prop: prop,
 		Old:  old,
 		New:  new,
 	}
 
+	if prop == subPropUsername {
+		var count int
+		if err := s.db.QueryRow(selectSubDeveloperExistsSQL, new).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to check developer %s: %w", new, err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("developer not found: %s", new)
+		}
+	}
+
+	prev, err := s.getSub(prop, old)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil && prev.New != new {
+		if _, err = s.DeleteSub(prop, old); err != nil {
+			return nil, fmt.Errorf("failed to restore previous substitution: %w", err)
+		}
+	}
+
 	if err := s.applyDeveloperSub(sub); err != nil {
 		return nil, fmt.Errorf("failed to apply developer sub: %w", err)
 	}
@@ -76,6 +290,98 @@ func (s *Store) SaveAndApplyDeveloperSub(prop, old, new string) (*data.Substitut
 	return sub, nil
 }
 
+// GetSubstitutions lists saved substitutions. Records holds the number of
+// developers (or rows, for username substitutions) each one changed.
+func (s *Store) GetSubstitutions() ([]*data.Substitution, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	rows, err := s.db.Query(selectSubListSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute substitute select statement: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.Substitution, 0)
+	for rows.Next() {
+		sub := &data.Substitution{}
+		if err := rows.Scan(&sub.Prop, &sub.Old, &sub.New, &sub.Records); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		list = append(list, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// DeleteSub removes the substitution and restores the original values of the
+// data it changed. Records holds the number of restored rows. Returns nil if
+// the substitution does not exist.
+func (s *Store) DeleteSub(prop, old string) (*data.Substitution, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	sub, err := s.getSub(prop, old)
+	if err != nil || sub == nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if sub.Prop == subPropUsername {
+		if sub.Records, err = restoreUsernameSub(tx, sub); err != nil {
+			rollbackTransaction(tx)
+			return nil, err
+		}
+	} else {
+		res, execErr := tx.Exec(fmt.Sprintf(restoreSubDeveloperSQL, sub.Prop, sub.Prop), sub.Old, sub.New, sub.Prop, sub.Old)
+		if execErr != nil {
+			rollbackTransaction(tx)
+			return nil, fmt.Errorf("failed to restore substituted values: %w", execErr)
+		}
+		if sub.Records, err = res.RowsAffected(); err != nil {
+			rollbackTransaction(tx)
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+	}
+
+	if _, err = tx.Exec(deleteSubChangeSQL, sub.Prop, sub.Old); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to delete substitution changes: %w", err)
+	}
+
+	if _, err = tx.Exec(deleteSubSQL, sub.Prop, sub.Old); err != nil {
+		rollbackTransaction(tx)
+		return nil, fmt.Errorf("failed to delete substitution: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (s *Store) getSub(prop, old string) (*data.Substitution, error) {
+	sub := &data.Substitution{}
+	if err := s.db.QueryRow(selectSubByKeySQL, prop, old).Scan(&sub.Prop, &sub.Old, &sub.New); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to select substitution: %w", err)
+	}
+	return sub, nil
+}
+
 func (s *Store) ApplySubstitutions() ([]*data.Substitution, error) {
 	if s.db == nil {
 		return nil, data.ErrDBNotInitialized
@@ -65,3 +65,144 @@ func TestApplySubstitutions_NilDB(t *testing.T) {
 	_, err := s.ApplySubstitutions()
 	assert.Error(t, err)
 }
+
+func TestDeleteSub_RestoresOriginal(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "u1", FullName: "U1", Entity: "ACME INC"},
+		{Username: "u2", FullName: "U2", Entity: "ACME"},
+	}))
+
+	_, err := store.SaveAndApplyDeveloperSub("entity", "ACME INC", "ACME")
+	require.NoError(t, err)
+
+	list, err := store.GetSubstitutions()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(1), list[0].Records)
+
+	sub, err := store.DeleteSub("entity", "ACME INC")
+	require.NoError(t, err)
+	require.NotNil(t, sub)
+	assert.Equal(t, int64(1), sub.Records)
+
+	dev, err := store.GetDeveloper("u1")
+	require.NoError(t, err)
+	assert.Equal(t, "ACME INC", dev.Entity)
+
+	// developers that already had the new value are left alone
+	dev, err = store.GetDeveloper("u2")
+	require.NoError(t, err)
+	assert.Equal(t, "ACME", dev.Entity)
+
+	list, err = store.GetSubstitutions()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	sub, err = store.DeleteSub("entity", "ACME INC")
+	require.NoError(t, err)
+	assert.Nil(t, sub)
+}
+
+func TestSaveAndApplyDeveloperSub_Edit(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "u1", FullName: "Jon Doe"},
+	}))
+
+	_, err := store.SaveAndApplyDeveloperSub("full_name", "Jon Doe", "Jonn Doe")
+	require.NoError(t, err)
+
+	sub, err := store.SaveAndApplyDeveloperSub("full_name", "Jon Doe", "John Doe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), sub.Records)
+
+	dev, err := store.GetDeveloper("u1")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", dev.FullName)
+
+	list, err := store.GetSubstitutions()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "John Doe", list[0].New)
+}
+
+func TestSaveAndApplyDeveloperSub_Username(t *testing.T) {
+	store := setupTestDB(t)
+	require.NoError(t, store.SaveDevelopers([]*data.Developer{
+		{Username: "jdoe"}, {Username: "jdoe-work"},
+	}))
+
+	for _, e := range []struct{ user, date string }{
+		{"jdoe", "2025-01-01"}, {"jdoe-work", "2025-01-01"}, {"jdoe-work", "2025-01-02"},
+	} {
+		_, err := store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels)
+			VALUES ('org1', 'repo1', ?, 'pr', ?, 'http://x', '', '')`, e.user, e.date)
+		require.NoError(t, err)
+	}
+
+	for _, q := range []string{
+		`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+			VALUES (1, 'org1', 'repo1', 1, 'jdoe-work', 'APPROVED', '2025-01-02T10:00:00Z')`,
+		`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at)
+			VALUES ('org1', 'repo1', 1, 'pr', 'merged', 'jdoe-work', '', '2025-01-02T11:00:00Z')`,
+		`INSERT INTO discussion (url, org, repo, number, username, state, created_at, updated_at)
+			VALUES ('http://d/1', 'org1', 'repo1', 1, 'jdoe-work', 'open', '2025-01-02T10:00:00Z', '2025-01-02T10:00:00Z')`,
+		`INSERT INTO discussion_comment (url, org, repo, number, username, created_at, updated_at)
+			VALUES ('http://d/1#c1', 'org1', 'repo1', 1, 'jdoe-work', '2025-01-02T10:00:00Z', '2025-01-02T10:00:00Z')`,
+		`INSERT INTO org_member (org, username, role, updated_at) VALUES
+			('org1', 'jdoe', 'member', '2025-01-01T00:00:00Z'),
+			('org1', 'jdoe-work', 'member', '2025-01-01T00:00:00Z')`,
+		`INSERT INTO cohort (name, description, min_merged_prs, months, entity, updated_at)
+			VALUES ('core', '', 0, 0, '', '2025-01-01T00:00:00Z')`,
+		`INSERT INTO cohort_member (cohort, username, static) VALUES ('core', 'jdoe-work', 1)`,
+	} {
+		_, err := store.db.Exec(q)
+		require.NoError(t, err)
+	}
+
+	count := func(q string) int {
+		var n int
+		require.NoError(t, store.db.QueryRow(q).Scan(&n))
+		return n
+	}
+
+	_, err := store.SaveAndApplyDeveloperSub("username", "jdoe-work", "unknown")
+	assert.Error(t, err)
+
+	// the event and org membership jdoe already has are merged into jdoe's rows
+	sub, err := store.SaveAndApplyDeveloperSub("username", "jdoe-work", "jdoe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), sub.Records)
+
+	assert.Equal(t, 2, count(`SELECT COUNT(*) FROM event WHERE username = 'jdoe'`))
+	assert.Zero(t, count(`SELECT COUNT(*) FROM event WHERE username = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM pr_review WHERE username = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM timeline_event WHERE actor = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM discussion WHERE username = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM discussion_comment WHERE username = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM org_member`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM cohort_member WHERE username = 'jdoe' AND static = 1`))
+
+	sub, err = store.DeleteSub("username", "jdoe-work")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), sub.Records)
+
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM event WHERE username = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM event WHERE username = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM pr_review WHERE username = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM timeline_event WHERE actor = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM discussion WHERE username = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM discussion_comment WHERE username = 'jdoe-work'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM org_member WHERE username = 'jdoe'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM cohort_member WHERE username = 'jdoe-work' AND static = 1`))
+	assert.Zero(t, count(`SELECT COUNT(*) FROM cohort_member WHERE username = 'jdoe'`))
+}
+
+func TestSubstitutions_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetSubstitutions()
+	assert.Error(t, err)
+	_, err = s.DeleteSub("entity", "old")
+	assert.Error(t, err)
+}
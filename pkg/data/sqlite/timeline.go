@@ -0,0 +1,380 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/mchmarny/devpulse/pkg/data/ghutil"
+)
+
+const (
+	labelDwellLimit = 20
+
+	insertTimelineEventSQL = `INSERT OR IGNORE INTO timeline_event (
+			org, repo, number, item_type, kind, actor, subject, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// timelineAuthorFilterSQL scopes timeline entries (alias "t") to items
+	// opened by developers matching the entity and developer filters.
+	// Args: entity, include bots, cohort.
+	timelineAuthorFilterSQL = `AND EXISTS (
+			SELECT 1 FROM event ie
+			JOIN developer d ON ie.username = d.username
+			WHERE ie.org = t.org AND ie.repo = t.repo AND ie.number = t.number
+			  AND ie.type = t.item_type
+			  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+			  ` + developerFilterSQL + `
+		)`
+
+	// selectTimeToTriageSQL measures the time from issue creation to the first
+	// label or assignment made by someone other than the author, so labels
+	// applied by issue templates do not count as triage.
+	selectTimeToTriageSQL = `WITH issues AS (
+		SELECT e.org, e.repo, e.number, e.username, MIN(e.created_at) AS created_at
+		FROM event e
+		JOIN developer d ON e.username = d.username
+		WHERE e.type = 'issue'
+		  AND e.number IS NOT NULL
+		  AND e.created_at IS NOT NULL
+		  AND e.url NOT LIKE '%/pull/%'
+		  AND e.org = COALESCE(?, e.org)
+		  AND e.repo = COALESCE(?, e.repo)
+		  AND IFNULL(d.entity, '') = COALESCE(?, IFNULL(d.entity, ''))
+		  AND e.created_at >= ?
+		  ` + developerFilterSQL + `
+		GROUP BY e.org, e.repo, e.number, e.username
+	),
+	triage AS (
+		SELECT
+			substr(i.created_at, 1, 7) AS month,
+			i.created_at,
+			(SELECT MIN(t.created_at) FROM timeline_event t
+				WHERE t.org = i.org AND t.repo = i.repo AND t.number = i.number
+				  AND t.kind IN ('labeled', 'assigned')
+				  AND t.actor != i.username) AS triaged_at
+		FROM issues i
+	)
+	SELECT
+		month,
+		COUNT(triaged_at) AS cnt,
+		COALESCE(AVG((julianday(triaged_at) - julianday(created_at)) * 24), 0) AS avg_hours,
+		SUM(CASE WHEN triaged_at IS NULL THEN 1 ELSE 0 END) AS untriaged
+	FROM triage
+	GROUP BY month
+	ORDER BY month
+	`
+
+	selectReopenRateSQL = `SELECT
+			substr(t.created_at, 1, 7) AS month,
+			COUNT(DISTINCT CASE WHEN t.kind = 'closed' THEN t.org || '/' || t.repo || '#' || t.number END) AS closed,
+			COUNT(DISTINCT CASE WHEN t.kind = 'reopened' THEN t.org || '/' || t.repo || '#' || t.number END) AS reopened
+		FROM timeline_event t
+		WHERE t.kind IN ('closed', 'reopened')
+		  AND t.org = COALESCE(?, t.org)
+		  AND t.repo = COALESCE(?, t.repo)
+		  AND t.created_at >= ?
+		  ` + timelineAuthorFilterSQL + `
+		GROUP BY month
+		ORDER BY month
+	`
+
+	// selectLabelDwellSQL pairs each labeled entry with the next removal of
+	// the same label from the same item.
+	selectLabelDwellSQL = `WITH applied AS (
+		SELECT
+			t.subject AS label,
+			t.created_at AS labeled_at,
+			(SELECT MIN(u.created_at) FROM timeline_event u
+				WHERE u.org = t.org AND u.repo = t.repo AND u.number = t.number
+				  AND u.kind = 'unlabeled'
+				  AND u.subject = t.subject
+				  AND u.created_at >= t.created_at) AS removed_at
+		FROM timeline_event t
+		WHERE t.kind = 'labeled'
+		  AND t.subject != ''
+		  AND t.org = COALESCE(?, t.org)
+		  AND t.repo = COALESCE(?, t.repo)
+		  AND t.created_at >= ?
+		  AND t.created_at < ?
+		  ` + timelineAuthorFilterSQL + `
+	)
+	SELECT
+		label,
+		COUNT(removed_at) AS removed,
+		COALESCE(AVG((julianday(removed_at) - julianday(labeled_at)) * 24), 0) AS avg_hours,
+		SUM(CASE WHEN removed_at IS NULL THEN 1 ELSE 0 END) AS active
+	FROM applied
+	GROUP BY label
+	ORDER BY COUNT(*) DESC, label
+	LIMIT ?
+	`
+
+	// selectReviewRequestLatencySQL measures the time from a review request
+	// to the first review submitted by the requested reviewer.
+	selectReviewRequestLatencySQL = `WITH requests AS (
+		SELECT t.org, t.repo, t.number, t.subject AS reviewer, MIN(t.created_at) AS requested_at
+		FROM timeline_event t
+		WHERE t.kind = 'review_requested'
+		  AND t.item_type = 'pr'
+		  AND t.subject != ''
+		  AND t.org = COALESCE(?, t.org)
+		  AND t.repo = COALESCE(?, t.repo)
+		  AND t.created_at >= ?
+		  ` + timelineAuthorFilterSQL + `
+		GROUP BY t.org, t.repo, t.number, t.subject
+	),
+	latency AS (
+		SELECT
+			substr(r.requested_at, 1, 7) AS month,
+			(julianday(MIN(rev.submitted_at)) - julianday(r.requested_at)) * 24 AS hours
+		FROM requests r
+		JOIN pr_review rev ON rev.org = r.org AND rev.repo = r.repo AND rev.number = r.number
+			AND rev.username = r.reviewer
+			AND rev.submitted_at >= r.requested_at
+		GROUP BY r.org, r.repo, r.number, r.reviewer
+	)
+	SELECT month, hours
+	FROM latency
+	ORDER BY month
+	`
+)
+
+// importTimeline imports the timeline of a single issue or PR. Only the kinds
+// listed in data.TimelineKinds are kept.
+func (e *eventImporter) importTimeline(ctx context.Context, number int, itemType string) error {
+	if number == 0 {
+		return nil
+	}
+
+	opts := &github.ListOptions{PerPage: pageSizeDefault}
+
+	for {
+		items, resp, err := e.client.Issues.ListIssueTimeline(ctx, e.owner, e.repo, number, opts)
+		if err != nil {
+			return fmt.Errorf("error listing timeline for #%d: %w", number, err)
+		}
+		if err := ghutil.CheckRateLimit(ctx, resp); err != nil {
+			return err
+		}
+
+		for i := range items {
+			if ev := mapTimelineEvent(items[i]); ev != nil {
+				ev.Org = e.owner
+				ev.Repo = e.repo
+				ev.Number = number
+				ev.ItemType = itemType
+				if err := e.addTimeline(ev); err != nil {
+					return fmt.Errorf("error adding timeline event: %w", err)
+				}
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return nil
+}
+
+// mapTimelineEvent converts a GitHub timeline entry, returning nil for kinds
+// that are not tracked.
+func mapTimelineEvent(t *github.Timeline) *data.TimelineEvent {
+	if t == nil || t.CreatedAt == nil || !data.Contains(data.TimelineKinds, t.GetEvent()) {
+		return nil
+	}
+
+	ev := &data.TimelineEvent{
+		Kind:      t.GetEvent(),
+		Actor:     t.GetActor().GetLogin(),
+		CreatedAt: *timestampStr(t.CreatedAt),
+	}
+
+	switch ev.Kind {
+	case data.TimelineLabeled, data.TimelineUnlabeled:
+		ev.Subject = t.GetLabel().GetName()
+	case data.TimelineAssigned:
+		ev.Subject = t.GetAssignee().GetLogin()
+	case data.TimelineMilestoned:
+		ev.Subject = t.GetMilestone().GetTitle()
+	case data.TimelineCrossReferenced:
+		ev.Subject = t.GetSource().GetIssue().GetHTMLURL()
+		if ev.Actor == "" {
+			ev.Actor = t.GetSource().GetActor().GetLogin()
+		}
+	case data.TimelineReviewRequested:
+		ev.Subject = t.GetReviewer().GetLogin()
+		if ev.Subject == "" {
+			ev.Subject = t.GetRequestedTeam().GetSlug()
+		}
+	}
+
+	return ev
+}
+
+// GetTimeToTriage returns the monthly average hours from issue creation to
+// the first label or assignment, and the number of issues never triaged.
+func (s *Store) GetTimeToTriage(f *data.InsightsFilter) (*data.TriageSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectTimeToTriageSQL), f.Org, f.Repo, f.Entity, since, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time to triage: %w", err)
+	}
+	defer rows.Close()
+
+	sr := &data.TriageSeries{
+		Months:    make([]string, 0),
+		Count:     make([]int, 0),
+		AvgHours:  make([]float64, 0),
+		Untriaged: make([]int, 0),
+	}
+
+	for rows.Next() {
+		var month string
+		var cnt, untriaged int
+		var avgHours float64
+		if err := rows.Scan(&month, &cnt, &avgHours, &untriaged); err != nil {
+			return nil, fmt.Errorf("failed to scan time to triage row: %w", err)
+		}
+		sr.Months = append(sr.Months, month)
+		sr.Count = append(sr.Count, cnt)
+		sr.AvgHours = append(sr.AvgHours, avgHours)
+		sr.Untriaged = append(sr.Untriaged, untriaged)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	br.align(sr)
+	return sr, nil
+}
+
+// GetReopenRate returns the monthly share of closed issues and PRs that were
+// reopened.
+func (s *Store) GetReopenRate(f *data.InsightsFilter) (*data.ReopenSeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectReopenRateSQL), f.Org, f.Repo, since, f.Entity, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reopen rate: %w", err)
+	}
+	defer rows.Close()
+
+	sr := &data.ReopenSeries{
+		Months:   make([]string, 0),
+		Closed:   make([]int, 0),
+		Reopened: make([]int, 0),
+		Rate:     make([]float64, 0),
+	}
+
+	for rows.Next() {
+		var month string
+		var closed, reopened int
+		if err := rows.Scan(&month, &closed, &reopened); err != nil {
+			return nil, fmt.Errorf("failed to scan reopen rate row: %w", err)
+		}
+		sr.Months = append(sr.Months, month)
+		sr.Closed = append(sr.Closed, closed)
+		sr.Reopened = append(sr.Reopened, reopened)
+
+		var rate float64
+		if closed > 0 {
+			rate = float64(reopened) / float64(closed) * 100
+		}
+		sr.Rate = append(sr.Rate, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	br.align(sr)
+	return sr, nil
+}
+
+// GetLabelDwell returns how long the most used labels stay on issues and PRs
+// before they are removed.
+func (s *Store) GetLabelDwell(f *data.InsightsFilter) ([]*data.LabelDwell, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(selectLabelDwellSQL, f.Org, f.Repo, since, br.until(), f.Entity, f.IncludeBots, f.Cohort, labelDwellLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query label dwell: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*data.LabelDwell, 0)
+	for rows.Next() {
+		l := &data.LabelDwell{}
+		if err := rows.Scan(&l.Label, &l.Removed, &l.AvgHours, &l.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan label dwell row: %w", err)
+		}
+		list = append(list, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetReviewRequestLatency returns monthly statistics of the hours from a
+// review request to the first review by the requested reviewer.
+func (s *Store) GetReviewRequestLatency(f *data.InsightsFilter) (*data.ReviewLatencySeries, error) {
+	if s.db == nil {
+		return nil, data.ErrDBNotInitialized
+	}
+
+	br, err := newBucketRange(f)
+	if err != nil {
+		return nil, err
+	}
+	since := br.since()
+
+	rows, err := s.db.Query(br.query(selectReviewRequestLatencySQL), f.Org, f.Repo, since, f.Entity, f.IncludeBots, f.Cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review request latency: %w", err)
+	}
+	defer rows.Close()
+
+	samples, err := scanDurationSamples(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := reviewLatencySeries(samples, nil)
+	br.align(sr)
+	return sr, nil
+}
@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/mchmarny/devpulse/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapTimelineEvent(t *testing.T) {
+	ts := &github.Timestamp{Time: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	actor := &github.User{Login: github.Ptr("alice")}
+
+	ev := mapTimelineEvent(&github.Timeline{Event: github.Ptr("labeled"), Actor: actor, CreatedAt: ts,
+		Label: &github.Label{Name: github.Ptr("bug")}})
+	require.NotNil(t, ev)
+	assert.Equal(t, data.TimelineLabeled, ev.Kind)
+	assert.Equal(t, "alice", ev.Actor)
+	assert.Equal(t, "bug", ev.Subject)
+	assert.Equal(t, "2025-03-01T10:00:00Z", ev.CreatedAt)
+
+	ev = mapTimelineEvent(&github.Timeline{Event: github.Ptr("review_requested"), Actor: actor, CreatedAt: ts,
+		RequestedTeam: &github.Team{Slug: github.Ptr("maintainers")}})
+	require.NotNil(t, ev)
+	assert.Equal(t, "maintainers", ev.Subject)
+
+	ev = mapTimelineEvent(&github.Timeline{Event: github.Ptr("cross-referenced"), CreatedAt: ts,
+		Source: &github.Source{Actor: actor, Issue: &github.Issue{HTMLURL: github.Ptr("https://github.com/o/r/pull/2")}}})
+	require.NotNil(t, ev)
+	assert.Equal(t, "alice", ev.Actor)
+	assert.Equal(t, "https://github.com/o/r/pull/2", ev.Subject)
+
+	ev = mapTimelineEvent(&github.Timeline{Event: github.Ptr("merged"), Actor: actor, CreatedAt: ts})
+	require.NotNil(t, ev)
+	assert.Equal(t, data.TimelineMerged, ev.Kind)
+	assert.Equal(t, "alice", ev.Actor)
+
+	assert.Nil(t, mapTimelineEvent(&github.Timeline{Event: github.Ptr("subscribed"), CreatedAt: ts}))
+	assert.Nil(t, mapTimelineEvent(&github.Timeline{Event: github.Ptr("labeled")}))
+	assert.Nil(t, mapTimelineEvent(nil))
+}
+
+func insertTimelineTestData(t *testing.T, store *Store) string {
+	t.Helper()
+
+	_, err := store.db.Exec(`INSERT INTO developer (username, full_name) VALUES
+		('alice', 'Alice'), ('bob', 'Bob')`)
+	require.NoError(t, err)
+
+	base := time.Now().UTC().AddDate(0, -1, 0).Truncate(24 * time.Hour)
+	at := func(h int) string { return base.Add(time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	day := func(d int) string { return base.AddDate(0, 0, d).Format("2006-01-02") }
+
+	// issue 1: labeled by its author (template), then by bob after 4h
+	// issue 2: never triaged
+	// PR 3: review requested from bob, reviewed 2h later
+	_, err = store.db.Exec(`INSERT INTO event (org, repo, username, type, date, url, mentions, labels, state, number, created_at) VALUES
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/1', '', '', 'open', 1, ?),
+		('org1', 'repo1', 'alice', 'issue', ?, 'http://i/issues/2', '', '', 'open', 2, ?),
+		('org1', 'repo1', 'alice', 'pr', ?, 'http://i/pull/3', '', '', 'open', 3, ?),
+		('org1', 'repo1', 'bob', 'pr_review', ?, 'http://i/pull/3#pullrequestreview-1', '', '', 'APPROVED', 3, ?)`,
+		day(0), at(0), day(1), at(0), day(0), at(0), day(0), at(12))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO pr_review (id, org, repo, number, username, state, submitted_at)
+		VALUES (1, 'org1', 'repo1', 3, 'bob', 'APPROVED', ?)`, at(12))
+	require.NoError(t, err)
+
+	_, err = store.db.Exec(`INSERT INTO timeline_event (org, repo, number, item_type, kind, actor, subject, created_at) VALUES
+		('org1', 'repo1', 1, 'issue', 'labeled', 'alice', 'needs-triage', ?),
+		('org1', 'repo1', 1, 'issue', 'labeled', 'bob', 'bug', ?),
+		('org1', 'repo1', 1, 'issue', 'unlabeled', 'bob', 'needs-triage', ?),
+		('org1', 'repo1', 1, 'issue', 'closed', 'bob', '', ?),
+		('org1', 'repo1', 1, 'issue', 'reopened', 'alice', '', ?),
+		('org1', 'repo1', 2, 'issue', 'closed', 'bob', '', ?),
+		('org1', 'repo1', 3, 'pr', 'review_requested', 'alice', 'bob', ?)`,
+		at(0), at(4), at(6), at(7), at(8), at(9), at(10))
+	require.NoError(t, err)
+
+	return base.Format("2006-01")
+}
+
+func TestGetTimeToTriage_NilDB(t *testing.T) {
+	s := &Store{db: nil}
+	_, err := s.GetTimeToTriage(&data.InsightsFilter{Months: 6})
+	assert.Error(t, err)
+}
+
+func TestGetTimeToTriage_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	month := insertTimelineTestData(t, store)
+
+	series, err := store.GetTimeToTriage(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, month, series.Months[0])
+	assert.Equal(t, 1, series.Count[0])
+	assert.InDelta(t, 4.0, series.AvgHours[0], 0.01)
+	assert.Equal(t, 1, series.Untriaged[0])
+}
+
+func TestGetReopenRate_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertTimelineTestData(t, store)
+
+	series, err := store.GetReopenRate(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, 2, series.Closed[0])
+	assert.Equal(t, 1, series.Reopened[0])
+	assert.InDelta(t, 50.0, series.Rate[0], 0.01)
+
+	// no items are authored by developers of an unknown entity
+	entity := "NONE"
+	series, err = store.GetReopenRate(&data.InsightsFilter{Months: 6, Entity: &entity})
+	require.NoError(t, err)
+	assert.Empty(t, series.Months)
+}
+
+func TestGetLabelDwell_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertTimelineTestData(t, store)
+
+	list, err := store.GetLabelDwell(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	byLabel := make(map[string]*data.LabelDwell)
+	for _, l := range list {
+		byLabel[l.Label] = l
+	}
+	require.Contains(t, byLabel, "needs-triage")
+	assert.Equal(t, 1, byLabel["needs-triage"].Removed)
+	assert.InDelta(t, 6.0, byLabel["needs-triage"].AvgHours, 0.01)
+	require.Contains(t, byLabel, "bug")
+	assert.Equal(t, 1, byLabel["bug"].Active)
+}
+
+func TestGetReviewRequestLatency_WithData(t *testing.T) {
+	store := setupTestDB(t)
+	insertTimelineTestData(t, store)
+
+	series, err := store.GetReviewRequestLatency(&data.InsightsFilter{Months: 6})
+	require.NoError(t, err)
+	require.Len(t, series.Months, 1)
+	assert.Equal(t, 1, series.Count[0])
+	assert.InDelta(t, 2.0, series.AvgHours[0], 0.01)
+}
+
+func TestTimelineInsights_EmptyDB(t *testing.T) {
+	store := setupTestDB(t)
+	f := &data.InsightsFilter{Months: 6}
+
+	triage, err := store.GetTimeToTriage(f)
+	require.NoError(t, err)
+	assert.Empty(t, triage.Months)
+
+	reopen, err := store.GetReopenRate(f)
+	require.NoError(t, err)
+	assert.Empty(t, reopen.Months)
+
+	dwell, err := store.GetLabelDwell(f)
+	require.NoError(t, err)
+	assert.Empty(t, dwell)
+
+	latency, err := store.GetReviewRequestLatency(f)
+	require.NoError(t, err)
+	assert.Empty(t, latency.Months)
+}
@@ -28,6 +28,25 @@ type DeleteStore interface {
 type SubstitutionStore interface {
 	SaveAndApplyDeveloperSub(prop, old, new string) (*Substitution, error)
 	ApplySubstitutions() ([]*Substitution, error)
+	GetSubstitutions() ([]*Substitution, error)
+	DeleteSub(prop, old string) (*Substitution, error)
+}
+
+// BotStore manages bot classification overrides and detection.
+type BotStore interface {
+	SaveBotOverride(username string, isBot bool) (*BotOverride, error)
+	DeleteBotOverride(username string) (*BotOverride, error)
+	GetBotOverrides() ([]*BotOverride, error)
+	DetectBots() ([]*BotCandidate, error)
+}
+
+// CohortStore manages named contributor cohorts.
+type CohortStore interface {
+	SaveCohort(c *Cohort) (*Cohort, error)
+	DeleteCohort(name string) (*Cohort, error)
+	GetCohort(name string) (*Cohort, error)
+	GetCohorts() ([]*Cohort, error)
+	RefreshCohorts() ([]*Cohort, error)
 }
 
 // EntityStore manages entity lookups and queries.
@@ -36,6 +55,7 @@ type EntityStore interface {
 	GetEntity(val string) (*EntityResult, error)
 	QueryEntities(val string, limit int) ([]*CountedItem, error)
 	CleanEntities() error
+	NormalizeEntities(dryRun bool) ([]*EntityChange, error)
 }
 
 // RepoStore manages repository lookups.
@@ -50,6 +70,8 @@ type OrgStore interface {
 	GetEntityPercentages(entity, org, repo *string, ex []string, months int) ([]*CountedItem, error)
 	SearchDeveloperUsernames(query string, org, repo *string, months, limit int) ([]string, error)
 	GetOrgLike(query string, limit int) ([]*ListItem, error)
+	ImportOrgMembers(ctx context.Context, token, org string) (int, error)
+	ImportAllOrgMembers(ctx context.Context, token string) error
 }
 
 // DeveloperStore manages developer records.
@@ -67,7 +89,7 @@ type DeveloperStore interface {
 type QueryStore interface {
 	SearchEvents(q *EventSearchCriteria) ([]*EventDetails, error)
 	GetMinEventDate(org, repo *string) (string, error)
-	GetEventTypeSeries(org, repo, entity *string, months int) (*EventTypeSeries, error)
+	GetEventTypeSeries(f *InsightsFilter) (*EventTypeSeries, error)
 }
 
 // EventStore manages event imports.
@@ -78,38 +100,60 @@ type EventStore interface {
 
 // InsightsStore provides analytics and insights queries.
 type InsightsStore interface {
-	GetInsightsSummary(org, repo, entity *string, months int) (*InsightsSummary, error)
-	GetDailyActivity(org, repo, entity *string, months int) (*DailyActivitySeries, error)
-	GetContributorRetention(org, repo, entity *string, months int) (*RetentionSeries, error)
-	GetPRReviewRatio(org, repo, entity *string, months int) (*PRReviewRatioSeries, error)
-	GetChangeFailureRate(org, repo, entity *string, months int) (*ChangeFailureRateSeries, error)
-	GetReviewLatency(org, repo, entity *string, months int) (*ReviewLatencySeries, error)
-	GetTimeToMerge(org, repo, entity *string, months int) (*VelocitySeries, error)
-	GetTimeToClose(org, repo, entity *string, months int) (*VelocitySeries, error)
-	GetTimeToRestoreBugs(org, repo, entity *string, months int) (*VelocitySeries, error)
-	GetPRSizeDistribution(org, repo, entity *string, months int) (*PRSizeSeries, error)
-	GetForksAndActivity(org, repo, entity *string, months int) (*ForksAndActivitySeries, error)
-	GetContributorFunnel(org, repo, entity *string, months int) (*ContributorFunnelSeries, error)
-	GetContributorMomentum(org, repo, entity *string, months int) (*MomentumSeries, error)
-	GetContributorProfile(username string, org, repo, entity *string, months int) (*ContributorProfileSeries, error)
-	GetIssueOpenCloseRatio(org, repo, entity *string, months int) (*IssueRatioSeries, error)
-	GetTimeToFirstResponse(org, repo, entity *string, months int) (*FirstResponseSeries, error)
+	GetInsightsSummary(f *InsightsFilter) (*InsightsSummary, error)
+	GetDailyActivity(f *InsightsFilter) (*DailyActivitySeries, error)
+	GetContributorRetention(f *InsightsFilter) (*RetentionSeries, error)
+	GetCohortRetention(f *InsightsFilter, split string) (*CohortRetention, error)
+	GetContributorsAtRisk(f *InsightsFilter, limit int) (*ContributorsAtRisk, error)
+	GetPRReviewRatio(f *InsightsFilter) (*PRReviewRatioSeries, error)
+	GetChangeFailureRate(f *InsightsFilter) (*ChangeFailureRateSeries, error)
+	GetChangeFailures(f *InsightsFilter, bucket string) ([]*ChangeFailure, error)
+	GetDORASummary(f *InsightsFilter) (*DORASummary, error)
+	GetReviewLatency(f *InsightsFilter) (*ReviewLatencySeries, error)
+	GetTimeToMerge(f *InsightsFilter) (*VelocitySeries, error)
+	GetTimeToClose(f *InsightsFilter) (*VelocitySeries, error)
+	GetTimeToRestoreBugs(f *InsightsFilter) (*VelocitySeries, error)
+	GetPRSizeDistribution(f *InsightsFilter) (*PRSizeSeries, error)
+	GetForksAndActivity(f *InsightsFilter) (*ForksAndActivitySeries, error)
+	GetContributorFunnel(f *InsightsFilter) (*ContributorFunnelSeries, error)
+	GetContributorMomentum(f *InsightsFilter) (*MomentumSeries, error)
+	GetContributorLifecycle(f *InsightsFilter, t *LifecycleThresholds) (*LifecycleSeries, error)
+	GetFactorTrend(f *InsightsFilter, window int) (*FactorSeries, error)
+	GetContributorProfile(username string, f *InsightsFilter) (*ContributorProfileSeries, error)
+	GetIssueOpenCloseRatio(f *InsightsFilter) (*IssueRatioSeries, error)
+	GetTimeToFirstResponse(f *InsightsFilter) (*FirstResponseSeries, error)
+	GetReviewDepth(f *InsightsFilter) (*ReviewDepthSeries, error)
+	GetApproverConcentration(f *InsightsFilter) (*ApproverConcentration, error)
+	GetTimeToTriage(f *InsightsFilter) (*TriageSeries, error)
+	GetReopenRate(f *InsightsFilter) (*ReopenSeries, error)
+	GetLabelDwell(f *InsightsFilter) ([]*LabelDwell, error)
+	GetReviewRequestLatency(f *InsightsFilter) (*ReviewLatencySeries, error)
+	GetIssueResolution(f *InsightsFilter) (*IssueResolutionSeries, error)
+	GetIssueFixLeadTime(f *InsightsFilter) (*VelocitySeries, error)
+	GetPRLifecycle(f *InsightsFilter, staleDays int) (*PRLifecycleSeries, error)
+	GetActivityHeatmap(f *InsightsFilter) (*ActivityHeatmap, error)
+	GetOffHoursShare(f *InsightsFilter) ([]*EntityOffHours, error)
+	GetMostWanted(f *InsightsFilter, limit int) ([]*WantedIssue, error)
+	GetDiscussionSeries(f *InsightsFilter) (*DiscussionSeries, error)
+	GetTopAnswerers(f *InsightsFilter, limit int) ([]*Answerer, error)
+	GetExternalContributionShare(f *InsightsFilter) (*ExternalContributionShare, error)
+	GetEntityDiversity(f *InsightsFilter, excludeUnaffiliated bool) (*EntityDiversitySeries, error)
 }
 
 // ReleaseStore manages release imports and queries.
 type ReleaseStore interface {
 	ImportReleases(ctx context.Context, token, owner, repo string) error
 	ImportAllReleases(ctx context.Context, token string) error
-	GetReleaseCadence(org, repo, entity *string, months int) (*ReleaseCadenceSeries, error)
-	GetReleaseDownloads(org, repo *string, months int) (*ReleaseDownloadsSeries, error)
-	GetReleaseDownloadsByTag(org, repo *string, months int) (*ReleaseDownloadsByTagSeries, error)
+	GetReleaseCadence(f *InsightsFilter) (*ReleaseCadenceSeries, error)
+	GetReleaseDownloads(f *InsightsFilter) (*ReleaseDownloadsSeries, error)
+	GetReleaseDownloadsByTag(f *InsightsFilter) (*ReleaseDownloadsByTagSeries, error)
 }
 
 // ContainerStore manages container version imports and queries.
 type ContainerStore interface {
 	ImportContainerVersions(ctx context.Context, token, org, repo string) error
 	ImportAllContainerVersions(ctx context.Context, token string) error
-	GetContainerActivity(org, repo *string, months int) (*ContainerActivitySeries, error)
+	GetContainerActivity(f *InsightsFilter) (*ContainerActivitySeries, error)
 }
 
 // RepoMetaStore manages repository metadata imports and queries.
@@ -117,14 +161,37 @@ type RepoMetaStore interface {
 	ImportRepoMeta(ctx context.Context, token, owner, repo string) error
 	ImportAllRepoMeta(ctx context.Context, token string) error
 	GetRepoMetas(org, repo *string) ([]*RepoMeta, error)
-	GetRepoOverview(org *string, months int) ([]*RepoOverview, error)
+	GetRepoOverview(f *InsightsFilter) ([]*RepoOverview, error)
 }
 
 // MetricHistoryStore manages repository metric history imports and queries.
 type MetricHistoryStore interface {
 	ImportRepoMetricHistory(ctx context.Context, token, owner, repo string) error
 	ImportAllRepoMetricHistory(ctx context.Context, token string) error
-	GetRepoMetricHistory(org, repo *string, months int) ([]*RepoMetricHistory, error)
+	GetRepoMetricHistory(f *InsightsFilter) ([]*RepoMetricHistory, error)
+}
+
+// BacklogStore reports on and snapshots the open issue and PR backlog.
+type BacklogStore interface {
+	GetBacklogAging(org, repo, entity *string) (*BacklogAging, error)
+	SnapshotBacklog(org, repo *string) (int, error)
+	GetBacklogHistory(f *InsightsFilter) (*BacklogHistory, error)
+}
+
+// AnomalyStore detects and reports anomalies in the metric series.
+type AnomalyStore interface {
+	DetectAnomalies(org, repo *string) (int, error)
+	GetAnomalies(f *InsightsFilter, metric string) ([]*Anomaly, error)
+}
+
+// MilestoneStore manages milestone and project board imports and reports
+// on planned work.
+type MilestoneStore interface {
+	ImportMilestones(ctx context.Context, token, owner, repo string) error
+	ImportAllMilestones(ctx context.Context, token string) error
+	GetMilestones(f *InsightsFilter) ([]*MilestoneProgress, error)
+	GetMilestoneBurnup(org, repo string, number int) (*MilestoneBurnup, error)
+	GetProjectStatus(org, repo *string) ([]*ProjectStatus, error)
 }
 
 // ReputationStore manages reputation scoring.
@@ -133,7 +200,7 @@ type ReputationStore interface {
 	ImportDeepReputation(ctx context.Context, tokenFn TokenFunc, limit, staleHours int, org, repo *string) (*DeepReputationResult, error)
 	GetOrComputeDeepReputation(ctx context.Context, token, username string) (*UserReputation, error)
 	ComputeDeepReputation(ctx context.Context, token, username string) (*UserReputation, error)
-	GetReputationDistribution(org, repo, entity *string, months int) (*ReputationDistribution, error)
+	GetReputationDistribution(f *InsightsFilter) (*ReputationDistribution, error)
 }
 
 // Store is the top-level interface composing all sub-interfaces.
@@ -142,6 +209,8 @@ type Store interface {
 	StateStore
 	DeleteStore
 	SubstitutionStore
+	BotStore
+	CohortStore
 	EntityStore
 	RepoStore
 	OrgStore
@@ -153,5 +222,8 @@ type Store interface {
 	ContainerStore
 	RepoMetaStore
 	MetricHistoryStore
+	BacklogStore
+	AnomalyStore
+	MilestoneStore
 	ReputationStore
 }
@@ -13,16 +13,132 @@ const (
 	DataFileName          string = "data.db"
 	EventAgeMonthsDefault int    = 6
 
-	EventTypePR           string = "pr"
-	EventTypePRReview     string = "pr_review"
-	EventTypeIssue        string = "issue"
-	EventTypeIssueComment string = "issue_comment"
-	EventTypeFork         string = "fork"
+	EventTypePR              string = "pr"
+	EventTypePRReview        string = "pr_review"
+	EventTypePRReviewComment string = "pr_review_comment"
+	EventTypeIssue           string = "issue"
+	EventTypeIssueComment    string = "issue_comment"
+	EventTypeFork            string = "fork"
+
+	// GitHub Discussions, imported through the GraphQL API.
+	EventTypeDiscussion        string = "discussion"
+	EventTypeDiscussionComment string = "discussion_comment"
+
+	// States of discussion events. Discussions in answerable (Q&A)
+	// categories are answered or unanswered, others open or closed. The
+	// accepted answer is the discussion comment in the answer state.
+	DiscussionStateAnswered   string = "answered"
+	DiscussionStateUnanswered string = "unanswered"
+	DiscussionStateOpen       string = "open"
+	DiscussionStateClosed     string = "closed"
+	DiscussionStateAnswer     string = "answer"
+
+	// PRStateMerged is stored as the state of merged PRs, which GitHub
+	// reports as closed.
+	PRStateMerged string = "merged"
+
+	// PRStaleDaysDefault is the inactivity after which an open PR counts
+	// as abandoned.
+	PRStaleDaysDefault int = 30
+
+	// Review verdicts stored as the state of pr_review events.
+	ReviewStateApproved         string = "APPROVED"
+	ReviewStateChangesRequested string = "CHANGES_REQUESTED"
+	ReviewStateCommented        string = "COMMENTED"
+	ReviewStateDismissed        string = "DISMISSED"
+
+	// Issue and PR timeline event kinds imported into timeline_event.
+	TimelineLabeled         string = "labeled"
+	TimelineUnlabeled       string = "unlabeled"
+	TimelineAssigned        string = "assigned"
+	TimelineMilestoned      string = "milestoned"
+	TimelineClosed          string = "closed"
+	TimelineReopened        string = "reopened"
+	TimelineCrossReferenced string = "cross-referenced"
+	TimelineReviewRequested string = "review_requested"
+	TimelineConnected       string = "connected"
+	TimelineReadyForReview  string = "ready_for_review"
+	TimelineMerged          string = "merged"
+
+	// Sources of PR to issue links.
+	LinkSourceKeyword   string = "keyword"
+	LinkSourceConnected string = "connected"
+
+	// IssueStateReasonNotPlanned is the close reason of won't-fix issues.
+	IssueStateReasonNotPlanned string = "not_planned"
+
+	// Milestone progress classifications.
+	MilestoneStatusOnTrack   string = "on_track"
+	MilestoneStatusAtRisk    string = "at_risk"
+	MilestoneStatusOverdue   string = "overdue"
+	MilestoneStatusNoDueDate string = "no_due_date"
+	MilestoneStatusComplete  string = "complete"
+
+	// MilestoneRateWindowDays is the recent window over which the close
+	// rate of a milestone is measured.
+	MilestoneRateWindowDays int = 28
+
+	// Org membership roles.
+	OrgRoleMember              string = "member"
+	OrgRoleOutsideCollaborator string = "outside_collaborator"
+
+	// Contributor classes relative to the org owning the repo.
+	ContributorClassMember       string = "member"
+	ContributorClassCollaborator string = "collaborator"
+	ContributorClassContributor  string = "contributor"
+	ContributorClassFirstTimer   string = "first_timer"
+
+	// Project (v2) board item types.
+	ProjectItemIssue      string = "issue"
+	ProjectItemPR         string = "pr"
+	ProjectItemDraftIssue string = "draft_issue"
+
+	// Time bucket granularities of insight series.
+	GranularityDay     string = "day"
+	GranularityWeek    string = "week"
+	GranularityMonth   string = "month"
+	GranularityQuarter string = "quarter"
+
+	// Business hours are weekdays from BusinessHourStart up to
+	// BusinessHourEnd, in UTC.
+	BusinessHourStart int = 9
+	BusinessHourEnd   int = 17
 )
 
+// TimelineKinds lists the timeline event kinds kept during import.
+var TimelineKinds = []string{
+	TimelineLabeled,
+	TimelineUnlabeled,
+	TimelineAssigned,
+	TimelineMilestoned,
+	TimelineClosed,
+	TimelineReopened,
+	TimelineCrossReferenced,
+	TimelineReviewRequested,
+	TimelineConnected,
+	TimelineReadyForReview,
+	TimelineMerged,
+}
+
+// Granularities lists the time buckets of insight series: ISO dates,
+// ISO weeks (2025-W03), months (2025-01) and quarters (2025-Q1).
+var Granularities = []string{
+	GranularityDay,
+	GranularityWeek,
+	GranularityMonth,
+	GranularityQuarter,
+}
+
+// BacklogBuckets are the age buckets of open issues and PRs.
+var BacklogBuckets = []string{"<7d", "<30d", "<90d", "<1y", "older"}
+
 // UpdatableProperties lists developer fields that can be substituted.
+// The username property maps all events of one account to another (person).
 var UpdatableProperties = []string{
 	"entity",
+	"full_name",
+	"email",
+	"username",
 }
 
 // ---------------------------------------------------------------------------
@@ -54,6 +170,14 @@ type DeleteResult struct {
 	RepoMeta      int64  `json:"repo_meta" yaml:"repo_meta"`
 	Releases      int64  `json:"releases" yaml:"releases"`
 	ReleaseAssets int64  `json:"release_assets" yaml:"release_assets"`
+	Timeline      int64  `json:"timeline" yaml:"timeline"`
+	Reviews       int64  `json:"reviews" yaml:"reviews"`
+	Discussions   int64  `json:"discussions" yaml:"discussions"`
+	Links         int64  `json:"links" yaml:"links"`
+	Engagement    int64  `json:"engagement" yaml:"engagement"`
+	Milestones    int64  `json:"milestones" yaml:"milestones"`
+	ProjectItems  int64  `json:"project_items" yaml:"project_items"`
+	Backlog       int64  `json:"backlog" yaml:"backlog"`
 	State         int64  `json:"state" yaml:"state"`
 }
 
@@ -123,6 +247,7 @@ type Developer struct {
 	AvatarURL     string `json:"avatar,omitempty" yaml:"avatar,omitempty"`
 	ProfileURL    string `json:"url,omitempty" yaml:"url,omitempty"`
 	Entity        string `json:"entity,omitempty" yaml:"entity,omitempty"`
+	IsBot         bool   `json:"is_bot,omitempty" yaml:"isBot,omitempty"`
 	Organizations []*Org `json:"organizations,omitempty" yaml:"organizations,omitempty"`
 }
 
@@ -131,6 +256,40 @@ type DeveloperListItem struct {
 	Entity   string `json:"entity,omitempty" yaml:"entity,omitempty"`
 }
 
+// BotOverride is a user-managed bot classification that takes precedence
+// over GitHub account type and detection heuristics.
+type BotOverride struct {
+	Username  string `json:"username" yaml:"username"`
+	IsBot     bool   `json:"is_bot" yaml:"isBot"`
+	UpdatedAt string `json:"updated_at" yaml:"updatedAt"`
+}
+
+// BotCandidate is a developer flagged as a bot by a detection heuristic.
+type BotCandidate struct {
+	Username string `json:"username" yaml:"username"`
+	Reason   string `json:"reason" yaml:"reason"`
+}
+
+// Cohort is a named group of developers used to scope insights. Members holds
+// the static usernames; Rules adds developers matching all of its criteria.
+type Cohort struct {
+	Name        string       `json:"name" yaml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Members     []string     `json:"members,omitempty" yaml:"members,omitempty"`
+	Rules       *CohortRules `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Matched     []string     `json:"matched,omitempty" yaml:"matched,omitempty"`
+	MemberCount int          `json:"member_count" yaml:"memberCount"`
+	UpdatedAt   string       `json:"updated_at,omitempty" yaml:"updatedAt,omitempty"`
+}
+
+// CohortRules selects developers dynamically. Zero values are ignored.
+type CohortRules struct {
+	// MinMergedPRs is the minimum number of merged PRs within Months.
+	MinMergedPRs int    `json:"min_merged_prs,omitempty" yaml:"minMergedPRs,omitempty"`
+	Months       int    `json:"months,omitempty" yaml:"months,omitempty"`
+	Entity       string `json:"entity,omitempty" yaml:"entity,omitempty"`
+}
+
 // ---------------------------------------------------------------------------
 // Event types
 // ---------------------------------------------------------------------------
@@ -154,6 +313,174 @@ type Event struct {
 	ChangedFiles *int    `json:"changed_files,omitempty" yaml:"changed_files,omitempty"`
 	Commits      *int    `json:"commits,omitempty" yaml:"commits,omitempty"`
 	Title        string  `json:"title,omitempty" yaml:"title,omitempty"`
+	StateReason  *string `json:"state_reason,omitempty" yaml:"stateReason,omitempty"`
+	Draft        *bool   `json:"draft,omitempty" yaml:"draft,omitempty"`
+	ReadyAt      *string `json:"ready_at,omitempty" yaml:"readyAt,omitempty"`
+	// EventAt is the full UTC time of the event; Date is its day key.
+	EventAt *string `json:"event_at,omitempty" yaml:"eventAt,omitempty"`
+	// AuthorAssociation is the author's GitHub association with the repo
+	// (e.g. MEMBER, CONTRIBUTOR, FIRST_TIME_CONTRIBUTOR).
+	AuthorAssociation *string `json:"author_association,omitempty" yaml:"authorAssociation,omitempty"`
+}
+
+// PRReview is a submitted review of a PR, keyed by its GitHub review ID.
+type PRReview struct {
+	ID          int64  `json:"id" yaml:"id"`
+	Org         string `json:"org" yaml:"org"`
+	Repo        string `json:"repo" yaml:"repo"`
+	Number      int    `json:"number" yaml:"number"`
+	Username    string `json:"username" yaml:"username"`
+	State       string `json:"state" yaml:"state"`
+	SubmittedAt string `json:"submitted_at" yaml:"submittedAt"`
+}
+
+// Discussion is a GitHub discussion keyed by its URL. AnswerURL is the URL
+// of the accepted answer comment, if any.
+type Discussion struct {
+	URL       string  `json:"url" yaml:"url"`
+	Org       string  `json:"org" yaml:"org"`
+	Repo      string  `json:"repo" yaml:"repo"`
+	Number    int     `json:"number" yaml:"number"`
+	Username  string  `json:"username" yaml:"username"`
+	State     string  `json:"state" yaml:"state"`
+	CreatedAt string  `json:"created_at" yaml:"createdAt"`
+	UpdatedAt string  `json:"updated_at" yaml:"updatedAt"`
+	AnswerURL *string `json:"answer_url,omitempty" yaml:"answerURL,omitempty"`
+}
+
+// DiscussionComment is a comment or reply on a GitHub discussion keyed by
+// its URL.
+type DiscussionComment struct {
+	URL       string `json:"url" yaml:"url"`
+	Org       string `json:"org" yaml:"org"`
+	Repo      string `json:"repo" yaml:"repo"`
+	Number    int    `json:"number" yaml:"number"`
+	Username  string `json:"username" yaml:"username"`
+	CreatedAt string `json:"created_at" yaml:"createdAt"`
+	UpdatedAt string `json:"updated_at" yaml:"updatedAt"`
+}
+
+// TimelineEvent is a single issue or PR timeline entry. Subject holds the
+// label, assignee, milestone, requested reviewer or cross-referencing URL.
+type TimelineEvent struct {
+	Org       string `json:"org" yaml:"org"`
+	Repo      string `json:"repo" yaml:"repo"`
+	Number    int    `json:"number" yaml:"number"`
+	ItemType  string `json:"item_type" yaml:"itemType"`
+	Kind      string `json:"kind" yaml:"kind"`
+	Actor     string `json:"actor,omitempty" yaml:"actor,omitempty"`
+	Subject   string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	CreatedAt string `json:"created_at" yaml:"createdAt"`
+}
+
+// IssueRef identifies an issue, possibly in another repository.
+type IssueRef struct {
+	Org    string `json:"org" yaml:"org"`
+	Repo   string `json:"repo" yaml:"repo"`
+	Number int    `json:"number" yaml:"number"`
+}
+
+// PRIssueLink records that a PR resolves an issue.
+type PRIssueLink struct {
+	Org      string    `json:"org" yaml:"org"`
+	Repo     string    `json:"repo" yaml:"repo"`
+	PRNumber int       `json:"pr_number" yaml:"prNumber"`
+	Issue    *IssueRef `json:"issue" yaml:"issue"`
+	Source   string    `json:"source" yaml:"source"`
+}
+
+// Engagement holds the latest comment count and reaction totals by type of
+// an issue or PR.
+type Engagement struct {
+	Org       string `json:"org" yaml:"org"`
+	Repo      string `json:"repo" yaml:"repo"`
+	Number    int    `json:"number" yaml:"number"`
+	ItemType  string `json:"item_type" yaml:"itemType"`
+	Comments  int    `json:"comments" yaml:"comments"`
+	Reactions int    `json:"reactions" yaml:"reactions"`
+	PlusOne   int    `json:"plus_one" yaml:"plusOne"`
+	MinusOne  int    `json:"minus_one" yaml:"minusOne"`
+	Laugh     int    `json:"laugh" yaml:"laugh"`
+	Hooray    int    `json:"hooray" yaml:"hooray"`
+	Confused  int    `json:"confused" yaml:"confused"`
+	Heart     int    `json:"heart" yaml:"heart"`
+	Rocket    int    `json:"rocket" yaml:"rocket"`
+	Eyes      int    `json:"eyes" yaml:"eyes"`
+}
+
+// WantedIssue is an open issue ranked by community demand.
+type WantedIssue struct {
+	Org        string `json:"org" yaml:"org"`
+	Repo       string `json:"repo" yaml:"repo"`
+	Number     int    `json:"number" yaml:"number"`
+	Title      string `json:"title" yaml:"title"`
+	URL        string `json:"url" yaml:"url"`
+	CreatedAt  string `json:"created_at" yaml:"createdAt"`
+	PlusOne    int    `json:"plus_one" yaml:"plusOne"`
+	Reactions  int    `json:"reactions" yaml:"reactions"`
+	Comments   int    `json:"comments" yaml:"comments"`
+	Commenters int    `json:"commenters" yaml:"commenters"`
+}
+
+// DiscussionSeries holds monthly discussion volume and answer metrics by
+// month of creation. AnswerRate is the percentage of answerable discussions
+// with an accepted answer.
+type DiscussionSeries struct {
+	Months          []string  `json:"months" yaml:"months"`
+	Opened          []int     `json:"opened" yaml:"opened"`
+	Answerable      []int     `json:"answerable" yaml:"answerable"`
+	Answered        []int     `json:"answered" yaml:"answered"`
+	AnswerRate      []float64 `json:"answer_rate" yaml:"answerRate"`
+	FirstReplyHours []float64 `json:"first_reply_hours" yaml:"firstReplyHours"`
+	AnswerHours     []float64 `json:"answer_hours" yaml:"answerHours"`
+}
+
+// Answerer is a developer ranked by accepted discussion answers.
+type Answerer struct {
+	Username string `json:"username" yaml:"username"`
+	Entity   string `json:"entity" yaml:"entity"`
+	Answers  int    `json:"answers" yaml:"answers"`
+	Replies  int    `json:"replies" yaml:"replies"`
+}
+
+// ContributionBreakdown is a monthly count of contributions by contributor
+// class. ExternalShare is the percentage not made by org members.
+type ContributionBreakdown struct {
+	Member        []int     `json:"member" yaml:"member"`
+	Collaborator  []int     `json:"collaborator" yaml:"collaborator"`
+	Contributor   []int     `json:"contributor" yaml:"contributor"`
+	FirstTimer    []int     `json:"first_timer" yaml:"firstTimer"`
+	ExternalShare []float64 `json:"external_share" yaml:"externalShare"`
+}
+
+// ExternalContributionShare splits PRs opened, PRs merged and reviews per
+// month between org members, outside collaborators, returning contributors
+// and first-timers.
+type ExternalContributionShare struct {
+	Months  []string               `json:"months" yaml:"months"`
+	Opened  *ContributionBreakdown `json:"opened" yaml:"opened"`
+	Merged  *ContributionBreakdown `json:"merged" yaml:"merged"`
+	Reviews *ContributionBreakdown `json:"reviews" yaml:"reviews"`
+}
+
+// DiversityIndex measures how evenly a kind of contribution is spread
+// across entities per month. HHI is the Herfindahl-Hirschman index of the
+// entity shares (0-10000, where 10000 is a single entity) and Entropy the
+// Shannon entropy in bits (0 for a single entity).
+type DiversityIndex struct {
+	Actions  []int     `json:"actions" yaml:"actions"`
+	Entities []int     `json:"entities" yaml:"entities"`
+	HHI      []float64 `json:"hhi" yaml:"hhi"`
+	Entropy  []float64 `json:"entropy" yaml:"entropy"`
+}
+
+// EntityDiversitySeries holds the monthly entity diversity of merged PRs
+// (by author), reviews and maintainership (approvals and merges).
+type EntityDiversitySeries struct {
+	Months         []string        `json:"months" yaml:"months"`
+	Code           *DiversityIndex `json:"code" yaml:"code"`
+	Reviews        *DiversityIndex `json:"reviews" yaml:"reviews"`
+	Maintainership *DiversityIndex `json:"maintainership" yaml:"maintainership"`
 }
 
 // ImportSummary contains per-repo import metadata.
@@ -169,14 +496,15 @@ type ImportSummary struct {
 // ---------------------------------------------------------------------------
 
 type EventTypeSeries struct {
-	Dates         []string  `json:"dates" yaml:"dates"`
-	PRs           []int     `json:"pr" yaml:"pr"`
-	PRReviews     []int     `json:"pr_review" yaml:"prReview"`
-	Issues        []int     `json:"issue" yaml:"issue"`
-	IssueComments []int     `json:"issue_comment" yaml:"issueComment"`
-	Forks         []int     `json:"fork" yaml:"fork"`
-	Total         []int     `json:"total" yaml:"total"`
-	Trend         []float32 `json:"trend" yaml:"trend"`
+	Dates            []string  `json:"dates" yaml:"dates"`
+	PRs              []int     `json:"pr" yaml:"pr"`
+	PRReviews        []int     `json:"pr_review" yaml:"prReview"`
+	PRReviewComments []int     `json:"pr_review_comment" yaml:"prReviewComment"`
+	Issues           []int     `json:"issue" yaml:"issue"`
+	IssueComments    []int     `json:"issue_comment" yaml:"issueComment"`
+	Forks            []int     `json:"fork" yaml:"fork"`
+	Total            []int     `json:"total" yaml:"total"`
+	Trend            []float32 `json:"trend" yaml:"trend"`
 }
 
 type EventDetails struct {
@@ -196,6 +524,13 @@ type EventSearchCriteria struct {
 	Label    *string `json:"label,omitempty" yaml:"label,omitempty"`
 	Page     int     `json:"page,omitempty" yaml:"page,omitempty"`
 	PageSize int     `json:"page_size,omitempty" yaml:"pageSize,omitempty"`
+	// IncludeBots disables the default exclusion of bot accounts.
+	IncludeBots bool `json:"include_bots,omitempty" yaml:"includeBots,omitempty"`
+	// Cohort limits results to members of the named cohort.
+	Cohort *string `json:"cohort,omitempty" yaml:"cohort,omitempty"`
+	// MinReactions limits results to issues and PRs with at least this many
+	// reactions.
+	MinReactions *int `json:"min_reactions,omitempty" yaml:"minReactions,omitempty"`
 }
 
 func (c EventSearchCriteria) String() string {
@@ -210,6 +545,29 @@ func (c EventSearchCriteria) String() string {
 // Insights series types
 // ---------------------------------------------------------------------------
 
+// InsightsFilter scopes insights queries. Nil pointers match any value.
+type InsightsFilter struct {
+	Org    *string `json:"org,omitempty" yaml:"org,omitempty"`
+	Repo   *string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	Entity *string `json:"entity,omitempty" yaml:"entity,omitempty"`
+	Months int     `json:"months,omitempty" yaml:"months,omitempty"`
+	// IncludeBots disables the default exclusion of bot accounts.
+	IncludeBots bool `json:"include_bots,omitempty" yaml:"includeBots,omitempty"`
+	// Cohort limits results to members of the named cohort.
+	Cohort *string `json:"cohort,omitempty" yaml:"cohort,omitempty"`
+	// IncludeDiscussions counts discussion activity toward contributor
+	// retention, momentum and funnel metrics.
+	IncludeDiscussions bool `json:"include_discussions,omitempty" yaml:"includeDiscussions,omitempty"`
+	// Granularity buckets series by day, week, month or quarter. When set,
+	// series hold every bucket of the range, with zeros where there is no
+	// data; when empty, series are monthly and hold only months with data.
+	Granularity string `json:"granularity,omitempty" yaml:"granularity,omitempty"`
+	// From and To bound the range by ISO date (inclusive). From replaces
+	// Months as the start of the range; To defaults to today.
+	From *string `json:"from,omitempty" yaml:"from,omitempty"`
+	To   *string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
 type InsightsSummary struct {
 	BusFactor    int    `json:"bus_factor" yaml:"busFactor"`
 	PonyFactor   int    `json:"pony_factor" yaml:"ponyFactor"`
@@ -225,10 +583,17 @@ type DailyActivitySeries struct {
 	Counts []int    `json:"counts"`
 }
 
+// VelocitySeries holds monthly duration statistics in days. Percentiles
+// are interpolated between the sorted samples of each month.
 type VelocitySeries struct {
 	Months  []string  `json:"months" yaml:"months"`
 	Count   []int     `json:"count" yaml:"count"`
 	AvgDays []float64 `json:"avg_days" yaml:"avgDays"`
+	P50Days []float64 `json:"p50_days" yaml:"p50Days"`
+	P75Days []float64 `json:"p75_days" yaml:"p75Days"`
+	P90Days []float64 `json:"p90_days" yaml:"p90Days"`
+	MinDays []float64 `json:"min_days" yaml:"minDays"`
+	MaxDays []float64 `json:"max_days" yaml:"maxDays"`
 }
 
 type IssueRatioSeries struct {
@@ -237,10 +602,29 @@ type IssueRatioSeries struct {
 	Closed []int    `json:"closed" yaml:"closed"`
 }
 
+// FirstResponseSeries holds monthly first response statistics in hours for
+// issues and PRs.
 type FirstResponseSeries struct {
-	Months   []string  `json:"months" yaml:"months"`
-	IssueAvg []float64 `json:"issue_avg" yaml:"issueAvg"`
-	PRAvg    []float64 `json:"pr_avg" yaml:"prAvg"`
+	Months     []string  `json:"months" yaml:"months"`
+	IssueCount []int     `json:"issue_count" yaml:"issueCount"`
+	IssueAvg   []float64 `json:"issue_avg" yaml:"issueAvg"`
+	IssueP50   []float64 `json:"issue_p50" yaml:"issueP50"`
+	IssueP75   []float64 `json:"issue_p75" yaml:"issueP75"`
+	IssueP90   []float64 `json:"issue_p90" yaml:"issueP90"`
+	IssueMin   []float64 `json:"issue_min" yaml:"issueMin"`
+	IssueMax   []float64 `json:"issue_max" yaml:"issueMax"`
+	PRCount    []int     `json:"pr_count" yaml:"prCount"`
+	PRAvg      []float64 `json:"pr_avg" yaml:"prAvg"`
+	PRP50      []float64 `json:"pr_p50" yaml:"prP50"`
+	PRP75      []float64 `json:"pr_p75" yaml:"prP75"`
+	PRP90      []float64 `json:"pr_p90" yaml:"prP90"`
+	PRMin      []float64 `json:"pr_min" yaml:"prMin"`
+	PRMax      []float64 `json:"pr_max" yaml:"prMax"`
+	// IssueBusinessAvg, PRBusinessAvg and their medians count only business hours.
+	IssueBusinessAvg []float64 `json:"issue_business_avg" yaml:"issueBusinessAvg"`
+	PRBusinessAvg    []float64 `json:"pr_business_avg" yaml:"prBusinessAvg"`
+	IssueBusinessP50 []float64 `json:"issue_business_p50" yaml:"issueBusinessP50"`
+	PRBusinessP50    []float64 `json:"pr_business_p50" yaml:"prBusinessP50"`
 }
 
 type RetentionSeries struct {
@@ -263,10 +647,193 @@ type ChangeFailureRateSeries struct {
 	Rate        []float64 `json:"rate" yaml:"rate"`
 }
 
+// ChangeFailure is an issue or PR counted as a failed change, with the
+// reason it matched the failure rules.
+type ChangeFailure struct {
+	Month     string `json:"month" yaml:"month"`
+	Org       string `json:"org" yaml:"org"`
+	Repo      string `json:"repo" yaml:"repo"`
+	Number    int    `json:"number" yaml:"number"`
+	Type      string `json:"type" yaml:"type"`
+	Title     string `json:"title" yaml:"title"`
+	URL       string `json:"url" yaml:"url"`
+	CreatedAt string `json:"created_at" yaml:"createdAt"`
+	// Rule is the scope of the applied rule: org/repo, org or *.
+	Rule   string `json:"rule" yaml:"rule"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// DORA performance tiers, from best to worst.
+const (
+	DORATierElite  = "elite"
+	DORATierHigh   = "high"
+	DORATierMedium = "medium"
+	DORATierLow    = "low"
+)
+
+// DORA trends of a key versus the previous period.
+const (
+	DORATrendImproving = "improving"
+	DORATrendDeclining = "declining"
+	DORATrendSteady    = "steady"
+)
+
+// DORAMetric is one of the four keys for a period and the previous period
+// of the same length. Tier and Trend are empty when there is no data.
+type DORAMetric struct {
+	Key          string  `json:"key" yaml:"key"`
+	Name         string  `json:"name" yaml:"name"`
+	Unit         string  `json:"unit" yaml:"unit"`
+	Value        float64 `json:"value" yaml:"value"`
+	Samples      int     `json:"samples" yaml:"samples"`
+	Tier         string  `json:"tier" yaml:"tier"`
+	Previous     float64 `json:"previous" yaml:"previous"`
+	PrevSamples  int     `json:"previous_samples" yaml:"previousSamples"`
+	PreviousTier string  `json:"previous_tier" yaml:"previousTier"`
+	Trend        string  `json:"trend" yaml:"trend"`
+}
+
+// DORASummary is the DORA scorecard of a period. Tier is the lowest tier
+// of the keys with data.
+type DORASummary struct {
+	From         string        `json:"from" yaml:"from"`
+	To           string        `json:"to" yaml:"to"`
+	PreviousFrom string        `json:"previous_from" yaml:"previousFrom"`
+	PreviousTo   string        `json:"previous_to" yaml:"previousTo"`
+	Tier         string        `json:"tier" yaml:"tier"`
+	Metrics      []*DORAMetric `json:"metrics" yaml:"metrics"`
+}
+
+// ReviewLatencySeries holds monthly latency statistics in hours.
 type ReviewLatencySeries struct {
 	Months   []string  `json:"months" yaml:"months"`
 	Count    []int     `json:"count" yaml:"count"`
 	AvgHours []float64 `json:"avg_hours" yaml:"avgHours"`
+	P50Hours []float64 `json:"p50_hours" yaml:"p50Hours"`
+	P75Hours []float64 `json:"p75_hours" yaml:"p75Hours"`
+	P90Hours []float64 `json:"p90_hours" yaml:"p90Hours"`
+	MinHours []float64 `json:"min_hours" yaml:"minHours"`
+	MaxHours []float64 `json:"max_hours" yaml:"maxHours"`
+	// BusinessAvgHours and BusinessP50Hours count only business hours.
+	BusinessAvgHours []float64 `json:"business_avg_hours,omitempty" yaml:"businessAvgHours,omitempty"`
+	BusinessP50Hours []float64 `json:"business_p50_hours,omitempty" yaml:"businessP50Hours,omitempty"`
+}
+
+// ActivityHeatmap counts events by UTC day of week (0 is Sunday) and hour.
+type ActivityHeatmap struct {
+	Days   []string `json:"days" yaml:"days"`
+	Counts [][]int  `json:"counts" yaml:"counts"`
+	Total  int      `json:"total" yaml:"total"`
+}
+
+// EntityOffHours is the share of an entity's events on weekends and on
+// weekdays outside business hours.
+type EntityOffHours struct {
+	Entity        string  `json:"entity" yaml:"entity"`
+	Events        int     `json:"events" yaml:"events"`
+	Weekend       int     `json:"weekend" yaml:"weekend"`
+	AfterHours    int     `json:"after_hours" yaml:"afterHours"`
+	WeekendPct    float64 `json:"weekend_pct" yaml:"weekendPct"`
+	AfterHoursPct float64 `json:"after_hours_pct" yaml:"afterHoursPct"`
+}
+
+// ReviewDepthSeries describes how thoroughly PRs opened each month were reviewed.
+type ReviewDepthSeries struct {
+	Months []string `json:"months" yaml:"months"`
+	// Reviewed is the number of PRs with at least one review.
+	Reviewed []int `json:"reviewed" yaml:"reviewed"`
+	// ReviewsPerPR is the average number of reviews per reviewed PR.
+	ReviewsPerPR []float64 `json:"reviews_per_pr" yaml:"reviewsPerPR"`
+	// ApprovalRounds is the average number of review rounds (change requests
+	// plus the approval) of approved PRs.
+	ApprovalRounds []float64 `json:"approval_rounds" yaml:"approvalRounds"`
+	// ChangeRequestRate is the percentage of reviewed PRs with at least one
+	// changes-requested review.
+	ChangeRequestRate []float64 `json:"change_request_rate" yaml:"changeRequestRate"`
+}
+
+// ApproverConcentration describes how PR approvals are spread across reviewers.
+type ApproverConcentration struct {
+	Approvals int `json:"approvals" yaml:"approvals"`
+	Approvers int `json:"approvers" yaml:"approvers"`
+	// Factor is the smallest number of approvers accounting for half of all approvals.
+	Factor int              `json:"factor" yaml:"factor"`
+	Top    []*ApproverShare `json:"top" yaml:"top"`
+}
+
+type ApproverShare struct {
+	Username  string  `json:"username" yaml:"username"`
+	Approvals int     `json:"approvals" yaml:"approvals"`
+	Percent   float64 `json:"percent" yaml:"percent"`
+}
+
+// TriageSeries is the time from issue creation to the first label or
+// assignment by someone other than the author, by month the issue was opened.
+type TriageSeries struct {
+	Months    []string  `json:"months" yaml:"months"`
+	Count     []int     `json:"count" yaml:"count"`
+	AvgHours  []float64 `json:"avg_hours" yaml:"avgHours"`
+	Untriaged []int     `json:"untriaged" yaml:"untriaged"`
+}
+
+// ReopenSeries is the share of closed issues and PRs that were reopened,
+// by month of the close or reopen.
+type ReopenSeries struct {
+	Months   []string  `json:"months" yaml:"months"`
+	Closed   []int     `json:"closed" yaml:"closed"`
+	Reopened []int     `json:"reopened" yaml:"reopened"`
+	Rate     []float64 `json:"rate" yaml:"rate"`
+}
+
+// IssueResolutionSeries splits closed issues by how they were resolved, by
+// month of close. Fixed issues are linked to a merged PR; declined issues were
+// closed as not planned or labeled stale/won't-fix without a fix.
+type IssueResolutionSeries struct {
+	Months   []string `json:"months" yaml:"months"`
+	Closed   []int    `json:"closed" yaml:"closed"`
+	Fixed    []int    `json:"fixed" yaml:"fixed"`
+	Declined []int    `json:"declined" yaml:"declined"`
+	// External is the number of fixed issues resolved by PRs from
+	// contributors who never approved a PR in the repo.
+	External  []int     `json:"external" yaml:"external"`
+	FixedRate []float64 `json:"fixed_rate" yaml:"fixedRate"`
+}
+
+// PRLifecycleSeries tracks PR outcomes by month the PR was opened. Closed
+// counts PRs closed without merge; Stale counts open PRs with no activity
+// for StaleDays. Both count as abandoned.
+type PRLifecycleSeries struct {
+	Months        []string    `json:"months" yaml:"months"`
+	Opened        []int       `json:"opened" yaml:"opened"`
+	Merged        []int       `json:"merged" yaml:"merged"`
+	Closed        []int       `json:"closed" yaml:"closed"`
+	Stale         []int       `json:"stale" yaml:"stale"`
+	MergeRate     []float64   `json:"merge_rate" yaml:"mergeRate"`
+	AbandonRate   []float64   `json:"abandon_rate" yaml:"abandonRate"`
+	Drafts        []int       `json:"drafts" yaml:"drafts"`
+	AvgDraftHours []float64   `json:"avg_draft_hours" yaml:"avgDraftHours"`
+	StaleDays     int         `json:"stale_days" yaml:"staleDays"`
+	FirstTime     *PROutcomes `json:"first_time" yaml:"firstTime"`
+	Returning     *PROutcomes `json:"returning" yaml:"returning"`
+}
+
+// PROutcomes totals PR outcomes for a group of authors over the window.
+type PROutcomes struct {
+	Opened      int     `json:"opened" yaml:"opened"`
+	Merged      int     `json:"merged" yaml:"merged"`
+	Abandoned   int     `json:"abandoned" yaml:"abandoned"`
+	MergeRate   float64 `json:"merge_rate" yaml:"mergeRate"`
+	AbandonRate float64 `json:"abandon_rate" yaml:"abandonRate"`
+}
+
+// LabelDwell is how long a label stays on issues and PRs before removal.
+type LabelDwell struct {
+	Label string `json:"label" yaml:"label"`
+	// Removed is the number of times the label was removed.
+	Removed  int     `json:"removed" yaml:"removed"`
+	AvgHours float64 `json:"avg_hours" yaml:"avgHours"`
+	// Active is the number of items still carrying the label.
+	Active int `json:"active" yaml:"active"`
 }
 
 type PRSizeSeries struct {
@@ -283,6 +850,115 @@ type MomentumSeries struct {
 	Delta  []int    `json:"delta" yaml:"delta"`
 }
 
+// FactorSeries holds the bus and pony factor of each bucket over a
+// trailing window of buckets ending with it. Periods is parallel to Months.
+type FactorSeries struct {
+	Months     []string        `json:"months" yaml:"months"`
+	Window     int             `json:"window" yaml:"window"`
+	BusFactor  []int           `json:"bus_factor" yaml:"busFactor"`
+	PonyFactor []int           `json:"pony_factor" yaml:"ponyFactor"`
+	Periods    []*FactorPeriod `json:"periods" yaml:"periods"`
+}
+
+// FactorPeriod lists the developers and entities behind half of the
+// activity in a window, and who joined or left each set versus the window
+// of the previous bucket.
+type FactorPeriod struct {
+	Developers       []*FactorMember `json:"developers" yaml:"developers"`
+	Entities         []*FactorMember `json:"entities" yaml:"entities"`
+	DevelopersJoined []string        `json:"developers_joined" yaml:"developersJoined"`
+	DevelopersLeft   []string        `json:"developers_left" yaml:"developersLeft"`
+	EntitiesJoined   []string        `json:"entities_joined" yaml:"entitiesJoined"`
+	EntitiesLeft     []string        `json:"entities_left" yaml:"entitiesLeft"`
+}
+
+// FactorMember is a developer or entity with its events and share (0-100)
+// of all events in a window.
+type FactorMember struct {
+	Name   string  `json:"name" yaml:"name"`
+	Events int     `json:"events" yaml:"events"`
+	Share  float64 `json:"share" yaml:"share"`
+}
+
+// Splits of the cohort retention table.
+const (
+	RetentionSplitEntity string = "entity"
+	RetentionSplitType   string = "type"
+
+	// RetentionGroupOther groups the entities past the largest ones.
+	RetentionGroupOther string = "other"
+)
+
+// RetentionSplits lists the valid cohort retention splits; empty does not
+// split.
+var RetentionSplits = []string{"", RetentionSplitEntity, RetentionSplitType}
+
+// CohortRetentionRow is a cohort of contributors by month of first
+// contribution, split by Group when requested. Active and Retention hold
+// the contributors active, and their percentage of Size, in each month
+// since joining, through the end of the range.
+type CohortRetentionRow struct {
+	Cohort    string    `json:"cohort" yaml:"cohort"`
+	Group     string    `json:"group" yaml:"group"`
+	Size      int       `json:"size" yaml:"size"`
+	Active    []int     `json:"active" yaml:"active"`
+	Retention []float64 `json:"retention" yaml:"retention"`
+}
+
+// CohortRetention is a cohort retention table. Split is empty, entity or
+// type (first contribution to an issue or a PR). Months is the number of
+// months since joining of the oldest cohort.
+type CohortRetention struct {
+	Split  string                `json:"split" yaml:"split"`
+	Months int                   `json:"months" yaml:"months"`
+	Rows   []*CohortRetentionRow `json:"rows" yaml:"rows"`
+}
+
+// ContributorRisk is a contributor whose recent activity fell well below
+// their personal baseline. Declines are percentages of the baseline rate
+// per 30 days; shares are percentages of all reviews and merges in the
+// baseline. Score is the largest decline weighted by the review and merge
+// shares, higher is more at risk.
+type ContributorRisk struct {
+	Username    string  `json:"username" yaml:"username"`
+	Entity      string  `json:"entity,omitempty" yaml:"entity,omitempty"`
+	Score       float64 `json:"score" yaml:"score"`
+	Baseline    float64 `json:"baseline" yaml:"baseline"`
+	Recent30    int     `json:"recent_30" yaml:"recent30"`
+	Recent60    int     `json:"recent_60" yaml:"recent60"`
+	Decline30   float64 `json:"decline_30" yaml:"decline30"`
+	Decline60   float64 `json:"decline_60" yaml:"decline60"`
+	ReviewShare float64 `json:"review_share" yaml:"reviewShare"`
+	MergeShare  float64 `json:"merge_share" yaml:"mergeShare"`
+	LastActive  string  `json:"last_active" yaml:"lastActive"`
+	Reason      string  `json:"reason" yaml:"reason"`
+}
+
+// ContributorsAtRisk lists the contributors at risk as of a date, most at
+// risk first, with the baseline period their recent activity is compared
+// with.
+type ContributorsAtRisk struct {
+	AsOf         string             `json:"as_of" yaml:"asOf"`
+	BaselineFrom string             `json:"baseline_from" yaml:"baselineFrom"`
+	BaselineTo   string             `json:"baseline_to" yaml:"baselineTo"`
+	Contributors []*ContributorRisk `json:"contributors" yaml:"contributors"`
+}
+
+// LifecycleSeries holds the contributors in each lifecycle stage per
+// bucket. Promoted counts contributors who moved up from Casual or Regular
+// since the previous bucket. Transitions counts stage changes between
+// consecutive buckets of the range, from stage to stage.
+type LifecycleSeries struct {
+	Months      []string                  `json:"months" yaml:"months"`
+	Thresholds  *LifecycleThresholds      `json:"thresholds" yaml:"thresholds"`
+	Casual      []int                     `json:"casual" yaml:"casual"`
+	Regular     []int                     `json:"regular" yaml:"regular"`
+	Core        []int                     `json:"core" yaml:"core"`
+	Lapsed      []int                     `json:"lapsed" yaml:"lapsed"`
+	Promoted    []int                     `json:"promoted" yaml:"promoted"`
+	Transitions map[string]map[string]int `json:"transitions" yaml:"transitions"`
+}
+
 type ForksAndActivitySeries struct {
 	Months []string `json:"months" yaml:"months"`
 	Forks  []int    `json:"forks" yaml:"forks"`
@@ -436,12 +1112,46 @@ type AffiliationImportResult struct {
 	CNCFDevs    int    `json:"cncf_devs,omitempty" yaml:"cncfDevs,omitempty"`
 	MappedDevs  int    `json:"mapped_devs,omitempty" yaml:"mappedDevs,omitempty"`
 	SkippedDevs int    `json:"skipped_devs,omitempty" yaml:"skippedDevs,omitempty"`
+	// CacheVersion identifies the cached affiliation file set (content hash).
+	CacheVersion   string `json:"cache_version,omitempty" yaml:"cacheVersion,omitempty"`
+	CacheCheckedAt string `json:"cache_checked_at,omitempty" yaml:"cacheCheckedAt,omitempty"`
+	Offline        bool   `json:"offline,omitempty" yaml:"offline,omitempty"`
 }
 
 // ---------------------------------------------------------------------------
 // Repo metric history types
 // ---------------------------------------------------------------------------
 
+// BacklogAging is the stock of currently open issues and PRs by age bucket
+// (see BacklogBuckets). Responded counts items that got a comment or review
+// from a maintainer, i.e. someone who approved a PR in the repo.
+type BacklogAging struct {
+	Buckets    []string        `json:"buckets" yaml:"buckets"`
+	Issues     []int           `json:"issues" yaml:"issues"`
+	PRs        []int           `json:"prs" yaml:"prs"`
+	Responded  []int           `json:"responded" yaml:"responded"`
+	NoResponse []int           `json:"no_response" yaml:"noResponse"`
+	Labels     []*BacklogLabel `json:"labels" yaml:"labels"`
+	Total      int             `json:"total" yaml:"total"`
+}
+
+// BacklogLabel is the number of open items carrying a label, by age bucket.
+type BacklogLabel struct {
+	Label  string `json:"label" yaml:"label"`
+	Counts []int  `json:"counts" yaml:"counts"`
+	Total  int    `json:"total" yaml:"total"`
+}
+
+// BacklogHistory is the daily size of the open backlog. Aged counts items
+// open for 90 days or more.
+type BacklogHistory struct {
+	Dates      []string `json:"dates" yaml:"dates"`
+	Issues     []int    `json:"issues" yaml:"issues"`
+	PRs        []int    `json:"prs" yaml:"prs"`
+	NoResponse []int    `json:"no_response" yaml:"noResponse"`
+	Aged       []int    `json:"aged" yaml:"aged"`
+}
+
 type RepoMetricHistory struct {
 	Org   string `json:"org"`
 	Repo  string `json:"repo"`
@@ -486,3 +1196,122 @@ type RepoOverview struct {
 	Archived     bool   `json:"archived"`
 	LastImport   string `json:"last_import"`
 }
+
+// ---------------------------------------------------------------------------
+// Planning types
+// ---------------------------------------------------------------------------
+
+// Milestone is a repository milestone with its latest issue counts.
+type Milestone struct {
+	Org          string  `json:"org" yaml:"org"`
+	Repo         string  `json:"repo" yaml:"repo"`
+	Number       int     `json:"number" yaml:"number"`
+	Title        string  `json:"title" yaml:"title"`
+	State        string  `json:"state" yaml:"state"`
+	URL          string  `json:"url" yaml:"url"`
+	DueOn        *string `json:"due_on,omitempty" yaml:"dueOn,omitempty"`
+	CreatedAt    string  `json:"created_at" yaml:"createdAt"`
+	ClosedAt     *string `json:"closed_at,omitempty" yaml:"closedAt,omitempty"`
+	OpenIssues   int     `json:"open_issues" yaml:"openIssues"`
+	ClosedIssues int     `json:"closed_issues" yaml:"closedIssues"`
+}
+
+// MilestoneItem is an issue or PR in a milestone.
+type MilestoneItem struct {
+	Milestone int     `json:"milestone" yaml:"milestone"`
+	Number    int     `json:"number" yaml:"number"`
+	ItemType  string  `json:"item_type" yaml:"itemType"`
+	CreatedAt string  `json:"created_at" yaml:"createdAt"`
+	ClosedAt  *string `json:"closed_at,omitempty" yaml:"closedAt,omitempty"`
+}
+
+// MilestoneProgress is a milestone with its completion and a projection
+// of when its open items will be closed at the current close rate.
+type MilestoneProgress struct {
+	Milestone
+	PercentComplete float64 `json:"percent_complete" yaml:"percentComplete"`
+	ClosedPerDay    float64 `json:"closed_per_day" yaml:"closedPerDay"`
+	ProjectedOn     string  `json:"projected_on,omitempty" yaml:"projectedOn,omitempty"`
+	Status          string  `json:"status" yaml:"status"`
+}
+
+// MilestoneBurnup is the daily count of items in a milestone (scope) and
+// of those closed (done). Ideal is the linear path from no closed items at
+// the start to the current scope on the due date.
+type MilestoneBurnup struct {
+	Org    string    `json:"org" yaml:"org"`
+	Repo   string    `json:"repo" yaml:"repo"`
+	Number int       `json:"number" yaml:"number"`
+	Title  string    `json:"title" yaml:"title"`
+	DueOn  string    `json:"due_on,omitempty" yaml:"dueOn,omitempty"`
+	Dates  []string  `json:"dates" yaml:"dates"`
+	Scope  []int     `json:"scope" yaml:"scope"`
+	Done   []int     `json:"done" yaml:"done"`
+	Ideal  []float64 `json:"ideal,omitempty" yaml:"ideal,omitempty"`
+}
+
+// ProjectItem is a repository item on a GitHub Projects (v2) board.
+type ProjectItem struct {
+	Project      int    `json:"project" yaml:"project"`
+	ProjectTitle string `json:"project_title" yaml:"projectTitle"`
+	ItemID       string `json:"item_id" yaml:"itemId"`
+	Number       *int   `json:"number,omitempty" yaml:"number,omitempty"`
+	ItemType     string `json:"item_type" yaml:"itemType"`
+	Status       string `json:"status" yaml:"status"`
+}
+
+// ProjectStatus counts the items of a project board by status. Items
+// without a status are counted under an empty status.
+type ProjectStatus struct {
+	Org      string   `json:"org" yaml:"org"`
+	Repo     string   `json:"repo" yaml:"repo"`
+	Project  int      `json:"project" yaml:"project"`
+	Title    string   `json:"title" yaml:"title"`
+	Statuses []string `json:"statuses" yaml:"statuses"`
+	Counts   []int    `json:"counts" yaml:"counts"`
+	Total    int      `json:"total" yaml:"total"`
+}
+
+// Anomaly metrics besides the event types, which are keyed by type.
+const (
+	AnomalyMetricReviewLatency string = "review_latency"
+	AnomalyMetricTimeToMerge   string = "time_to_merge"
+	AnomalyMetricStars         string = "stars"
+
+	AnomalyDirectionUp   string = "up"
+	AnomalyDirectionDown string = "down"
+)
+
+// AnomalyMetrics lists the series checked for anomalies: event counts by
+// type, median review latency in hours, median time to merge in days and
+// stars gained.
+var AnomalyMetrics = []string{
+	EventTypePR,
+	EventTypePRReview,
+	EventTypePRReviewComment,
+	EventTypeIssue,
+	EventTypeIssueComment,
+	EventTypeFork,
+	AnomalyMetricReviewLatency,
+	AnomalyMetricTimeToMerge,
+	AnomalyMetricStars,
+}
+
+// Anomaly is a bucket of a metric series that deviates from the previous
+// buckets by a robust z-score (median and median absolute deviation)
+// beyond the threshold. Org and Repo are empty for totals across orgs or
+// repos. Change is the percentage change versus the median.
+type Anomaly struct {
+	Org         string  `json:"org" yaml:"org"`
+	Repo        string  `json:"repo" yaml:"repo"`
+	Granularity string  `json:"granularity" yaml:"granularity"`
+	Metric      string  `json:"metric" yaml:"metric"`
+	Period      string  `json:"period" yaml:"period"`
+	Value       float64 `json:"value" yaml:"value"`
+	Median      float64 `json:"median" yaml:"median"`
+	MAD         float64 `json:"mad" yaml:"mad"`
+	Score       float64 `json:"score" yaml:"score"`
+	Change      float64 `json:"change" yaml:"change"`
+	Direction   string  `json:"direction" yaml:"direction"`
+	DetectedAt  string  `json:"detected_at" yaml:"detectedAt"`
+}
@@ -78,3 +78,84 @@ func Download(ctx context.Context, url string, filepath string) (retErr error) {
 
 	return nil
 }
+
+// CacheValidators holds the HTTP validators of a previously downloaded file.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty" yaml:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty" yaml:"lastModified,omitempty"`
+}
+
+// DownloadIfModified downloads url to filepath using a conditional request
+// built from v. It returns the validators of the response and whether new
+// content was written. When the server responds 304 Not Modified the file is
+// left untouched. Content is written to a temp file and renamed into place so
+// a failed download never corrupts an existing file.
+func DownloadIfModified(ctx context.Context, url string, filepath string, v *CacheValidators) (*CacheValidators, bool, error) {
+	c, err := GetHTTPClient()
+	if err != nil {
+		return nil, false, fmt.Errorf("error creating HTTP client: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("error creating HTTP Get request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", clientAgent)
+	if v != nil && v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v != nil && v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	resp, err := c.Do(req) //nolint:gosec,nolintlint // G704: URL from internal callers
+	if err != nil {
+		return nil, false, fmt.Errorf("error executing HTTP Get request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return v, false, nil
+	case http.StatusNotFound:
+		return nil, false, ErrURLNotFound
+	case http.StatusOK:
+	default:
+		return nil, false, fmt.Errorf("error downloading file (status: %d - %s): %s", resp.StatusCode, resp.Status, url)
+	}
+
+	tmp := filepath + ".tmp"
+	if err := writeFile(tmp, resp.Body); err != nil {
+		os.Remove(tmp)
+		return nil, false, err
+	}
+
+	if err := os.Rename(tmp, filepath); err != nil {
+		os.Remove(tmp)
+		return nil, false, fmt.Errorf("error moving downloaded file into place: %w", err)
+	}
+
+	return &CacheValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, true, nil
+}
+
+func writeFile(path string, r io.Reader) (retErr error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer func() {
+		if cerr := out.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("closing file: %w", cerr)
+		}
+	}()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return fmt.Errorf("error saving downloaded content to file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package net
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadIfModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "file.txt")
+
+	v, modified, err := DownloadIfModified(ctx, srv.URL+"/file", path, nil)
+	require.NoError(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, `"v1"`, v.ETag)
+	assert.NotEmpty(t, v.LastModified)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(b))
+
+	v2, modified, err := DownloadIfModified(ctx, srv.URL+"/file", path, v)
+	require.NoError(t, err)
+	assert.False(t, modified)
+	assert.Equal(t, v, v2)
+
+	_, _, err = DownloadIfModified(ctx, srv.URL+"/missing", path, nil)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}